@@ -0,0 +1,86 @@
+package gocms
+
+import "github.com/goliatone/go-notifications/pkg/domain"
+
+const (
+	// MetadataConfigurationKey holds the allowlisted snapshot configuration in
+	// TemplateInput.Metadata.
+	MetadataConfigurationKey = "configuration"
+	// MetadataBlockSettingsKey holds the allowlisted per-block settings in
+	// TemplateInput.Metadata, one map per payload block in block order.
+	MetadataBlockSettingsKey = "block_settings"
+)
+
+// blockSettingsFields are the nested maps searched for block settings after
+// the block itself.
+var blockSettingsFields = []string{"settings", "configuration"}
+
+// applyConfigurationAllowlist copies the allowlisted configuration and block
+// settings into metadata and narrows the payload configuration to the same
+// keys. It is a no-op without an allowlist.
+func applyConfigurationAllowlist(keys []string, metadata, payload domain.JSONMap) domain.JSONMap {
+	if len(keys) == 0 {
+		return metadata
+	}
+	set := func(key string, value any) {
+		if metadata == nil {
+			metadata = make(domain.JSONMap)
+		}
+		metadata[key] = value
+	}
+
+	configuration, _ := payload["configuration"].(map[string]any)
+	if selected := selectConfiguration(keys, configuration); len(selected) > 0 {
+		set(MetadataConfigurationKey, selected)
+		payload["configuration"] = cloneMap(selected)
+	} else {
+		delete(payload, "configuration")
+	}
+
+	blocks, _ := payload["blocks"].([]any)
+	settings := make([]any, len(blocks))
+	found := false
+	for i, raw := range blocks {
+		selected := map[string]any{}
+		if block, ok := raw.(map[string]any); ok {
+			selected = selectBlockSettings(keys, block)
+		}
+		found = found || len(selected) > 0
+		settings[i] = selected
+	}
+	if found {
+		set(MetadataBlockSettingsKey, settings)
+	}
+	return metadata
+}
+
+// selectConfiguration returns the values found at the given dotted paths,
+// keyed by path.
+func selectConfiguration(keys []string, source map[string]any) map[string]any {
+	out := make(map[string]any)
+	if len(source) == 0 {
+		return out
+	}
+	for _, key := range keys {
+		if value := findValue(source, key); value != nil {
+			out[key] = cloneValue(value)
+		}
+	}
+	return out
+}
+
+func selectBlockSettings(keys []string, block map[string]any) map[string]any {
+	out := selectConfiguration(keys, block)
+	for _, field := range blockSettingsFields {
+		nested, ok := block[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, value := range selectConfiguration(keys, nested) {
+			if _, exists := out[key]; !exists {
+				out[key] = value
+			}
+		}
+	}
+	return out
+}
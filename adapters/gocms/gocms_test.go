@@ -3,6 +3,7 @@ package gocms
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/goliatone/go-notifications/pkg/domain"
@@ -198,3 +199,286 @@ func TestTemplatesFromBlockSnapshotErrors(t *testing.T) {
 		t.Fatalf("expected ErrLocaleIdentifierMissing, got %v", err)
 	}
 }
+
+func TestTemplatesFromWidgetDocumentWalksRegions(t *testing.T) {
+	doc := WidgetDocument{
+		Configuration: map[string]any{"layout": "digest"},
+		Translations: []WidgetTranslation{
+			{
+				Locale:  "locale-en",
+				Content: map[string]any{"subject": "Digest for {{ Name }}"},
+			},
+			{
+				Locale:             "locale-es",
+				Content:            map[string]any{"subject": "Resumen"},
+				AttributeOverrides: map[string]any{"subject": "Resumen para {{ Name }}"},
+			},
+		},
+		Regions: []WidgetRegion{
+			{
+				Name: "main",
+				Slots: []WidgetSlot{
+					{
+						Name: "primary",
+						Widgets: []WidgetDocument{
+							{
+								Configuration: map[string]any{"color": "blue"},
+								Translations: []WidgetTranslation{
+									{Locale: "locale-en", Content: map[string]any{"type": "richtext", "body": "<p>Hello</p>"}},
+									{
+										Locale:             "locale-es",
+										Content:            map[string]any{"type": "richtext", "body": "<p>Hello</p>"},
+										AttributeOverrides: map[string]any{"body": "<p>Hola</p>"},
+									},
+								},
+								Regions: []WidgetRegion{
+									{
+										Name: "footer",
+										Slots: []WidgetSlot{
+											{
+												Name: "links",
+												Widgets: []WidgetDocument{
+													{
+														Translations: []WidgetTranslation{
+															{Locale: "locale-en", Content: map[string]any{"type": "button", "label": "Open"}},
+															{Locale: "locale-fr", Content: map[string]any{"type": "button", "label": "Ouvrir"}},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	spec := TemplateSpec{
+		Code:    "digest",
+		Channel: "email",
+		ResolveLocale: func(raw string) (string, error) {
+			return strings.TrimPrefix(raw, "locale-"), nil
+		},
+	}
+
+	inputs, err := TemplatesFromWidgetDocument(spec, doc)
+	if err != nil {
+		t.Fatalf("TemplatesFromWidgetDocument: %v", err)
+	}
+	if len(inputs) != 3 {
+		t.Fatalf("expected one template per locale, got %d", len(inputs))
+	}
+	byLocale := make(map[string]templates.TemplateInput, len(inputs))
+	for _, tpl := range inputs {
+		byLocale[tpl.Locale] = tpl
+	}
+
+	en := byLocale["en"]
+	blocks, _ := en.Source.Payload["blocks"].([]any)
+	if len(blocks) != 2 {
+		t.Fatalf("expected nested widgets flattened into blocks: %#v", en.Source.Payload["blocks"])
+	}
+	first, _ := blocks[0].(map[string]any)
+	if first["region"] != "main" || first["slot"] != "primary" || first["body"] != "<p>Hello</p>" {
+		t.Fatalf("unexpected first block: %#v", first)
+	}
+	config, _ := first["configuration"].(map[string]any)
+	if config["color"] != "blue" {
+		t.Fatalf("expected widget configuration on block: %#v", first)
+	}
+	second, _ := blocks[1].(map[string]any)
+	if second["region"] != "footer" || second["slot"] != "links" || second["label"] != "Open" {
+		t.Fatalf("unexpected nested block: %#v", second)
+	}
+
+	es := byLocale["es"]
+	if es.Source.Payload["subject"] != "Resumen para {{ Name }}" {
+		t.Fatalf("expected document override subject, got %#v", es.Source.Payload["subject"])
+	}
+	esBlocks, _ := es.Source.Payload["blocks"].([]any)
+	if len(esBlocks) != 1 {
+		t.Fatalf("expected widgets without es translation to be skipped: %#v", esBlocks)
+	}
+	if block, _ := esBlocks[0].(map[string]any); block["body"] != "<p>Hola</p>" {
+		t.Fatalf("expected widget override body, got %#v", block)
+	}
+
+	fr, ok := byLocale["fr"]
+	if !ok {
+		t.Fatalf("expected locale found only in a nested widget")
+	}
+	if _, ok := fr.Source.Payload["subject"]; ok {
+		t.Fatalf("expected no document subject for fr: %#v", fr.Source.Payload)
+	}
+	frBlocks, _ := fr.Source.Payload["blocks"].([]any)
+	if len(frBlocks) != 1 {
+		t.Fatalf("expected fr nested block: %#v", fr.Source.Payload["blocks"])
+	}
+}
+
+func TestTemplatesFromWidgetDocumentErrors(t *testing.T) {
+	_, err := TemplatesFromWidgetDocument(TemplateSpec{}, WidgetDocument{})
+	if !errors.Is(err, ErrCodeRequired) {
+		t.Fatalf("expected ErrCodeRequired, got %v", err)
+	}
+
+	_, err = TemplatesFromWidgetDocument(TemplateSpec{Code: "code", Channel: "email"}, WidgetDocument{})
+	if !errors.Is(err, ErrNoTranslations) {
+		t.Fatalf("expected ErrNoTranslations, got %v", err)
+	}
+
+	_, err = TemplatesFromWidgetDocument(
+		TemplateSpec{Code: "code", Channel: "email"},
+		WidgetDocument{
+			Regions: []WidgetRegion{{
+				Name: "main",
+				Slots: []WidgetSlot{{Widgets: []WidgetDocument{{
+					Translations: []WidgetTranslation{{Locale: " ", Content: map[string]any{"body": "x"}}},
+				}}}},
+			}},
+		},
+	)
+	if !errors.Is(err, ErrLocaleIdentifierMissing) {
+		t.Fatalf("expected ErrLocaleIdentifierMissing, got %v", err)
+	}
+}
+
+func TestTemplatesFromBlockSnapshotRendersTypedBlocks(t *testing.T) {
+	snapshot := BlockVersionSnapshot{
+		Translations: []BlockTranslationSnapshot{
+			{
+				Locale: "en",
+				Content: map[string]any{
+					"subject": "Your order",
+					"blocks": []any{
+						map[string]any{"type": "richtext", "body": "<p>Hi {{ Name }} &amp; team</p>"},
+						map[string]any{"type": "button", "label": "Track", "url": "{{ TrackURL }}?src=mail&x=1"},
+						map[string]any{"type": "image", "src": "https://cdn.example.com/box.png", "alt": "Box"},
+						map[string]any{"type": "divider"},
+						map[string]any{"type": "coupon", "code": "SAVE10"},
+					},
+				},
+			},
+		},
+	}
+	renderers := BlockRenderers{
+		"Coupon": func(channel string, block map[string]any) (string, error) {
+			return channel + ":" + block["code"].(string), nil
+		},
+	}
+
+	email, err := TemplatesFromBlockSnapshot(TemplateSpec{Code: "order", Channel: "email", BlockRenderers: renderers}, snapshot)
+	if err != nil {
+		t.Fatalf("email: %v", err)
+	}
+	wantEmail := "<p>Hi {{ Name }} &amp; team</p>\n" +
+		`<a href="{{ TrackURL }}?src=mail&amp;x=1">Track</a>` + "\n" +
+		`<img src="https://cdn.example.com/box.png" alt="Box">` + "\n" +
+		"<hr>\n" +
+		"email:SAVE10"
+	if got := email[0].Source.Payload["body"]; got != wantEmail {
+		t.Fatalf("unexpected email body:\n%v\nwant:\n%s", got, wantEmail)
+	}
+
+	sms, err := TemplatesFromBlockSnapshot(TemplateSpec{Code: "order", Channel: "sms", BlockRenderers: renderers}, snapshot)
+	if err != nil {
+		t.Fatalf("sms: %v", err)
+	}
+	wantSMS := "Hi {{ Name }} & team\n\n" +
+		"Track: {{ TrackURL }}?src=mail&x=1\n\n" +
+		"Box: https://cdn.example.com/box.png\n\n" +
+		"---\n\n" +
+		"sms:SAVE10"
+	if got := sms[0].Source.Payload["body"]; got != wantSMS {
+		t.Fatalf("unexpected sms body:\n%v\nwant:\n%s", got, wantSMS)
+	}
+
+	snapshot.Translations[0].AttributeOverrides = map[string]any{"body": "explicit"}
+	explicit, err := TemplatesFromBlockSnapshot(TemplateSpec{Code: "order", Channel: "email"}, snapshot)
+	if err != nil {
+		t.Fatalf("explicit: %v", err)
+	}
+	if got := explicit[0].Source.Payload["body"]; got != "explicit" {
+		t.Fatalf("expected explicit body to win, got %v", got)
+	}
+
+	failing := BlockRenderers{"coupon": func(string, map[string]any) (string, error) {
+		return "", errors.New("boom")
+	}}
+	snapshot.Translations[0].AttributeOverrides = nil
+	if _, err := TemplatesFromBlockSnapshot(TemplateSpec{Code: "order", Channel: "email", BlockRenderers: failing}, snapshot); err == nil {
+		t.Fatalf("expected renderer error")
+	}
+}
+
+func TestTemplatesFromBlockSnapshotConfigurationAllowlist(t *testing.T) {
+	snapshot := BlockVersionSnapshot{
+		Configuration: map[string]any{
+			"layout": "hero",
+			"secret": "internal",
+			"theme":  map[string]any{"color": "#0055ff", "font": "serif"},
+		},
+		Translations: []BlockTranslationSnapshot{
+			{
+				Locale: "en",
+				Content: map[string]any{
+					"subject": "Hi",
+					"blocks": []any{
+						map[string]any{"type": "richtext", "body": "<p>Hi</p>"},
+						map[string]any{"type": "button", "label": "Go", "url": "https://example.com", "color": "red", "settings": map[string]any{"alignment": "center"}},
+					},
+				},
+			},
+		},
+	}
+	spec := TemplateSpec{
+		Code:              "hero",
+		Channel:           "email",
+		Metadata:          domain.JSONMap{"source": "cms"},
+		ConfigurationKeys: []string{"layout", " theme.color ", "color", "alignment"},
+	}
+
+	inputs, err := TemplatesFromBlockSnapshot(spec, snapshot)
+	if err != nil {
+		t.Fatalf("TemplatesFromBlockSnapshot: %v", err)
+	}
+	input := inputs[0]
+	if input.Metadata["source"] != "cms" {
+		t.Fatalf("expected spec metadata preserved: %#v", input.Metadata)
+	}
+	config, _ := input.Metadata[MetadataConfigurationKey].(map[string]any)
+	if len(config) != 2 || config["layout"] != "hero" || config["theme.color"] != "#0055ff" {
+		t.Fatalf("unexpected allowlisted configuration: %#v", config)
+	}
+	payloadConfig, _ := input.Source.Payload["configuration"].(map[string]any)
+	if _, leaked := payloadConfig["secret"]; leaked || payloadConfig["layout"] != "hero" {
+		t.Fatalf("expected payload configuration narrowed to allowlist: %#v", payloadConfig)
+	}
+	settings, _ := input.Metadata[MetadataBlockSettingsKey].([]any)
+	if len(settings) != 2 {
+		t.Fatalf("expected settings per block: %#v", input.Metadata[MetadataBlockSettingsKey])
+	}
+	if first, _ := settings[0].(map[string]any); len(first) != 0 {
+		t.Fatalf("expected no settings for richtext block: %#v", first)
+	}
+	button, _ := settings[1].(map[string]any)
+	if button["color"] != "red" || button["alignment"] != "center" {
+		t.Fatalf("unexpected button settings: %#v", button)
+	}
+
+	spec.ConfigurationKeys = nil
+	inputs, err = TemplatesFromBlockSnapshot(spec, snapshot)
+	if err != nil {
+		t.Fatalf("TemplatesFromBlockSnapshot without allowlist: %v", err)
+	}
+	if _, ok := inputs[0].Metadata[MetadataConfigurationKey]; ok {
+		t.Fatalf("expected no configuration metadata without allowlist: %#v", inputs[0].Metadata)
+	}
+	full, _ := inputs[0].Source.Payload["configuration"].(map[string]any)
+	if full["secret"] != "internal" {
+		t.Fatalf("expected full configuration without allowlist: %#v", full)
+	}
+}
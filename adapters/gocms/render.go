@@ -0,0 +1,151 @@
+package gocms
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// BlockRenderer renders a single content block for the target channel. An
+// empty result leaves the block out of the body.
+type BlockRenderer func(channel string, block map[string]any) (string, error)
+
+// BlockRenderers maps block "type" values to renderers. Blocks without a
+// registered type render their "body" (or "text") field like richtext.
+type BlockRenderers map[string]BlockRenderer
+
+// DefaultBlockRenderers returns the built-in renderers for richtext, button,
+// image, and divider blocks. Email output is HTML; other channels get plain
+// text with links spelled out.
+func DefaultBlockRenderers() BlockRenderers {
+	return BlockRenderers{
+		"richtext": renderTextBlock,
+		"button":   renderButtonBlock,
+		"image":    renderImageBlock,
+		"divider":  renderDividerBlock,
+	}
+}
+
+func (r BlockRenderers) withDefaults() BlockRenderers {
+	out := DefaultBlockRenderers()
+	for name, renderer := range r {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || renderer == nil {
+			continue
+		}
+		out[name] = renderer
+	}
+	return out
+}
+
+// renderBlocksBody renders blocks in order and joins the non-empty results.
+func renderBlocksBody(renderers BlockRenderers, channel string, blocks []any) (string, error) {
+	parts := make([]string, 0, len(blocks))
+	for i, raw := range blocks {
+		block, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		kind := strings.ToLower(strings.TrimSpace(firstString(block, "type")))
+		renderer := renderers[kind]
+		if renderer == nil {
+			renderer = renderTextBlock
+		}
+		out, err := renderer(channel, block)
+		if err != nil {
+			return "", fmt.Errorf("gocms: render block %d (%s): %w", i, kind, err)
+		}
+		if out = strings.TrimSpace(out); out != "" {
+			parts = append(parts, out)
+		}
+	}
+	if isHTMLChannel(channel) {
+		return strings.Join(parts, "\n"), nil
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func isHTMLChannel(channel string) bool {
+	return strings.EqualFold(strings.TrimSpace(channel), "email")
+}
+
+func renderTextBlock(channel string, block map[string]any) (string, error) {
+	body := firstNonEmpty(block, "body", "text")
+	if isHTMLChannel(channel) {
+		return body, nil
+	}
+	return stripTags(body), nil
+}
+
+func renderButtonBlock(channel string, block map[string]any) (string, error) {
+	label := firstNonEmpty(block, "label", "text")
+	url := firstNonEmpty(block, "url", "href")
+	if url == "" {
+		return label, nil
+	}
+	if isHTMLChannel(channel) {
+		if label == "" {
+			label = url
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, escapeOutsideTags(url), escapeOutsideTags(label)), nil
+	}
+	if label == "" {
+		return url, nil
+	}
+	return label + ": " + url, nil
+}
+
+func renderImageBlock(channel string, block map[string]any) (string, error) {
+	src := firstNonEmpty(block, "src", "url")
+	alt := firstNonEmpty(block, "alt", "caption")
+	if src == "" {
+		return alt, nil
+	}
+	if isHTMLChannel(channel) {
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, escapeOutsideTags(src), escapeOutsideTags(alt)), nil
+	}
+	if alt == "" {
+		return src, nil
+	}
+	return alt + ": " + src, nil
+}
+
+func renderDividerBlock(channel string, _ map[string]any) (string, error) {
+	if isHTMLChannel(channel) {
+		return "<hr>", nil
+	}
+	return "---", nil
+}
+
+func firstNonEmpty(block map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(firstString(block, key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+var (
+	htmlTagPattern      = regexp.MustCompile(`<[^>]*>`)
+	templateTagsPattern = regexp.MustCompile(`\{\{.*?\}\}|\{%.*?%\}`)
+)
+
+func stripTags(value string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(value, "")))
+}
+
+// escapeOutsideTags HTML-escapes value while leaving template expressions
+// untouched, so {{ t(locale, "key") }} still renders later.
+func escapeOutsideTags(value string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range templateTagsPattern.FindAllStringIndex(value, -1) {
+		b.WriteString(html.EscapeString(value[last:loc[0]]))
+		b.WriteString(value[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(value[last:]))
+	return b.String()
+}
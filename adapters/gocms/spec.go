@@ -70,6 +70,16 @@ type TemplateSpec struct {
 	Metadata        domain.JSONMap
 	Fields          FieldMapping
 	ResolveLocale   LocaleResolver
+	// BlockRenderers registers renderers for custom block types, or replaces
+	// the defaults (see DefaultBlockRenderers). They build the body when the
+	// payload has blocks but no body.
+	BlockRenderers BlockRenderers
+	// ConfigurationKeys allowlists configuration keys (dotted paths) copied
+	// into TemplateInput.Metadata, both from the snapshot configuration and
+	// from each block's settings. When set, Source.Payload's configuration is
+	// narrowed to the same keys; when empty, it is copied whole and metadata
+	// is left alone.
+	ConfigurationKeys []string
 }
 
 func (s TemplateSpec) normalized() (TemplateSpec, error) {
@@ -85,6 +95,16 @@ func (s TemplateSpec) normalized() (TemplateSpec, error) {
 		s.Format = "text/html"
 	}
 	s.Fields = s.Fields.withDefaults()
+	s.BlockRenderers = s.BlockRenderers.withDefaults()
+	if len(s.ConfigurationKeys) > 0 {
+		keys := make([]string, 0, len(s.ConfigurationKeys))
+		for _, key := range s.ConfigurationKeys {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		s.ConfigurationKeys = keys
+	}
 	if s.ResolveLocale == nil {
 		s.ResolveLocale = func(raw string) (string, error) {
 			raw = strings.TrimSpace(raw)
@@ -103,6 +123,9 @@ type translationPayload struct {
 	overrides     map[string]any
 	configuration map[string]any
 	metadata      map[string]any
+	// children are extra blocks appended after the translation's own blocks
+	// (nested widgets).
+	children []any
 }
 
 func buildTemplateInput(spec TemplateSpec, payload translationPayload) (templates.TemplateInput, error) {
@@ -115,6 +138,17 @@ func buildTemplateInput(spec TemplateSpec, payload translationPayload) (template
 	}
 
 	srcPayload := newSourcePayload(payload, spec.Fields)
+	if blocks, ok := srcPayload["blocks"].([]any); ok && srcPayload["body"] == nil {
+		body, err := renderBlocksBody(spec.BlockRenderers, spec.Channel, blocks)
+		if err != nil {
+			return templates.TemplateInput{}, err
+		}
+		if body != "" {
+			srcPayload["body"] = body
+		}
+	}
+
+	metadata := applyConfigurationAllowlist(spec.ConfigurationKeys, cloneJSONMap(spec.Metadata), srcPayload)
 
 	return templates.TemplateInput{
 		Code:        spec.Code,
@@ -123,7 +157,7 @@ func buildTemplateInput(spec TemplateSpec, payload translationPayload) (template
 		Format:      spec.Format,
 		Description: spec.Description,
 		Schema:      spec.Schema,
-		Metadata:    cloneJSONMap(spec.Metadata),
+		Metadata:    metadata,
 		Source: domain.TemplateSource{
 			Type:      TemplateSourceType,
 			Reference: strings.TrimSpace(spec.SourceReference),
@@ -183,11 +217,11 @@ func newSourcePayload(payload translationPayload, fields FieldMapping) domain.JS
 		setString(preheader, "preheader")
 	}
 
-	if blocks := firstBlocks(payload.overrides, fields.Blocks); len(blocks) > 0 {
-		setBlocks(blocks)
-	} else if blocks := firstBlocks(payload.content, fields.Blocks); len(blocks) > 0 {
-		setBlocks(blocks)
+	blocks := firstBlocks(payload.overrides, fields.Blocks)
+	if len(blocks) == 0 {
+		blocks = firstBlocks(payload.content, fields.Blocks)
 	}
+	setBlocks(append(blocks, payload.children...))
 
 	addMap("content", payload.content)
 	addMap("attribute_overrides", payload.overrides)
@@ -2,6 +2,7 @@ package gocms
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/goliatone/go-notifications/pkg/templates"
 )
@@ -10,36 +11,176 @@ import (
 type WidgetDocument struct {
 	Configuration map[string]any      `json:"configuration"`
 	Translations  []WidgetTranslation `json:"translations"`
+	Regions       []WidgetRegion      `json:"regions"`
 	Metadata      map[string]any      `json:"metadata"`
 }
 
 // WidgetTranslation stores the locale + payload data for a widget.
 type WidgetTranslation struct {
-	Locale  string         `json:"locale"`
-	Content map[string]any `json:"content"`
+	Locale             string         `json:"locale"`
+	Content            map[string]any `json:"content"`
+	AttributeOverrides map[string]any `json:"attribute_overrides"`
+}
+
+// WidgetRegion is a named area of a widget document (e.g. "header", "main").
+type WidgetRegion struct {
+	Name  string       `json:"name"`
+	Slots []WidgetSlot `json:"slots"`
+}
+
+// WidgetSlot holds the widgets placed in one position of a region, in order.
+type WidgetSlot struct {
+	Name    string           `json:"name"`
+	Widgets []WidgetDocument `json:"widgets"`
 }
 
 // TemplatesFromWidgetDocument converts widget translations into template inputs.
+// Locales are collected from the document and every nested widget, resolved
+// through spec.ResolveLocale, and each distinct locale yields one input.
+// Nested widgets are flattened depth-first into the payload blocks after the
+// document's own blocks; each block is the widget content with its attribute
+// overrides applied, tagged with "region" and "slot". Widgets without a
+// translation for a locale are left out of that locale's blocks.
 func TemplatesFromWidgetDocument(spec TemplateSpec, document WidgetDocument) ([]templates.TemplateInput, error) {
 	spec, err := spec.normalized()
 	if err != nil {
 		return nil, err
 	}
-	if len(document.Translations) == 0 {
+	locales, err := widgetLocales(spec, document, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(locales) == 0 {
 		return nil, ErrNoTranslations
 	}
-	inputs := make([]templates.TemplateInput, 0, len(document.Translations))
-	for _, translation := range document.Translations {
+	inputs := make([]templates.TemplateInput, 0, len(locales))
+	for _, locale := range locales {
+		translation, _, err := widgetTranslation(spec, document, locale.code)
+		if err != nil {
+			return nil, err
+		}
+		children, err := widgetBlocks(spec, document.Regions, locale.code)
+		if err != nil {
+			return nil, err
+		}
 		input, err := buildTemplateInput(spec, translationPayload{
-			locale:        translation.Locale,
+			locale:        locale.raw,
 			content:       translation.Content,
+			overrides:     translation.AttributeOverrides,
 			configuration: document.Configuration,
 			metadata:      document.Metadata,
+			children:      children,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("gocms: build template for widget locale %q: %w", translation.Locale, err)
+			return nil, fmt.Errorf("gocms: build template for widget locale %q: %w", locale.raw, err)
 		}
 		inputs = append(inputs, input)
 	}
 	return inputs, nil
 }
+
+type widgetLocale struct {
+	raw  string
+	code string
+}
+
+func resolveWidgetLocale(spec TemplateSpec, raw string) (string, error) {
+	code, err := spec.ResolveLocale(raw)
+	if err == nil && strings.TrimSpace(code) == "" {
+		err = ErrLocaleIdentifierMissing
+	}
+	if err != nil {
+		return "", fmt.Errorf("gocms: build template for widget locale %q: resolve locale: %w", raw, err)
+	}
+	return code, nil
+}
+
+// widgetLocales walks the document depth-first and returns each resolved
+// locale once, in the order it is first seen.
+func widgetLocales(spec TemplateSpec, document WidgetDocument, seen []widgetLocale) ([]widgetLocale, error) {
+	for _, translation := range document.Translations {
+		code, err := resolveWidgetLocale(spec, translation.Locale)
+		if err != nil {
+			return nil, err
+		}
+		known := false
+		for _, existing := range seen {
+			if strings.EqualFold(existing.code, code) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			seen = append(seen, widgetLocale{raw: translation.Locale, code: code})
+		}
+	}
+	for _, region := range document.Regions {
+		for _, slot := range region.Slots {
+			for _, widget := range slot.Widgets {
+				var err error
+				if seen, err = widgetLocales(spec, widget, seen); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return seen, nil
+}
+
+// widgetTranslation returns the document translation whose resolved locale
+// matches code.
+func widgetTranslation(spec TemplateSpec, document WidgetDocument, code string) (WidgetTranslation, bool, error) {
+	for _, translation := range document.Translations {
+		resolved, err := resolveWidgetLocale(spec, translation.Locale)
+		if err != nil {
+			return WidgetTranslation{}, false, err
+		}
+		if strings.EqualFold(resolved, code) {
+			return translation, true, nil
+		}
+	}
+	return WidgetTranslation{}, false, nil
+}
+
+func widgetBlocks(spec TemplateSpec, regions []WidgetRegion, code string) ([]any, error) {
+	var blocks []any
+	for _, region := range regions {
+		for _, slot := range region.Slots {
+			for _, widget := range slot.Widgets {
+				translation, ok, err := widgetTranslation(spec, widget, code)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					blocks = append(blocks, widgetBlock(region.Name, slot.Name, widget, translation))
+				}
+				nested, err := widgetBlocks(spec, widget.Regions, code)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, nested...)
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func widgetBlock(region, slot string, widget WidgetDocument, translation WidgetTranslation) map[string]any {
+	block := cloneMap(translation.Content)
+	if block == nil {
+		block = make(map[string]any)
+	}
+	for key, value := range translation.AttributeOverrides {
+		block[key] = cloneValue(value)
+	}
+	if region = strings.TrimSpace(region); region != "" {
+		block["region"] = region
+	}
+	if slot = strings.TrimSpace(slot); slot != "" {
+		block["slot"] = slot
+	}
+	if len(widget.Configuration) > 0 {
+		block["configuration"] = cloneMap(widget.Configuration)
+	}
+	return block
+}
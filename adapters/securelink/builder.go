@@ -21,6 +21,7 @@ type Builder struct {
 	actionRoute    RouteFunc
 	manifestRoute  RouteFunc
 	payloadBuilder PayloadBuilder
+	singleUse      func(req links.LinkRequest) bool
 	now            func() time.Time
 }
 
@@ -78,6 +79,22 @@ func WithPayloadBuilder(fn PayloadBuilder) Option {
 	}
 }
 
+// WithSingleUse marks every generated link as single-use, so the first
+// click consumes it.
+func WithSingleUse(enabled bool) Option {
+	return WithSingleUseFunc(func(links.LinkRequest) bool { return enabled })
+}
+
+// WithSingleUseFunc selects single-use semantics per request, e.g. only for
+// password reset definitions.
+func WithSingleUseFunc(fn func(req links.LinkRequest) bool) Option {
+	return func(builder *Builder) {
+		if fn != nil {
+			builder.singleUse = fn
+		}
+	}
+}
+
 // WithClock overrides the clock used for expiration timestamps.
 func WithClock(now func() time.Time) Option {
 	return func(builder *Builder) {
@@ -109,30 +126,43 @@ func (b *Builder) Build(ctx context.Context, req links.LinkRequest) (links.Resol
 		expiresAt = now().Add(ttl)
 	}
 
+	singleUse := b.singleUse != nil && b.singleUse(req)
+	payloadFor := func(key string) links.SecureLinkPayload {
+		payload := payloadBuilder(req, key)
+		if singleUse {
+			if payload == nil {
+				payload = links.SecureLinkPayload{}
+			}
+			payload["single_use"] = true
+		}
+		return payload
+	}
+
 	resolved := links.ResolvedLinks{}
 	records := make([]links.LinkRecord, 0, 2)
 
 	if route := routeFor(b.actionRoute, req); route != "" {
-		url, err := b.manager.Generate(route, payloadBuilder(req, links.ResolvedURLActionKey))
+		url, err := b.manager.Generate(route, payloadFor(links.ResolvedURLActionKey))
 		if err != nil {
 			return links.ResolvedLinks{}, err
 		}
 		resolved.ActionURL = url
 		resolved.URL = url
-		records = append(records, buildRecord(req, url, links.ResolvedURLActionKey, route, expiresAt))
+		records = append(records, buildRecord(req, url, links.ResolvedURLActionKey, route, expiresAt, singleUse))
 	}
 
 	if route := routeFor(b.manifestRoute, req); route != "" {
-		url, err := b.manager.Generate(route, payloadBuilder(req, links.ResolvedURLManifestKey))
+		url, err := b.manager.Generate(route, payloadFor(links.ResolvedURLManifestKey))
 		if err != nil {
 			return links.ResolvedLinks{}, err
 		}
 		resolved.ManifestURL = url
-		records = append(records, buildRecord(req, url, links.ResolvedURLManifestKey, route, expiresAt))
+		records = append(records, buildRecord(req, url, links.ResolvedURLManifestKey, route, expiresAt, singleUse))
 	}
 
 	if len(records) > 0 {
 		resolved.Records = records
+		resolved.ExpiresAt = expiresAt
 	}
 	return resolved, nil
 }
@@ -158,7 +188,7 @@ func defaultPayloadBuilder(req links.LinkRequest, key string) links.SecureLinkPa
 	}
 }
 
-func buildRecord(req links.LinkRequest, url, key, route string, expiresAt time.Time) links.LinkRecord {
+func buildRecord(req links.LinkRequest, url, key, route string, expiresAt time.Time, singleUse bool) links.LinkRecord {
 	metadata := map[string]any{
 		"link_key": key,
 		"route":    route,
@@ -170,6 +200,7 @@ func buildRecord(req links.LinkRequest, url, key, route string, expiresAt time.T
 		MessageID:  req.MessageID,
 		Definition: req.Definition,
 		ExpiresAt:  expiresAt,
+		SingleUse:  singleUse,
 		Metadata:   metadata,
 	}
 }
@@ -50,6 +50,9 @@ func TestBuilderBuildActionOnlyWithAdapter(t *testing.T) {
 	if !record.ExpiresAt.Equal(expectedExpiresAt) {
 		t.Fatalf("expected ExpiresAt %v, got %v", expectedExpiresAt, record.ExpiresAt)
 	}
+	if !resolved.ExpiresAt.Equal(expectedExpiresAt) {
+		t.Fatalf("expected resolved ExpiresAt %v, got %v", expectedExpiresAt, resolved.ExpiresAt)
+	}
 
 	token := extractTokenFromURL(t, resolved.ActionURL, "token")
 	payload, err := manager.Validate(token)
@@ -107,6 +110,38 @@ func TestBuilderBuildActionAndManifestWithAdapter(t *testing.T) {
 	}
 }
 
+func TestBuilderSingleUseMarksRecordsAndPayload(t *testing.T) {
+	manager := WrapManager(newTestURLKitManager(t))
+	builder := NewBuilder(
+		manager,
+		WithManifestRoute(""),
+		WithSingleUseFunc(func(req links.LinkRequest) bool { return req.Definition == testLinkRequest().Definition }),
+	)
+
+	resolved, err := builder.Build(context.Background(), testLinkRequest())
+	if err != nil {
+		t.Fatalf("expected resolved links, got error: %v", err)
+	}
+	if len(resolved.Records) != 1 || !resolved.Records[0].SingleUse {
+		t.Fatalf("expected single-use record, got %+v", resolved.Records)
+	}
+	payload, err := manager.Validate(extractTokenFromURL(t, resolved.ActionURL, "token"))
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+	if payload["single_use"] != true {
+		t.Fatalf("expected single_use in payload, got %+v", payload)
+	}
+
+	plain, err := NewBuilder(manager, WithManifestRoute("")).Build(context.Background(), testLinkRequest())
+	if err != nil {
+		t.Fatalf("expected resolved links, got error: %v", err)
+	}
+	if plain.Records[0].SingleUse {
+		t.Fatal("expected links to be reusable by default")
+	}
+}
+
 func TestBuilderSkipsEmptyRoutes(t *testing.T) {
 	manager := WrapManager(newTestURLKitManager(t))
 	builder := NewBuilder(
@@ -4,26 +4,37 @@ import (
 	"context"
 	"maps"
 	"sync"
+	"time"
 
 	"github.com/goliatone/go-notifications/pkg/links"
 )
 
-// MemoryStore keeps link records in memory for demos and tests.
+// MemoryStore keeps link records in memory for demos and tests. Records and
+// clicks are keyed by tracking token, so two deliveries of the same URL are
+// tracked and consumed independently.
 type MemoryStore struct {
 	mu      sync.Mutex
-	records map[string]links.LinkRecord
+	records map[string]links.LinkRecord // tracking token -> record
+	urls    map[string]string           // URL -> latest tracking token
+	clicks  map[string]int              // tracking token -> click count
 }
 
-var _ links.LinkStore = (*MemoryStore)(nil)
+var (
+	_ links.LinkStore  = (*MemoryStore)(nil)
+	_ links.LinkLookup = (*MemoryStore)(nil)
+)
 
 // NewMemoryStore creates an in-memory LinkStore.
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
 		records: make(map[string]links.LinkRecord),
+		urls:    make(map[string]string),
+		clicks:  make(map[string]int),
 	}
 }
 
-// Save stores records by URL (idempotent).
+// Save stores records by tracking token (idempotent). Records without a token
+// or URL cannot be clicked and are skipped.
 func (s *MemoryStore) Save(ctx context.Context, records []links.LinkRecord) error {
 	if s == nil {
 		return nil
@@ -33,16 +44,127 @@ func (s *MemoryStore) Save(ctx context.Context, records []links.LinkRecord) erro
 	if s.records == nil {
 		s.records = make(map[string]links.LinkRecord)
 	}
+	if s.urls == nil {
+		s.urls = make(map[string]string)
+	}
 	for _, record := range records {
-		if record.URL == "" {
+		if record.Token == "" || record.URL == "" {
 			continue
 		}
 		record.Metadata = cloneMetadata(record.Metadata)
-		s.records[record.URL] = record
+		if existing, ok := s.records[record.Token]; ok && record.ConsumedAt.IsZero() {
+			record.ConsumedAt = existing.ConsumedAt
+		}
+		s.records[record.Token] = record
+		s.urls[record.URL] = record.Token
 	}
 	return nil
 }
 
+// Lookup returns the link saved under token without counting a click.
+func (s *MemoryStore) Lookup(ctx context.Context, token string) (links.LinkRecord, error) {
+	if s == nil || token == "" {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[token]
+	if !ok {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	record.Metadata = cloneMetadata(record.Metadata)
+	return record, nil
+}
+
+// LookupURL returns the most recently saved link for url.
+func (s *MemoryStore) LookupURL(ctx context.Context, url string) (links.LinkRecord, error) {
+	if s == nil || url == "" {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	s.mu.Lock()
+	token, ok := s.urls[url]
+	s.mu.Unlock()
+	if !ok {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	return s.Lookup(ctx, token)
+}
+
+// RecordClick increments the click counter of the link saved under token.
+// Click metadata is not retained.
+func (s *MemoryStore) RecordClick(ctx context.Context, token string, meta map[string]any) (links.LinkRecord, error) {
+	if s == nil || token == "" {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[token]
+	if !ok {
+		return links.LinkRecord{}, links.ErrLinkNotFound
+	}
+	if s.clicks == nil {
+		s.clicks = make(map[string]int)
+	}
+	s.clicks[token]++
+	record.Metadata = cloneMetadata(record.Metadata)
+	return record, nil
+}
+
+// Consume marks the link saved under token as used, reporting false when it
+// was already consumed.
+func (s *MemoryStore) Consume(ctx context.Context, token string) (bool, error) {
+	if s == nil || token == "" {
+		return false, links.ErrLinkNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[token]
+	if !ok {
+		return false, links.ErrLinkNotFound
+	}
+	if !record.ConsumedAt.IsZero() {
+		return false, nil
+	}
+	record.ConsumedAt = time.Now().UTC()
+	s.records[token] = record
+	return true, nil
+}
+
+// ClickCount returns the total clicks recorded across the links of a message.
+func (s *MemoryStore) ClickCount(messageID string) int {
+	total := 0
+	for _, count := range s.Clicks(messageID) {
+		total += count
+	}
+	return total
+}
+
+// Clicks returns the click counts of a message keyed by link_key (falling
+// back to the URL when the record carries no link_key). Links without clicks
+// are reported with a zero count.
+func (s *MemoryStore) Clicks(messageID string) map[string]int {
+	if s == nil || messageID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out map[string]int
+	for token, record := range s.records {
+		if record.MessageID != messageID {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]int)
+		}
+		key, _ := record.Metadata["link_key"].(string)
+		if key == "" {
+			key = record.URL
+		}
+		out[key] += s.clicks[token]
+	}
+	return out
+}
+
 // Records returns a snapshot of stored records.
 func (s *MemoryStore) Records() []links.LinkRecord {
 	if s == nil {
@@ -0,0 +1,160 @@
+package securelink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/links"
+)
+
+func TestMemoryStoreRecordClickCountsPerMessage(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	err := store.Save(ctx, []links.LinkRecord{
+		{Token: "tok-action", URL: "https://example.com/action", MessageID: "msg-1", Metadata: map[string]any{"link_key": links.ResolvedURLActionKey}},
+		{Token: "tok-manifest", URL: "https://example.com/manifest", MessageID: "msg-1", Metadata: map[string]any{"link_key": links.ResolvedURLManifestKey}},
+		{Token: "tok-other", URL: "https://example.com/other", MessageID: "msg-2"},
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	for range 2 {
+		record, err := store.RecordClick(ctx, "tok-action", map[string]any{"user_agent": "test"})
+		if err != nil {
+			t.Fatalf("record click: %v", err)
+		}
+		if record.URL != "https://example.com/action" {
+			t.Fatalf("expected action record, got %+v", record)
+		}
+	}
+	if _, err := store.RecordClick(ctx, "tok-other", nil); err != nil {
+		t.Fatalf("record click: %v", err)
+	}
+	if _, err := store.RecordClick(ctx, "missing", nil); !errors.Is(err, links.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+
+	clicks := store.Clicks("msg-1")
+	if clicks[links.ResolvedURLActionKey] != 2 || clicks[links.ResolvedURLManifestKey] != 0 || len(clicks) != 2 {
+		t.Fatalf("unexpected clicks %+v", clicks)
+	}
+	if got := store.ClickCount("msg-1"); got != 2 {
+		t.Fatalf("expected 2 clicks for msg-1, got %d", got)
+	}
+	if got := store.ClickCount("msg-2"); got != 1 {
+		t.Fatalf("expected 1 click for msg-2, got %d", got)
+	}
+}
+
+func TestMemoryStoreKeysRecordsByToken(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	err := store.Save(ctx, []links.LinkRecord{
+		{Token: "tok-1", URL: "https://example.com/shared", MessageID: "msg-1"},
+		{Token: "tok-2", URL: "https://example.com/shared", MessageID: "msg-2"},
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := store.RecordClick(ctx, "tok-1", nil); err != nil {
+		t.Fatalf("record click: %v", err)
+	}
+	if got := store.ClickCount("msg-1"); got != 1 {
+		t.Fatalf("expected 1 click for msg-1, got %d", got)
+	}
+	if got := store.ClickCount("msg-2"); got != 0 {
+		t.Fatalf("expected no clicks for msg-2, got %d", got)
+	}
+
+	record, err := store.Lookup(ctx, "tok-1")
+	if err != nil || record.MessageID != "msg-1" {
+		t.Fatalf("expected tok-1 to resolve msg-1, got %+v (%v)", record, err)
+	}
+	record, err = store.LookupURL(ctx, "https://example.com/shared")
+	if err != nil || record.Token != "tok-2" {
+		t.Fatalf("expected URL lookup to return the latest record, got %+v (%v)", record, err)
+	}
+	if _, err := store.Lookup(ctx, "missing"); !errors.Is(err, links.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+	if len(store.Records()) != 2 {
+		t.Fatalf("expected both records kept, got %+v", store.Records())
+	}
+}
+
+func TestClickHandlerRedirectsThroughMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Save(context.Background(), []links.LinkRecord{
+		{Token: "tok", URL: "https://example.com/reset", MessageID: "msg-1"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	handler := links.ClickHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/tok", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://example.com/reset" {
+		t.Fatalf("expected redirect to reset link, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if got := store.ClickCount("msg-1"); got != 1 {
+		t.Fatalf("expected 1 click, got %d", got)
+	}
+}
+
+func TestMemoryStoreConsumeSingleUseLink(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	record := links.LinkRecord{Token: "tok", URL: "https://example.com/reset", MessageID: "msg-1", SingleUse: true}
+	if err := store.Save(ctx, []links.LinkRecord{record}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	handler := links.ClickHandler(store)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/tok", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected first click to redirect, got %d", rec.Code)
+	}
+
+	// Saving the same link again must not revive it.
+	if err := store.Save(ctx, []links.LinkRecord{record}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/tok", nil))
+	if rec.Code != http.StatusGone {
+		t.Fatalf("expected consumed link to be rejected, got %d", rec.Code)
+	}
+	if ok, err := store.Consume(ctx, "tok"); ok || err != nil {
+		t.Fatalf("expected already consumed, got %v (%v)", ok, err)
+	}
+	if _, err := store.Consume(ctx, "missing"); !errors.Is(err, links.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStoreConsumesOnlyTheTokensRecord(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	err := store.Save(ctx, []links.LinkRecord{
+		{Token: "tok-1", URL: "https://example.com/reset", MessageID: "msg-1", SingleUse: true},
+		{Token: "tok-2", URL: "https://example.com/reset", MessageID: "msg-2", SingleUse: true},
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if ok, err := store.Consume(ctx, "tok-1"); !ok || err != nil {
+		t.Fatalf("expected tok-1 consumed, got %v (%v)", ok, err)
+	}
+	if ok, err := store.Consume(ctx, "tok-2"); !ok || err != nil {
+		t.Fatalf("expected tok-2 unaffected by tok-1, got %v (%v)", ok, err)
+	}
+	record, err := store.Lookup(ctx, "tok-1")
+	if err != nil || record.ConsumedAt.IsZero() || record.MessageID != "msg-1" {
+		t.Fatalf("expected tok-1 record consumed, got %+v (%v)", record, err)
+	}
+}
@@ -16,22 +16,11 @@ import (
 	"github.com/goliatone/go-notifications/pkg/secrets"
 )
 
-// AdapterRegistry holds information about configured adapters.
-type AdapterRegistry struct {
-	Adapters        []adapters.Messenger
-	EnabledAdapters []string
-	EnabledChannels []string
-	AdapterConfig   config.AdapterConfig
-}
-
-// BuildAdapters detects and builds all configured adapters.
-func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory, resolver secrets.Resolver, logs *DeliveryLogStore) *AdapterRegistry {
-	registry := &AdapterRegistry{
-		Adapters:        make([]adapters.Messenger, 0),
-		EnabledAdapters: make([]string, 0),
-		EnabledChannels: make([]string, 0),
-		AdapterConfig:   cfg,
-	}
+// BuildAdapters detects and builds all configured adapters. The notifier
+// module registers them in an adapters.Registry, which the app queries for
+// channels and providers.
+func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory, resolver secrets.Resolver, logs *DeliveryLogStore) []adapters.Messenger {
+	list := make([]adapters.Messenger, 0)
 
 	wrap := func(m adapters.Messenger) adapters.Messenger {
 		if dir == nil {
@@ -48,9 +37,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 
 	// Console adapter is always enabled
 	consoleAdapter := wrap(console.New(lgr))
-	registry.Adapters = append(registry.Adapters, consoleAdapter)
-	registry.EnabledAdapters = append(registry.EnabledAdapters, "console")
-	registry.addChannels("console", "email", "email:console")
+	list = append(list, consoleAdapter)
 
 	// Slack
 	if cfg.Slack.IsConfigured() {
@@ -60,9 +47,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			BaseURL: "https://slack.com/api",
 			Timeout: config.DefaultAdapterTimeout,
 		})))
-		registry.Adapters = append(registry.Adapters, slackAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "slack")
-		registry.addChannels("chat", "slack", "chat:slack")
+		list = append(list, slackAdapter)
 	}
 
 	// Telegram
@@ -73,9 +58,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			BaseURL: "https://api.telegram.org",
 			Timeout: config.DefaultAdapterTimeout,
 		})))
-		registry.Adapters = append(registry.Adapters, telegramAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "telegram")
-		registry.addChannels("chat", "chat:telegram", "telegram")
+		list = append(list, telegramAdapter)
 	}
 
 	// Twilio (SMS)
@@ -86,9 +69,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			From:       cfg.Twilio.FromPhone,
 			Timeout:    config.DefaultAdapterTimeout,
 		})))
-		registry.Adapters = append(registry.Adapters, twilioAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "twilio")
-		registry.addChannels("sms", "sms:twilio")
+		list = append(list, twilioAdapter)
 	}
 
 	// SendGrid (Email)
@@ -102,9 +83,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			sendgrid.WithFrom(fromEmail),
 			sendgrid.WithTimeout(30),
 		))
-		registry.Adapters = append(registry.Adapters, sendgridAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "sendgrid")
-		registry.addChannels("email", "email:sendgrid")
+		list = append(list, sendgridAdapter)
 	}
 
 	// Mailgun (Email)
@@ -119,9 +98,7 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			From:       fromEmail,
 			TimeoutSec: 30,
 		})))
-		registry.Adapters = append(registry.Adapters, mailgunAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "mailgun")
-		registry.addChannels("email", "email:mailgun")
+		list = append(list, mailgunAdapter)
 	}
 
 	// WhatsApp
@@ -131,59 +108,22 @@ func BuildAdapters(lgr logger.Logger, cfg config.AdapterConfig, dir *Directory,
 			PhoneNumberID: cfg.WhatsApp.FromPhone,
 			Timeout:       config.DefaultAdapterTimeout,
 		})))
-		registry.Adapters = append(registry.Adapters, whatsappAdapter)
-		registry.EnabledAdapters = append(registry.EnabledAdapters, "whatsapp")
-		registry.addChannels("whatsapp", "whatsapp:whatsapp")
+		list = append(list, whatsappAdapter)
 	}
 
-	return registry
-}
-
-// addChannels adds unique channels to the registry.
-func (r *AdapterRegistry) addChannels(channels ...string) {
-	for _, ch := range channels {
-		if !contains(r.EnabledChannels, ch) {
-			r.EnabledChannels = append(r.EnabledChannels, ch)
-		}
-	}
+	return list
 }
 
-// LogEnabledAdapters logs which adapters are configured and enabled.
-func (r *AdapterRegistry) LogEnabledAdapters(lgr logger.Logger) {
-	if len(r.EnabledAdapters) == 0 {
+// logEnabledAdapters logs which adapters are configured and enabled.
+func logEnabledAdapters(lgr logger.Logger, registry *adapters.Registry) {
+	names := registry.EnabledNames()
+	if len(names) == 0 {
 		lgr.Info("No adapters configured")
 		return
 	}
 
-	lgr.Info(fmt.Sprintf("Enabled adapters (%d): %v", len(r.EnabledAdapters), r.EnabledAdapters))
-	lgr.Info(fmt.Sprintf("Available channels: %v", r.EnabledChannels))
-}
-
-// GetAvailableChannels returns the list of channels that can be used.
-func (r *AdapterRegistry) GetAvailableChannels() []string {
-	// Always include in-app channel
-	channels := []string{"in-app"}
-	channels = append(channels, r.EnabledChannels...)
-	return uniqueStrings(channels)
-}
-
-// ProvidersForChannel returns adapter names that can deliver the given channel.
-func (r *AdapterRegistry) ProvidersForChannel(channel string) []string {
-	if r == nil {
-		return nil
-	}
-	base, _ := adapters.ParseChannel(channel)
-	providers := make([]string, 0)
-	for _, adapter := range r.Adapters {
-		caps := adapter.Capabilities()
-		for _, ch := range caps.Channels {
-			if candidate, _ := adapters.ParseChannel(ch); candidate == base {
-				providers = append(providers, adapter.Name())
-				break
-			}
-		}
-	}
-	return uniqueStrings(providers)
+	lgr.Info(fmt.Sprintf("Enabled adapters (%d): %v", len(names), names))
+	lgr.Info(fmt.Sprintf("Available channels: %v", registry.AvailableChannels()))
 }
 
 func contains(slice []string, item string) bool {
@@ -24,18 +24,17 @@ type DemoUser struct {
 }
 
 type App struct {
-	Config          config.Config
-	Module          *notifier.Module
-	Catalog         *commands.Catalog
-	DB              *bun.DB
-	Logger          logger.Logger
-	WSHub           *WebSocketHub
-	Directory       *Directory
-	DeliveryLogs    *DeliveryLogStore
-	Users           map[string]*DemoUser
-	Sessions        map[string]*DemoUser
-	Translator      i18n.Translator
-	AdapterRegistry *AdapterRegistry
+	Config       config.Config
+	Module       *notifier.Module
+	Catalog      *commands.Catalog
+	DB           *bun.DB
+	Logger       logger.Logger
+	WSHub        *WebSocketHub
+	Directory    *Directory
+	DeliveryLogs *DeliveryLogStore
+	Users        map[string]*DemoUser
+	Sessions     map[string]*DemoUser
+	Translator   i18n.Translator
 }
 
 func NewApp(ctx context.Context, cfg config.Config) (*App, error) {
@@ -60,8 +59,7 @@ func NewApp(ctx context.Context, cfg config.Config) (*App, error) {
 
 	// Build adapters based on environment configuration
 	adapterCfg := config.LoadAdapterConfig()
-	adapterRegistry := BuildAdapters(lgr, adapterCfg, directory, secretResolver, deliveryLogs)
-	adapterRegistry.LogEnabledAdapters(lgr)
+	adapterList := BuildAdapters(lgr, adapterCfg, directory, secretResolver, deliveryLogs)
 
 	var wsHub *WebSocketHub
 	if cfg.Features.EnableWebSocket {
@@ -75,26 +73,26 @@ func NewApp(ctx context.Context, cfg config.Config) (*App, error) {
 		Logger:      lgr,
 		Translator:  translator,
 		Broadcaster: wsHub,
-		Adapters:    adapterRegistry.Adapters,
+		Adapters:    adapterList,
 		Secrets:     secretResolver,
 	})
 	if err != nil {
 		return nil, err
 	}
+	logEnabledAdapters(lgr, module.AdapterRegistry())
 
 	app := &App{
-		Config:          cfg,
-		Module:          module,
-		Catalog:         module.Commands().Catalog,
-		DB:              db,
-		Logger:          lgr,
-		WSHub:           wsHub,
-		Directory:       directory,
-		DeliveryLogs:    deliveryLogs,
-		Users:           make(map[string]*DemoUser),
-		Sessions:        make(map[string]*DemoUser),
-		Translator:      translator,
-		AdapterRegistry: adapterRegistry,
+		Config:       cfg,
+		Module:       module,
+		Catalog:      module.Commands().Catalog,
+		DB:           db,
+		Logger:       lgr,
+		WSHub:        wsHub,
+		Directory:    directory,
+		DeliveryLogs: deliveryLogs,
+		Users:        make(map[string]*DemoUser),
+		Sessions:     make(map[string]*DemoUser),
+		Translator:   translator,
 	}
 
 	app.initDemoUsers()
@@ -149,6 +147,14 @@ func (a *App) CreateSession(user *DemoUser) string {
 	return sessionID
 }
 
+// AvailableChannels returns the channels the configured adapters can deliver,
+// plus the in-app inbox channel, which needs no adapter.
+func (a *App) AvailableChannels() []string {
+	channels := []string{"in-app"}
+	channels = append(channels, a.Module.AdapterRegistry().AvailableChannels()...)
+	return uniqueStrings(channels)
+}
+
 func (a *App) GetUserBySession(sessionID string) *DemoUser {
 	return a.Sessions[sessionID]
 }
@@ -157,7 +163,23 @@ func (a *App) DeleteSession(sessionID string) {
 	delete(a.Sessions, sessionID)
 }
 
+// Shutdown lets in-flight notifications finish (bounded by ctx) before
+// closing the hub and database.
+func (a *App) Shutdown(ctx context.Context) error {
+	var err error
+	if a.Module != nil {
+		err = a.Module.Shutdown(ctx)
+	}
+	if closeErr := a.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 func (a *App) Close() error {
+	if a.Module != nil {
+		_ = a.Module.Close()
+	}
 	if a.WSHub != nil {
 		a.WSHub.Close()
 	}
@@ -176,6 +198,10 @@ func notifierConfig() notifierconfig.Config {
 			MaxAttempts: 3,
 			MaxWorkers:  4,
 		},
+		Inbox: notifierconfig.InboxConfig{
+			Enabled:   true,
+			MaxPinned: 5,
+		},
 	}
 }
 
@@ -160,11 +160,6 @@ func buildTestApp(ctx context.Context, t *testing.T, cfg config.Config, lgr logg
 		ResolvingMessenger{inner: fakes["slack"], directory: directory, secrets: secretResolver, logs: deliveryLogs, logger: lgr},
 		ResolvingMessenger{inner: fakes["telegram"], directory: directory, secrets: secretResolver, logs: deliveryLogs, logger: lgr},
 	}
-	registry := &AdapterRegistry{
-		Adapters:        adaptersList,
-		EnabledAdapters: []string{"slack", "telegram"},
-		EnabledChannels: []string{"chat", "slack", "telegram", "chat:slack", "chat:telegram"},
-	}
 
 	module, err := notifier.NewModule(notifier.ModuleOptions{
 		Config:      notifierConfig(),
@@ -180,17 +175,16 @@ func buildTestApp(ctx context.Context, t *testing.T, cfg config.Config, lgr logg
 	}
 
 	app := &App{
-		Config:          cfg,
-		Module:          module,
-		Catalog:         module.Commands().Catalog,
-		DB:              db,
-		Logger:          lgr,
-		Directory:       directory,
-		DeliveryLogs:    deliveryLogs,
-		Users:           make(map[string]*DemoUser),
-		Sessions:        make(map[string]*DemoUser),
-		Translator:      &NoopTranslator{},
-		AdapterRegistry: registry,
+		Config:       cfg,
+		Module:       module,
+		Catalog:      module.Commands().Catalog,
+		DB:           db,
+		Logger:       lgr,
+		Directory:    directory,
+		DeliveryLogs: deliveryLogs,
+		Users:        make(map[string]*DemoUser),
+		Sessions:     make(map[string]*DemoUser),
+		Translator:   &NoopTranslator{},
 	}
 
 	app.initDemoUsers()
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -87,6 +90,9 @@ func (a *App) ListInbox(c router.Context) error {
 
 	unreadOnly := c.Query("unread_only") == "true"
 	filters := inbox.ListFilters{UnreadOnly: unreadOnly}
+	if tags := strings.TrimSpace(c.Query("tags")); tags != "" {
+		filters.Tags = strings.Split(tags, ",")
+	}
 
 	opts := store.ListOptions{Limit: 20, Offset: 0}
 
@@ -113,6 +119,16 @@ func countUnread(items []domain.InboxItem) int {
 	return count
 }
 
+func countPinned(items []domain.InboxItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Pinned {
+			count++
+		}
+	}
+	return count
+}
+
 func providerFromRules(rules domain.JSONMap, channel string) string {
 	if len(rules) == 0 {
 		return ""
@@ -241,6 +257,43 @@ func (a *App) SnoozeNotification(c router.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{"success": true})
 }
 
+// PinNotification pins an inbox item, reporting the pin limit when exceeded.
+func (a *App) PinNotification(c router.Context) error {
+	return a.setPinned(c, true)
+}
+
+// UnpinNotification unpins an inbox item.
+func (a *App) UnpinNotification(c router.Context) error {
+	return a.setPinned(c, false)
+}
+
+func (a *App) setPinned(c router.Context, pinned bool) error {
+	user := GetUser(c)
+	if user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
+	}
+
+	id := c.Param("id", "")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]any{"error": "id required"})
+	}
+
+	err := a.Module.Inbox().SetPinned(c.Context(), user.ID, id, pinned)
+	var limitErr inbox.PinLimitError
+	if errors.As(err, &limitErr) {
+		return c.JSON(http.StatusConflict, map[string]any{
+			"error":     err.Error(),
+			"pin_limit": limitErr.Limit,
+			"pinned":    limitErr.Current,
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]any{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"success": true})
+}
+
 // MarkAllRead marks all inbox items as read for the current user.
 func (a *App) MarkAllRead(c router.Context) error {
 	user := GetUser(c)
@@ -287,8 +340,10 @@ func (a *App) InboxStats(c router.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
-		"unread": countUnread(result.Items),
-		"total":  result.Total,
+		"unread":    countUnread(result.Items),
+		"pinned":    countPinned(result.Items),
+		"pin_limit": a.Module.Inbox().PinLimit(),
+		"total":     result.Total,
 	})
 }
 
@@ -336,7 +391,7 @@ func (a *App) GetPreferences(c router.Context) error {
 	for _, def := range defs.Items {
 		for _, channel := range def.Channels {
 			if _, ok := providerOptions[channel]; !ok {
-				providerOptions[channel] = a.AdapterRegistry.ProvidersForChannel(channel)
+				providerOptions[channel] = a.Module.AdapterRegistry().Providers(channel)
 			}
 			key := def.Code + ":" + channel
 			pref, exists := prefMap[key]
@@ -359,48 +414,90 @@ func (a *App) GetPreferences(c router.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{"preferences": items})
 }
 
-// UpdatePreferences updates user preferences.
+// preferenceUpdate is one row of an UpdatePreferences request.
+type preferenceUpdate struct {
+	DefinitionCode string `json:"definition_code"`
+	Channel        string `json:"channel"`
+	Enabled        *bool  `json:"enabled,omitempty"`
+	Provider       string `json:"provider,omitempty"`
+}
+
+// UpdatePreferences updates user preferences. The body is either a single
+// update or an array of updates applied together.
 func (a *App) UpdatePreferences(c router.Context) error {
 	user := GetUser(c)
 	if user == nil {
 		return c.JSON(http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
 	}
 
-	var req struct {
-		DefinitionCode string `json:"definition_code"`
-		Channel        string `json:"channel"`
-		Enabled        *bool  `json:"enabled,omitempty"`
-		Provider       string `json:"provider,omitempty"`
+	var updates []preferenceUpdate
+	body := bytes.TrimSpace(c.Body())
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &updates); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]any{"error": "invalid request"})
+		}
+	} else {
+		var single preferenceUpdate
+		if err := json.Unmarshal(body, &single); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]any{"error": "invalid request"})
+		}
+		updates = []preferenceUpdate{single}
+	}
+
+	inputs := make([]preferences.PreferenceInput, 0, len(updates))
+	for _, req := range updates {
+		update := preferences.PreferenceInput{
+			SubjectID:      user.ID,
+			SubjectType:    "user",
+			DefinitionCode: req.DefinitionCode,
+			Channel:        req.Channel,
+			Rules:          domain.JSONMap{},
+			Enabled:        req.Enabled,
+		}
+		if provider := strings.TrimSpace(req.Provider); provider != "" {
+			update.Rules = domain.JSONMap{
+				"channels": map[string]any{
+					strings.ToLower(req.Channel): map[string]any{
+						"provider": provider,
+					},
+				},
+			}
+		}
+		inputs = append(inputs, update)
 	}
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{"error": "invalid request"})
+
+	if err := a.Catalog.UpsertPreferences.Execute(c.Context(), commands.UpsertPreferences{Preferences: inputs}); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]any{"error": err.Error()})
 	}
 
-	update := preferences.PreferenceInput{
-		SubjectID:      user.ID,
-		SubjectType:    "user",
-		DefinitionCode: req.DefinitionCode,
-		Channel:        req.Channel,
-		Rules:          domain.JSONMap{},
+	return c.JSON(http.StatusOK, map[string]any{"success": true, "updated": len(inputs)})
+}
+
+// EffectivePreference explains whether the current user would receive a
+// definition on a channel right now.
+func (a *App) EffectivePreference(c router.Context) error {
+	user := GetUser(c)
+	if user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
 	}
-	if req.Enabled != nil {
-		update.Enabled = req.Enabled
+
+	code := strings.TrimSpace(c.Query("definition_code"))
+	channel := strings.TrimSpace(c.Query("channel"))
+	if code == "" || channel == "" {
+		return c.JSON(http.StatusBadRequest, map[string]any{"error": "definition_code and channel are required"})
 	}
-	if provider := strings.TrimSpace(req.Provider); provider != "" {
-		update.Rules = domain.JSONMap{
-			"channels": map[string]any{
-				strings.ToLower(req.Channel): map[string]any{
-					"provider": provider,
-				},
-			},
-		}
+
+	subject := preferences.Subject{UserID: user.ID, TenantID: user.TenantID, Locale: user.Locale}
+	if def, err := a.Module.Container().Storage.Definitions.GetByCode(c.Context(), code); err == nil {
+		subject.Category = def.Category
+		subject.Severity = def.Severity
 	}
 
-	if err := a.Catalog.UpsertPreference.Execute(c.Context(), update); err != nil {
+	state, err := a.Module.Preferences().Effective(c.Context(), subject, code, channel, time.Now())
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]any{"error": err.Error()})
 	}
-
-	return c.JSON(http.StatusOK, map[string]any{"success": true})
+	return c.JSON(http.StatusOK, state)
 }
 
 // SendTestNotification sends a test notification.
@@ -573,8 +670,8 @@ func (a *App) SendToUser(c router.Context) error {
 // GetAvailableChannels returns the list of configured notification channels.
 func (a *App) GetAvailableChannels(c router.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{
-		"channels": a.AdapterRegistry.GetAvailableChannels(),
-		"adapters": a.AdapterRegistry.EnabledAdapters,
+		"channels": a.AvailableChannels(),
+		"adapters": a.Module.AdapterRegistry().EnabledNames(),
 	})
 }
 
@@ -25,7 +25,6 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to create app: %v", err)
 	}
-	defer app.Close()
 
 	srv, err := buildServer(app)
 	if err != nil {
@@ -58,6 +57,9 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server shutdown error: %v", err)
 	}
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		log.Printf("App shutdown error: %v", err)
+	}
 }
 
 func buildServer(app *App) (router.Server[*fiber.App], error) {
@@ -26,11 +26,14 @@ func (a *App) SetupRoutes(r router.Router[*fiber.App]) {
 	api.Post("/inbox/:id/unread", a.MarkUnread)
 	api.Post("/inbox/:id/dismiss", a.DismissNotification)
 	api.Post("/inbox/:id/snooze", a.SnoozeNotification)
+	api.Post("/inbox/:id/pin", a.PinNotification)
+	api.Post("/inbox/:id/unpin", a.UnpinNotification)
 	api.Post("/inbox/mark-all-read", a.MarkAllRead)
 	api.Get("/inbox/stats", a.InboxStats)
 
 	api.Get("/preferences", a.GetPreferences)
 	api.Put("/preferences", a.UpdatePreferences)
+	api.Get("/preferences/effective", a.EffectivePreference)
 
 	api.Get("/channels", a.GetAvailableChannels)
 	api.Get("/deliveries/last", a.GetLastDeliveries)
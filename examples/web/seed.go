@@ -84,7 +84,7 @@ func ensureSeededOrError(ctx context.Context, app *App) error {
 
 func seedDefinitions(ctx context.Context, app *App) error {
 	// Get available channels from adapter registry
-	availableChannels := app.AdapterRegistry.GetAvailableChannels()
+	availableChannels := app.AvailableChannels()
 
 	// Helper to filter channels based on what's available
 	getChannels := func(desired ...string) []string {
@@ -17,13 +17,14 @@ import (
 
 // Catalog exposes go-command compatible handlers for host transports.
 type Catalog struct {
-	CreateDefinition command.Commander[CreateDefinition]
-	SaveTemplate     command.Commander[TemplateUpsert]
-	UpsertPreference command.Commander[preferences.PreferenceInput]
-	InboxMarkRead    command.Commander[InboxMarkRead]
-	InboxDismiss     command.Commander[InboxDismiss]
-	InboxSnooze      command.Commander[InboxSnooze]
-	EnqueueEvent     command.Commander[events.IntakeRequest]
+	CreateDefinition  command.Commander[CreateDefinition]
+	SaveTemplate      command.Commander[TemplateUpsert]
+	UpsertPreference  command.Commander[preferences.PreferenceInput]
+	UpsertPreferences command.Commander[UpsertPreferences]
+	InboxMarkRead     command.Commander[InboxMarkRead]
+	InboxDismiss      command.Commander[InboxDismiss]
+	InboxSnooze       command.Commander[InboxSnooze]
+	EnqueueEvent      command.Commander[events.IntakeRequest]
 }
 
 type templateService interface {
@@ -34,6 +35,7 @@ type templateService interface {
 
 type preferenceService interface {
 	Upsert(ctx context.Context, input preferences.PreferenceInput) (*domain.NotificationPreference, error)
+	UpsertMany(ctx context.Context, inputs []preferences.PreferenceInput) ([]*domain.NotificationPreference, error)
 }
 
 type inboxService interface {
@@ -78,13 +80,14 @@ func NewCatalog(deps Dependencies) (*Catalog, error) {
 	}
 
 	return &Catalog{
-		CreateDefinition: definitionCreateCommand{repo: deps.Definitions},
-		SaveTemplate:     templateUpsertCommand{templates: deps.Templates},
-		UpsertPreference: preferenceUpsertCommand{svc: deps.Preferences},
-		InboxMarkRead:    inboxMarkReadCommand{svc: deps.Inbox},
-		InboxDismiss:     inboxDismissCommand{svc: deps.Inbox},
-		InboxSnooze:      inboxSnoozeCommand{svc: deps.Inbox},
-		EnqueueEvent:     eventEnqueueCommand{svc: deps.Events},
+		CreateDefinition:  definitionCreateCommand{repo: deps.Definitions},
+		SaveTemplate:      templateUpsertCommand{templates: deps.Templates},
+		UpsertPreference:  preferenceUpsertCommand{svc: deps.Preferences},
+		UpsertPreferences: preferenceUpsertManyCommand{svc: deps.Preferences},
+		InboxMarkRead:     inboxMarkReadCommand{svc: deps.Inbox},
+		InboxDismiss:      inboxDismissCommand{svc: deps.Inbox},
+		InboxSnooze:       inboxSnoozeCommand{svc: deps.Inbox},
+		EnqueueEvent:      eventEnqueueCommand{svc: deps.Events},
 	}, nil
 }
 
@@ -179,6 +182,20 @@ func (c preferenceUpsertCommand) Execute(ctx context.Context, msg preferences.Pr
 	return err
 }
 
+// UpsertPreferences applies several preference changes in one call.
+type UpsertPreferences struct {
+	Preferences []preferences.PreferenceInput `json:"preferences"`
+}
+
+type preferenceUpsertManyCommand struct {
+	svc preferenceService
+}
+
+func (c preferenceUpsertManyCommand) Execute(ctx context.Context, msg UpsertPreferences) error {
+	_, err := c.svc.UpsertMany(ctx, msg.Preferences)
+	return err
+}
+
 // InboxMarkRead request payload.
 type InboxMarkRead struct {
 	UserID string   `json:"user_id"`
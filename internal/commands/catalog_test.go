@@ -68,6 +68,12 @@ func TestCatalogCommands(t *testing.T) {
 	if err := cat.UpsertPreference.Execute(ctx, preferences.PreferenceInput{SubjectType: "user", SubjectID: "u1", DefinitionCode: "welcome", Channel: "email"}); err != nil {
 		t.Fatalf("upsert preference: %v", err)
 	}
+	if err := cat.UpsertPreferences.Execute(ctx, UpsertPreferences{Preferences: []preferences.PreferenceInput{
+		{SubjectType: "user", SubjectID: "u1", DefinitionCode: "welcome", Channel: "email"},
+		{SubjectType: "user", SubjectID: "u1", DefinitionCode: "welcome", Channel: "sms"},
+	}}); err != nil {
+		t.Fatalf("upsert preferences: %v", err)
+	}
 
 	item, err := inboxSvc.Create(ctx, inbox.CreateInput{UserID: "u1", Title: "Hello", Body: "World"})
 	if err != nil {
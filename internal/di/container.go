@@ -2,6 +2,7 @@ package di
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 
 	i18n "github.com/goliatone/go-i18n"
@@ -26,23 +27,27 @@ import (
 
 // Options configure the DI container.
 type Options struct {
-	Config       config.Config
-	Storage      storage.Providers
-	Logger       logger.Logger
-	Cache        cache.Cache
-	Translator   i18n.Translator
-	Fallbacks    i18n.FallbackResolver
-	Queue        queue.Queue
-	Broadcaster  broadcaster.Broadcaster
-	Adapters     []adapters.Messenger
-	Attachments  adapters.AttachmentResolver
-	LinkBuilder  links.LinkBuilder
-	LinkStore    links.LinkStore
-	LinkObserver links.LinkObserver
-	LinkPolicy   links.FailurePolicy
-	Secrets      secrets.Resolver
-	Backoff      retry.Backoff
-	Activity     activity.Hooks
+	Config        config.Config
+	Storage       storage.Providers
+	Logger        logger.Logger
+	Cache         cache.Cache
+	Translator    i18n.Translator
+	Fallbacks     i18n.FallbackResolver
+	LocaleCatalog *i18n.LocaleCatalog
+	Queue         queue.Queue
+	Broadcaster   broadcaster.Broadcaster
+	Adapters      []adapters.Messenger
+	Strategies    map[string]adapters.SelectionStrategy
+	Attachments   adapters.AttachmentResolver
+	LinkBuilder   links.LinkBuilder
+	LinkStore     links.LinkStore
+	LinkObserver  links.LinkObserver
+	LinkPolicy    links.FailurePolicy
+	Secrets       secrets.Resolver
+	Backoff       retry.Backoff
+	Activity      activity.Hooks
+	Groups        preferences.GroupResolver
+	Subscriptions preferences.SubscriptionResolver
 }
 
 // Container wires repositories, services, dispatcher, commands, and manager.
@@ -112,6 +117,16 @@ func New(opts Options) (*Container, error) {
 	}
 
 	adapterRegistry := adapters.NewRegistry(opts.Adapters...)
+	for channel, selection := range cfg.Dispatcher.Selection {
+		strategy, err := adapters.NewSelectionStrategy(selection.Strategy, selection.Weights)
+		if err != nil {
+			return nil, fmt.Errorf("di: dispatcher.selection.%s: %w", channel, err)
+		}
+		adapterRegistry.SetStrategy(channel, strategy)
+	}
+	for channel, strategy := range opts.Strategies {
+		adapterRegistry.SetStrategy(channel, strategy)
+	}
 
 	tplSvc, err := templates.New(templates.Dependencies{
 		Repository:    providers.Templates,
@@ -119,6 +134,7 @@ func New(opts Options) (*Container, error) {
 		Logger:        lgr,
 		Translator:    opts.Translator,
 		Fallbacks:     opts.Fallbacks,
+		LocaleCatalog: opts.LocaleCatalog,
 		DefaultLocale: cfg.Localization.DefaultLocale,
 	})
 	if err != nil {
@@ -126,8 +142,12 @@ func New(opts Options) (*Container, error) {
 	}
 
 	prefSvc, err := preferences.New(preferences.Dependencies{
-		Repository: providers.Preferences,
-		Logger:     lgr,
+		Repository:         providers.Preferences,
+		Logger:             lgr,
+		KnownProviders:     adapterRegistry.ProviderNames,
+		CriticalSeverities: cfg.Preferences.CriticalSeverities,
+		Groups:             opts.Groups,
+		Subscriptions:      opts.Subscriptions,
 	})
 	if err != nil {
 		return nil, err
@@ -138,6 +158,7 @@ func New(opts Options) (*Container, error) {
 		Broadcaster: b,
 		Logger:      lgr,
 		Activity:    hooks,
+		MaxPinned:   cfg.Inbox.MaxPinned,
 	})
 	if err != nil {
 		return nil, err
@@ -160,6 +181,7 @@ func New(opts Options) (*Container, error) {
 		Preferences:  prefSvc,
 		Inbox:        inboxSvc,
 		Secrets:      secretsResolver,
+		Groups:       opts.Groups,
 		Backoff:      opts.Backoff,
 		Activity:     hooks,
 	})
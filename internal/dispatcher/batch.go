@@ -0,0 +1,253 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/domain"
+	"github.com/goliatone/go-notifications/pkg/links"
+)
+
+// supportsBatch reports whether deliveries on channel should go through the
+// batch path: more than one recipient and at least one registered adapter
+// with a batch endpoint. List is used instead of Select so checking does not
+// advance stateful strategies such as round-robin.
+func (s *Service) supportsBatch(channel string, recipients int) bool {
+	if recipients < 2 {
+		return false
+	}
+	for _, m := range s.registry.List(channel) {
+		if _, ok := m.(adapters.BatchMessenger); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// prebuildLinks resolves the links of every job in one BuildMany call when
+// the link builder implements links.BatchLinkBuilder. Preferences are
+// evaluated up front so opted-out deliveries are not signed; the resulting
+// plans ride on the returned jobs. Jobs that fail planning are dropped and
+// reported through the returned errors. Without a batch builder, or with a
+// single job, jobs are returned unchanged and links are built per delivery.
+func (s *Service) prebuildLinks(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, jobs []deliveryJob) ([]deliveryJob, []error) {
+	batcher, ok := s.linkBuilder.(links.BatchLinkBuilder)
+	if !ok || len(jobs) < 2 {
+		return jobs, nil
+	}
+	var errs []error
+	planned := make([]deliveryJob, 0, len(jobs))
+	var pending []int
+	var reqs []links.LinkRequest
+	for _, job := range jobs {
+		plan, err := s.planDelivery(ctx, event, def, job)
+		if err != nil {
+			job.reportFailure(err)
+			errs = append(errs, err)
+			continue
+		}
+		job.plan = plan
+		if !plan.skipped {
+			pending = append(pending, len(planned))
+			reqs = append(reqs, linkRequest(event, def, job, plan))
+		}
+		planned = append(planned, job)
+	}
+	if len(reqs) == 0 {
+		return planned, errs
+	}
+
+	results, err := batcher.BuildMany(ctx, reqs)
+	if err == nil && len(results) != len(reqs) {
+		err = fmt.Errorf("link builder returned %d results for %d requests", len(results), len(reqs))
+	}
+	for i, idx := range pending {
+		job := planned[idx]
+		var resolved links.ResolvedLinks
+		if err == nil {
+			resolved = results[i]
+		}
+		outcome := s.settleLinks(def, job, job.plan, reqs[i], resolved, err)
+		job.plan.links = &outcome
+	}
+	return planned, errs
+}
+
+// processBatch prepares every job for one channel, groups the deliveries by
+// the adapter chosen for them and hands each group to SendBatch. Deliveries
+// that fail in the batch fall back to the remaining candidates one by one.
+// It returns one error per failed delivery.
+func (s *Service) processBatch(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, jobs []deliveryJob) []error {
+	var errs []error
+	var order []string
+	groups := make(map[string][]*preparedDelivery)
+	batchers := make(map[string]adapters.BatchMessenger)
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			job.reportFailure(ctx.Err())
+			errs = append(errs, ctx.Err())
+			continue
+		}
+		delivery, err := s.prepareDelivery(ctx, event, def, job)
+		if err != nil {
+			job.reportFailure(err)
+			errs = append(errs, err)
+			continue
+		}
+		if delivery == nil {
+			continue
+		}
+		batcher, ok := delivery.candidates[0].(adapters.BatchMessenger)
+		if !ok {
+			success, provider, err := s.deliverToCandidates(ctx, delivery, delivery.candidates)
+			if err := s.finishDelivery(ctx, delivery, success, provider, err); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		name := batcher.Name()
+		if _, seen := groups[name]; !seen {
+			order = append(order, name)
+			batchers[name] = batcher
+		}
+		groups[name] = append(groups[name], delivery)
+	}
+
+	for _, name := range order {
+		errs = append(errs, s.deliverBatch(ctx, batchers[name], groups[name])...)
+	}
+	return errs
+}
+
+// deliverBatch sends a group of deliveries that share their first candidate.
+func (s *Service) deliverBatch(ctx context.Context, batcher adapters.BatchMessenger, deliveries []*preparedDelivery) []error {
+	outcomes := make([]error, len(deliveries))
+	var pending []int
+	var sendMsgs []adapters.Message
+	for i, delivery := range deliveries {
+		sendMsg, err := s.buildSendMessage(ctx, delivery, batcher)
+		if err != nil {
+			outcomes[i] = err
+			continue
+		}
+		pending = append(pending, i)
+		sendMsgs = append(sendMsgs, sendMsg)
+	}
+	if len(pending) > 0 {
+		results := s.deliverBatchWithRetries(ctx, batcher, deliveries, pending, sendMsgs)
+		// As in deliverToCandidates, deliveries whose credentials were
+		// rejected get one more batch with the rotated secret.
+		var rotated []int
+		var rotatedMsgs []adapters.Message
+		for j, idx := range pending {
+			outcomes[idx] = results[j]
+			if !errors.Is(results[j], adapters.ErrCredentialsRejected) {
+				continue
+			}
+			if refreshed, ok := s.refreshSecrets(ctx, deliveries[idx], batcher, sendMsgs[j]); ok {
+				rotated = append(rotated, idx)
+				rotatedMsgs = append(rotatedMsgs, refreshed)
+			}
+		}
+		if len(rotated) > 0 {
+			s.logger.Info("retrying batch deliveries with rotated secret", "provider", batcher.Name(), "count", len(rotated))
+			results = s.deliverBatchWithRetries(ctx, batcher, deliveries, rotated, rotatedMsgs)
+			for j, idx := range rotated {
+				outcomes[idx] = results[j]
+			}
+		}
+	}
+
+	var errs []error
+	for i, delivery := range deliveries {
+		success, provider, err := outcomes[i] == nil, batcher.Name(), outcomes[i]
+		if !success && len(delivery.candidates) > 1 {
+			if ok, fallbackProvider, fallbackErr := s.deliverToCandidates(ctx, delivery, delivery.candidates[1:]); ok || fallbackErr != nil {
+				success, provider, err = ok, fallbackProvider, fallbackErr
+			}
+		}
+		if err := s.finishDelivery(ctx, delivery, success, provider, err); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// deliverBatchWithRetries mirrors deliverWithRetries for a batch: each round
+// resends only the messages that failed with a retryable error, and attempts
+// are recorded against the message each result belongs to. The returned
+// slice lines up with indexes.
+func (s *Service) deliverBatchWithRetries(ctx context.Context, batcher adapters.BatchMessenger, deliveries []*preparedDelivery, indexes []int, sendMsgs []adapters.Message) []error {
+	results := make([]error, len(indexes))
+	messages := make([]domain.NotificationMessage, len(indexes))
+	for j, idx := range indexes {
+		messages[j] = *deliveries[idx].message
+	}
+
+	pending := make([]int, len(indexes))
+	for j := range pending {
+		pending[j] = j
+	}
+	attempts := make([]int, len(indexes))
+	for attempt := 1; attempt <= s.cfg.MaxAttempts && len(pending) > 0; attempt++ {
+		if ctx.Err() != nil {
+			for _, j := range pending {
+				results[j] = ctx.Err()
+			}
+			return results
+		}
+		batch := make([]adapters.Message, len(pending))
+		for k, j := range pending {
+			batch[k] = sendMsgs[j]
+		}
+		errs := adapters.SendBatch(ctx, batcher, batch)
+
+		var retry []int
+		var delay time.Duration
+		for k, j := range pending {
+			message := &messages[j]
+			attempts[j] = attempt
+			results[j] = errs[k]
+			if errs[k] == nil {
+				_ = s.recordAttempt(ctx, batcher.Name(), message, domain.AttemptStatusSucceeded, "", attempt)
+				message.Status = domain.MessageStatusDelivered
+				if s.messages != nil {
+					_ = s.messages.Update(ctx, message)
+				}
+				continue
+			}
+			s.logger.Warn("batch delivery error", "attempt", attempt, "recipient", message.Receiver, "error", errs[k])
+			_ = s.recordAttempt(ctx, batcher.Name(), message, domain.AttemptStatusFailed, errs[k].Error(), attempt)
+			if adapters.IsRetryable(errs[k]) && attempt < s.cfg.MaxAttempts {
+				retry = append(retry, j)
+				delay = max(delay, s.retryDelay(attempt, errs[k]))
+			}
+		}
+		pending = retry
+		if len(pending) == 0 {
+			break
+		}
+		if err := waitRetry(ctx, delay); err != nil {
+			for _, j := range pending {
+				results[j] = err
+			}
+			return results
+		}
+	}
+
+	for j, err := range results {
+		if err == nil {
+			continue
+		}
+		messages[j].Status = domain.MessageStatusFailed
+		if s.messages != nil {
+			_ = s.messages.Update(ctx, &messages[j])
+		}
+		results[j] = fmt.Errorf("dispatcher: delivery failed after %d attempts: %w", attempts[j], err)
+	}
+	return results
+}
@@ -0,0 +1,68 @@
+package dispatcher
+
+import (
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/domain"
+)
+
+// Delivery result statuses reported through DispatchOptions.OnResult.
+const (
+	ResultStatusDelivered = domain.MessageStatusDelivered
+	ResultStatusFailed    = domain.MessageStatusFailed
+	ResultStatusSkipped   = "skipped"
+)
+
+// DeliveryResult is the outcome of one delivery (one recipient on one
+// channel). Subject, Body and Locale are the rendered content and are empty
+// when the delivery failed or was skipped before rendering.
+type DeliveryResult struct {
+	MessageID string
+	Recipient string
+	Channel   string
+	Provider  string
+	Status    string
+	Locale    string
+	Subject   string
+	Body      string
+	Err       error
+}
+
+// report hands result to the job's OnResult callback, if any.
+func (job deliveryJob) report(result DeliveryResult) {
+	if job.onResult == nil {
+		return
+	}
+	if result.Recipient == "" {
+		result.Recipient = job.recipient
+	}
+	if result.Channel == "" {
+		result.Channel, _ = adapters.ParseChannel(job.channel)
+	}
+	job.onResult(result)
+}
+
+// reportFailure reports a delivery that failed before it reached a provider.
+func (job deliveryJob) reportFailure(err error) {
+	_, provider := adapters.ParseChannel(job.channel)
+	job.report(DeliveryResult{
+		Provider: provider,
+		Status:   ResultStatusFailed,
+		Locale:   job.locale,
+		Err:      err,
+	})
+}
+
+// messageResult builds a result carrying the rendered message content.
+func messageResult(message *domain.NotificationMessage, status, provider string, err error) DeliveryResult {
+	return DeliveryResult{
+		MessageID: message.ID.String(),
+		Recipient: message.Receiver,
+		Channel:   message.Channel,
+		Provider:  provider,
+		Status:    status,
+		Locale:    message.Locale,
+		Subject:   message.Subject,
+		Body:      message.Body,
+		Err:       err,
+	}
+}
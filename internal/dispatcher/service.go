@@ -1,14 +1,17 @@
 package dispatcher
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"maps"
+	"path"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/goliatone/go-notifications/internal/inbox"
 	"github.com/goliatone/go-notifications/pkg/activity"
 	"github.com/goliatone/go-notifications/pkg/adapters"
 	"github.com/goliatone/go-notifications/pkg/config"
@@ -21,7 +24,6 @@ import (
 	"github.com/goliatone/go-notifications/pkg/retry"
 	"github.com/goliatone/go-notifications/pkg/secrets"
 	"github.com/goliatone/go-notifications/pkg/templates"
-	opts "github.com/goliatone/go-options"
 	"github.com/google/uuid"
 )
 
@@ -47,6 +49,7 @@ type Dependencies struct {
 	Preferences  *prefsvc.Service
 	Inbox        inboxDeliverer
 	Secrets      secrets.Resolver
+	Groups       prefsvc.GroupResolver // group-scoped secrets, tried between user and tenant
 	Backoff      retry.Backoff
 	Activity     activity.Hooks
 }
@@ -69,14 +72,24 @@ type Service struct {
 	preferences  *prefsvc.Service
 	inbox        inboxDeliverer
 	secrets      secrets.Resolver
+	groups       prefsvc.GroupResolver
 	backoff      retry.Backoff
 	activity     activity.Hooks
+
+	inflight inflight
 }
 
 // DispatchOptions allow callers to override channels/locales.
 type DispatchOptions struct {
 	Channels []string
 	Locale   string
+	// OnResult, when set, is called once per delivery with its outcome.
+	// Deliveries run on concurrent workers, so it must be safe for
+	// concurrent use.
+	OnResult func(DeliveryResult)
+	// Include, when set, limits dispatch to the deliveries it returns true
+	// for. channelType is the channel without its provider (e.g. "email").
+	Include func(channelType, recipient string) bool
 }
 
 var (
@@ -139,6 +152,7 @@ func New(deps Dependencies) (*Service, error) {
 		preferences:  deps.Preferences,
 		inbox:        deps.Inbox,
 		secrets:      deps.Secrets,
+		groups:       deps.Groups,
 		backoff:      deps.Backoff,
 		activity:     deps.Activity,
 	}, nil
@@ -149,6 +163,10 @@ func (s *Service) Dispatch(ctx context.Context, event *domain.NotificationEvent,
 	if event == nil {
 		return errors.New("dispatcher: event is required")
 	}
+	if !s.inflight.begin() {
+		return ErrShuttingDown
+	}
+	defer s.inflight.done()
 	definition, err := s.definitions.GetByCode(ctx, event.DefinitionCode)
 	if err != nil {
 		return fmt.Errorf("dispatcher: load definition: %w", err)
@@ -175,6 +193,7 @@ func (s *Service) Dispatch(ctx context.Context, event *domain.NotificationEvent,
 		wg.Go(func() {
 			for job := range jobs {
 				if ctx.Err() != nil {
+					job.reportFailure(ctx.Err())
 					errCh <- ctx.Err()
 					continue
 				}
@@ -185,16 +204,49 @@ func (s *Service) Dispatch(ctx context.Context, event *domain.NotificationEvent,
 		})
 	}
 
+	channelJobs := make(map[string][]deliveryJob, len(channels))
+	allJobs := make([]deliveryJob, 0, len(channels)*len(recipients))
 	for _, channel := range channels {
-		templateCode := templateCodeForChannel(definition, channel)
+		templateCode := TemplateCodeForChannel(definition, channel)
+		channelType, _ := adapters.ParseChannel(channel)
 		for _, recipient := range recipients {
-			jobs <- deliveryJob{
+			if opts.Include != nil && !opts.Include(channelType, recipient) {
+				continue
+			}
+			allJobs = append(allJobs, deliveryJob{
 				event:        event,
 				channel:      channel,
 				templateCode: templateCode,
 				recipient:    recipient,
 				locale:       opts.Locale,
-			}
+				onResult:     opts.OnResult,
+			})
+		}
+	}
+	allJobs, planErrs := s.prebuildLinks(ctx, event, definition, allJobs)
+	for _, err := range planErrs {
+		errCh <- err
+	}
+	for _, job := range allJobs {
+		channelJobs[job.channel] = append(channelJobs[job.channel], job)
+	}
+
+	for _, channel := range channels {
+		jobsForChannel, ok := channelJobs[channel]
+		if !ok {
+			continue
+		}
+		delete(channelJobs, channel)
+		if s.supportsBatch(channel, len(jobsForChannel)) {
+			wg.Go(func() {
+				for _, err := range s.processBatch(ctx, event, definition, jobsForChannel) {
+					errCh <- err
+				}
+			})
+			continue
+		}
+		for _, job := range jobsForChannel {
+			jobs <- job
 		}
 	}
 	close(jobs)
@@ -222,14 +274,16 @@ func (s *Service) Dispatch(ctx context.Context, event *domain.NotificationEvent,
 	return nil
 }
 
+// resolveSecrets looks up the "default" secret for a delivery, plus any keys
+// the adapter declares through adapters.SecretKeyer. Scopes are tried in
+// this order, and the first one holding any of the keys supplies all of them:
+//
+//  1. user: the recipient
+//  2. group: each of the recipient's groups, in the order Groups returns them
+//  3. tenant: event.TenantID
+//  4. system: "default"
 func (s *Service) resolveSecrets(ctx context.Context, event *domain.NotificationEvent, job deliveryJob, messenger adapters.Messenger, overrideProvider string) (map[string][]byte, error) {
-	channelType, provider := adapters.ParseChannel(job.channel)
-	if overrideProvider != "" {
-		provider = overrideProvider
-	}
-	if provider == "" {
-		provider = messenger.Name()
-	}
+	channelType, provider := secretTarget(job, messenger, overrideProvider)
 	if s.secrets == nil {
 		if s.allowFallback(job.recipient, event) {
 			return nil, nil
@@ -237,23 +291,26 @@ func (s *Service) resolveSecrets(ctx context.Context, event *domain.Notification
 		return nil, fmt.Errorf("dispatcher: secrets resolver not configured and fallback not allowed for recipient %s", job.recipient)
 	}
 
-	refs := []secrets.Reference{
-		{Scope: secrets.ScopeUser, SubjectID: job.recipient, Channel: channelType, Provider: provider, Key: "default"},
-	}
-	if event != nil && strings.TrimSpace(event.TenantID) != "" {
-		refs = append(refs, secrets.Reference{Scope: secrets.ScopeTenant, SubjectID: event.TenantID, Channel: channelType, Provider: provider, Key: "default"})
+	refs, err := s.secretRefs(ctx, event, job.recipient, channelType, provider)
+	if err != nil {
+		return nil, err
 	}
-	refs = append(refs, secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: channelType, Provider: provider, Key: "default"})
-
-	resolved, err := s.secrets.Resolve(refs...)
+	keys := adapters.SecretKeys(messenger)
+	resolved, err := s.secrets.Resolve(withSecretKeys(refs, keys)...)
 	if err != nil && err != secrets.ErrNotFound {
 		return nil, err
 	}
 
-	// Prefer user -> tenant -> system
 	for _, ref := range refs {
-		if val, ok := resolved[ref]; ok {
-			return map[string][]byte{"default": val.Data}, nil
+		payload := make(map[string][]byte, len(keys))
+		for _, key := range keys {
+			ref.Key = key
+			if val, ok := resolved[ref]; ok {
+				payload[key] = val.Data
+			}
+		}
+		if len(payload) > 0 {
+			return payload, nil
 		}
 	}
 
@@ -263,63 +320,230 @@ func (s *Service) resolveSecrets(ctx context.Context, event *domain.Notification
 	return nil, fmt.Errorf("dispatcher: no scoped secret for recipient %s and fallback not allowed", job.recipient)
 }
 
+// secretTarget returns the channel type and provider secrets are looked up
+// under for a delivery.
+func secretTarget(job deliveryJob, messenger adapters.Messenger, overrideProvider string) (string, string) {
+	channelType, provider := adapters.ParseChannel(job.channel)
+	if overrideProvider != "" {
+		provider = overrideProvider
+	}
+	if provider == "" {
+		provider = messenger.Name()
+	}
+	return channelType, provider
+}
+
+// secretRefs lists the references tried for a delivery, most specific first.
+func (s *Service) secretRefs(ctx context.Context, event *domain.NotificationEvent, recipient, channelType, provider string) ([]secrets.Reference, error) {
+	refs := []secrets.Reference{
+		{Scope: secrets.ScopeUser, SubjectID: recipient, Channel: channelType, Provider: provider, Key: "default"},
+	}
+	if s.groups != nil {
+		groups, err := s.groups(ctx, "user", recipient)
+		if err != nil {
+			return nil, fmt.Errorf("dispatcher: resolve groups for %s: %w", recipient, err)
+		}
+		tenantID := ""
+		if event != nil {
+			tenantID = event.TenantID
+		}
+		for _, group := range groups {
+			if group = strings.TrimSpace(group); group != "" {
+				refs = append(refs, secrets.Reference{Scope: secrets.ScopeGroup, SubjectID: secrets.GroupSubject(tenantID, group), Channel: channelType, Provider: provider, Key: "default"})
+			}
+		}
+	}
+	if event != nil && strings.TrimSpace(event.TenantID) != "" {
+		refs = append(refs, secrets.Reference{Scope: secrets.ScopeTenant, SubjectID: event.TenantID, Channel: channelType, Provider: provider, Key: "default"})
+	}
+	return append(refs, secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: channelType, Provider: provider, Key: "default"}), nil
+}
+
+// withSecretKeys expands refs, which use the "default" key, to one reference
+// per key in keys.
+func withSecretKeys(refs []secrets.Reference, keys []string) []secrets.Reference {
+	out := make([]secrets.Reference, 0, len(refs)*len(keys))
+	for _, ref := range refs {
+		for _, key := range keys {
+			ref.Key = key
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// secretInvalidator is implemented by caching resolvers such as
+// secrets.CachingResolver.
+type secretInvalidator interface {
+	Invalidate(refs ...secrets.Reference)
+}
+
+// refreshSecrets resolves the secrets for sendMsg again after the provider
+// rejected its credentials, bypassing any cache. It reports whether a
+// different secret came back, i.e. the secret was rotated since sendMsg was
+// built and one more attempt is worthwhile.
+func (s *Service) refreshSecrets(ctx context.Context, delivery *preparedDelivery, messenger adapters.Messenger, sendMsg adapters.Message) (adapters.Message, bool) {
+	if s.secrets == nil {
+		return sendMsg, false
+	}
+	current, _ := sendMsg.Metadata["secrets"].(map[string][]byte)
+	if len(current) == 0 {
+		return sendMsg, false
+	}
+	if cache, ok := s.secrets.(secretInvalidator); ok {
+		channelType, provider := secretTarget(delivery.job, messenger, delivery.preferredProvider)
+		if refs, err := s.secretRefs(ctx, delivery.event, delivery.job.recipient, channelType, provider); err == nil {
+			cache.Invalidate(withSecretKeys(refs, adapters.SecretKeys(messenger))...)
+		}
+	}
+	fresh, err := s.resolveSecrets(ctx, delivery.event, delivery.job, messenger, delivery.preferredProvider)
+	if err != nil || len(fresh) == 0 || maps.EqualFunc(fresh, current, bytes.Equal) {
+		return sendMsg, false
+	}
+	refreshed := sendMsg
+	refreshed.Metadata = maps.Clone(sendMsg.Metadata)
+	refreshed.Metadata["secrets"] = fresh
+	return refreshed, true
+}
+
+// allowFallback reports whether the recipient or the event's tenant matches
+// an EnvFallbackAllowlist entry, either exactly or as a glob pattern.
 func (s *Service) allowFallback(recipient string, event *domain.NotificationEvent) bool {
 	if len(s.cfg.EnvFallbackAllowlist) == 0 {
 		return false
 	}
 	for _, allowed := range s.cfg.EnvFallbackAllowlist {
-		if allowed == recipient {
+		if allowlistMatch(allowed, recipient) {
 			return true
 		}
-		if event != nil && allowed == event.TenantID {
+		if event != nil && allowlistMatch(allowed, event.TenantID) {
 			return true
 		}
 	}
 	return false
 }
 
+// allowlistMatch compares value to an allowlist entry. Entries containing
+// *, ? or [ are path.Match patterns ("*@example.com", "tenant-dev-*"); the
+// rest must match exactly. Empty values and malformed patterns never match.
+func allowlistMatch(pattern, value string) bool {
+	if value == "" || pattern == "" {
+		return false
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return pattern == value
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
 type deliveryJob struct {
 	event        *domain.NotificationEvent
 	channel      string
 	templateCode string
 	recipient    string
 	locale       string
+	plan         *deliveryPlan // set when links were built ahead in a batch
+	onResult     func(DeliveryResult)
 }
 
 func (s *Service) processDelivery(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob) error {
+	delivery, err := s.prepareDelivery(ctx, event, def, job)
+	if err != nil {
+		job.reportFailure(err)
+		return err
+	}
+	if delivery == nil {
+		return nil
+	}
+	success, provider, err := s.deliverToCandidates(ctx, delivery, delivery.candidates)
+	return s.finishDelivery(ctx, delivery, success, provider, err)
+}
+
+// preparedDelivery carries a rendered, persisted message between preparation
+// and sending so single and batched sends share the same steps.
+type preparedDelivery struct {
+	event             *domain.NotificationEvent
+	def               *domain.NotificationDefinition
+	job               deliveryJob
+	channel           string
+	preferredProvider string
+	message           *domain.NotificationMessage
+	format            string
+	locale            string
+	attachments       []adapters.Attachment
+	candidates        []adapters.Messenger
+}
+
+// deliveryPlan holds the per-delivery state computed before links are
+// resolved: the preference outcome, the message ID and the render payload.
+type deliveryPlan struct {
+	channelType       string
+	provider          string
+	inboxChannel      bool
+	renderLocale      string
+	preferredProvider string
+	resolvedProvider  string
+	skipped           bool // opted out by preferences
+	criticalOverride  bool // a critical severity bypassed a preference
+	messageID         uuid.UUID
+	payload           domain.JSONMap
+	basePayload       domain.JSONMap
+	attachments       []adapters.Attachment
+	links             *linkOutcome // resolved ahead of time by BuildMany
+}
+
+// linkOutcome is the result of resolving links for one delivery.
+type linkOutcome struct {
+	req       links.LinkRequest
+	resolved  links.ResolvedLinks
+	attempted bool // a builder was called
+	ok        bool // the builder succeeded, so records may be stored
+	err       error
+}
+
+// planDelivery evaluates preferences and builds the render payload for job.
+func (s *Service) planDelivery(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob) (*deliveryPlan, error) {
 	channelType, provider := adapters.ParseChannel(job.channel)
-	inboxChannel := isInboxChannel(channelType)
-	renderLocale := job.locale
-	if renderLocale == "" && event != nil {
+	plan := &deliveryPlan{
+		channelType:  channelType,
+		provider:     provider,
+		inboxChannel: isInboxChannel(channelType),
+		renderLocale: job.locale,
+	}
+	if plan.renderLocale == "" && event != nil {
 		if locale, ok := event.Context["locale"].(string); ok && locale != "" {
-			renderLocale = locale
+			plan.renderLocale = locale
 		}
 	}
 
-	preferredProvider := ""
-	if allowed, reason, providerOverride, err := s.allowDelivery(ctx, event, def, job.recipient, channelType); err != nil {
-		return fmt.Errorf("preferences evaluation: %w", err)
+	if allowed, reason, providerOverride, err := s.allowDelivery(ctx, event, def, job.recipient, channelType, plan.renderLocale); err != nil {
+		return nil, fmt.Errorf("preferences evaluation: %w", err)
 	} else if !allowed {
 		s.logger.Debug("delivery skipped by preferences",
 			"recipient", job.recipient,
 			"channel", channelType,
 			"reason", reason,
 		)
-		return nil
-	} else if providerOverride != "" {
-		preferredProvider = providerOverride
+		plan.skipped = true
+		return plan, nil
+	} else {
+		plan.criticalOverride = reason == prefsvc.ReasonCriticalOverride
+		if providerOverride != "" {
+			plan.preferredProvider = providerOverride
+		}
 	}
 
-	messageID := uuid.New()
+	plan.messageID = uuid.New()
 	payload := cloneJSONMap(event.Context)
 	if payload == nil {
 		payload = make(domain.JSONMap)
 	}
-	basePayload := cloneJSONMap(payload)
-	attachments := adapters.AttachmentsFromValue(payload["attachments"])
+	plan.basePayload = cloneJSONMap(payload)
+	plan.attachments = adapters.AttachmentsFromValue(payload["attachments"])
 	channelAttachments := adapters.ChannelAttachmentsFromValue(payload["channel_attachments"])
 	if override := adapters.ChannelAttachmentsFor(channelAttachments, channelType); len(override) > 0 {
-		attachments = override
+		plan.attachments = override
 	}
 	payload["recipient"] = job.recipient
 	payload["channel"] = channelType
@@ -327,16 +551,53 @@ func (s *Service) processDelivery(ctx context.Context, event *domain.Notificatio
 	payload["definition"] = def.Metadata
 	applyChannelOverridesToPayload(payload, channelType)
 	normalizeLinkPayload(payload)
+	plan.payload = payload
 
-	resolvedProvider := provider
-	if preferredProvider != "" {
-		resolvedProvider = preferredProvider
+	plan.resolvedProvider = provider
+	if plan.preferredProvider != "" {
+		plan.resolvedProvider = plan.preferredProvider
 	}
-	linkReq, resolvedLinks, builderAttempted, builderOK, err := s.resolveLinks(ctx, event, def, job, basePayload, payload, channelType, resolvedProvider, renderLocale, messageID)
-	if err != nil {
-		s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, nil, "failed", resolvedProvider, renderLocale, err))
-		return err
+	return plan, nil
+}
+
+// prepareDelivery evaluates preferences, renders and persists the message,
+// and resolves the ordered adapter candidates. A nil delivery with a nil
+// error means there is nothing left to send (skipped or delivered to inbox).
+func (s *Service) prepareDelivery(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob) (*preparedDelivery, error) {
+	plan := job.plan
+	if plan == nil {
+		var err error
+		if plan, err = s.planDelivery(ctx, event, def, job); err != nil {
+			return nil, err
+		}
+	}
+	if plan.skipped {
+		job.report(DeliveryResult{
+			Provider: plan.provider,
+			Status:   ResultStatusSkipped,
+			Locale:   plan.renderLocale,
+		})
+		return nil, nil
 	}
+	channelType, provider := plan.channelType, plan.provider
+	inboxChannel := plan.inboxChannel
+	renderLocale := plan.renderLocale
+	preferredProvider := plan.preferredProvider
+	resolvedProvider := plan.resolvedProvider
+	messageID := plan.messageID
+	payload := plan.payload
+	attachments := plan.attachments
+
+	outcome := plan.links
+	if outcome == nil {
+		resolved := s.resolveLinks(ctx, event, def, job, plan)
+		outcome = &resolved
+	}
+	if outcome.err != nil {
+		s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, nil, "failed", resolvedProvider, renderLocale, outcome.err))
+		return nil, outcome.err
+	}
+	resolvedLinks := outcome.resolved
 	applyResolvedLinksToPayload(payload, resolvedLinks)
 
 	renderResult, err := s.templates.Render(ctx, templates.RenderRequest{
@@ -355,7 +616,7 @@ func (s *Service) processDelivery(ctx context.Context, event *domain.Notificatio
 			"error", err,
 		)
 		s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, nil, "failed", provider, renderLocale, err))
-		return fmt.Errorf("render template %s: %w", job.templateCode, err)
+		return nil, fmt.Errorf("render template %s: %w", job.templateCode, err)
 	}
 
 	message := &domain.NotificationMessage{
@@ -371,110 +632,178 @@ func (s *Service) processDelivery(ctx context.Context, event *domain.Notificatio
 	}
 	applyChannelOverrides(payload, channelType, message)
 	applyResolvedLinksToMessage(message, resolvedLinks)
-	if builderAttempted {
-		if err := s.invokeLinkHooks(ctx, linkReq, resolvedLinks, builderOK, true); err != nil {
+	if plan.criticalOverride {
+		if message.Metadata == nil {
+			message.Metadata = make(domain.JSONMap, 1)
+		}
+		message.Metadata["preference_reason"] = prefsvc.ReasonCriticalOverride
+	}
+	if inboxChannel {
+		if tags := inboxTags(def, event); len(tags) > 0 {
+			message.Metadata["tags"] = tags
+		}
+	}
+	if outcome.attempted {
+		if err := s.invokeLinkHooks(ctx, outcome.req, resolvedLinks, outcome.ok, true); err != nil {
 			s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "failed", resolvedProvider, renderLocale, err))
-			return err
+			return nil, err
 		}
 	}
 	if s.messages != nil {
 		if err := s.messages.Create(ctx, message); err != nil {
 			s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "failed", provider, renderLocale, err))
-			return fmt.Errorf("persist message: %w", err)
+			return nil, fmt.Errorf("persist message: %w", err)
 		}
 	}
 
 	if inboxChannel {
 		if s.inbox == nil {
 			s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "failed", provider, renderLocale, errors.New("inbox service not configured")))
-			return errors.New("dispatcher: inbox channel requested but inbox service is not configured")
+			return nil, errors.New("dispatcher: inbox channel requested but inbox service is not configured")
 		}
 		if err := s.handleInboxDelivery(ctx, message); err != nil {
 			s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "failed", provider, renderLocale, err))
-			return err
+			return nil, err
 		}
 		s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "delivered", provider, renderLocale, nil))
-		return nil
+		job.report(messageResult(message, ResultStatusDelivered, provider, nil))
+		return nil, nil
 	}
 	// TODO: We should support multi-channel deliveries
 	routeChannel := job.channel
 	if preferredProvider != "" {
 		routeChannel = fmt.Sprintf("%s:%s", channelType, preferredProvider)
 	}
-	candidates := s.registry.List(routeChannel)
+	candidates := s.registry.Select(routeChannel)
 	if len(candidates) == 0 {
-		return fmt.Errorf("route channel %s: %w", routeChannel, adapters.ErrAdapterNotFound)
-	}
+		return nil, fmt.Errorf("route channel %s: %w", routeChannel, adapters.ErrAdapterNotFound)
+	}
+
+	return &preparedDelivery{
+		event:             event,
+		def:               def,
+		job:               job,
+		channel:           channelType,
+		preferredProvider: preferredProvider,
+		message:           message,
+		format:            renderResult.Format,
+		locale:            renderResult.Locale,
+		attachments:       attachments,
+		candidates:        candidates,
+	}, nil
+}
 
-	var success bool
+// deliverToCandidates tries each candidate in order and stops at the first
+// one that delivers.
+func (s *Service) deliverToCandidates(ctx context.Context, delivery *preparedDelivery, candidates []adapters.Messenger) (bool, string, error) {
 	var lastErr error
 	var lastProvider string
 
 	for _, messenger := range candidates {
-		resolvedAttachments := attachments
-		if s.attachments != nil && len(attachments) > 0 {
-			resolved, err := s.attachments.Resolve(ctx, adapters.AttachmentJob{
-				Channel:        channelType,
-				Provider:       messenger.Name(),
-				Recipient:      job.recipient,
-				EventID:        event.ID.String(),
-				DefinitionCode: def.Code,
-			}, attachments)
-			if err != nil {
-				lastErr = err
-				lastProvider = messenger.Name()
-				continue
-			}
-			resolvedAttachments = resolved
-		}
-
-		secretPayload, err := s.resolveSecrets(ctx, event, job, messenger, preferredProvider)
+		sendMsg, err := s.buildSendMessage(ctx, delivery, messenger)
 		if err != nil {
 			lastErr = err
 			lastProvider = messenger.Name()
 			continue
 		}
 
-		sendMsg := adapters.Message{
-			ID:          message.ID.String(),
-			Channel:     channelType,
-			Provider:    messenger.Name(),
-			Subject:     message.Subject,
-			Body:        message.Body,
-			To:          message.Receiver,
-			Attachments: resolvedAttachments,
-			Metadata: map[string]any{
-				"event_id":        event.ID.String(),
-				"definition_code": def.Code,
-			},
-			Locale: renderResult.Locale,
-		}
-		if len(secretPayload) > 0 {
-			sendMsg.Metadata["secrets"] = secretPayload
-		}
-		if len(message.Metadata) > 0 {
-			if sendMsg.Metadata == nil {
-				sendMsg.Metadata = make(map[string]any)
-			}
-			for k, v := range message.Metadata {
-				if _, exists := sendMsg.Metadata[k]; exists {
-					continue
-				}
-				sendMsg.Metadata[k] = v
+		// Use a copy so per-adapter status updates don't clobber each other mid-loop.
+		msgCopy := *delivery.message
+		err = s.deliverWithRetries(ctx, messenger, &msgCopy, sendMsg)
+		if errors.Is(err, adapters.ErrCredentialsRejected) {
+			if refreshed, ok := s.refreshSecrets(ctx, delivery, messenger, sendMsg); ok {
+				s.logger.Info("retrying delivery with rotated secret", "provider", messenger.Name())
+				msgCopy = *delivery.message
+				err = s.deliverWithRetries(ctx, messenger, &msgCopy, refreshed)
 			}
 		}
-
-		// Use a copy so per-adapter status updates don't clobber each other mid-loop.
-		msgCopy := *message
-		if err := s.deliverWithRetries(ctx, messenger, &msgCopy, sendMsg); err != nil {
+		if err != nil {
 			lastErr = err
 			lastProvider = messenger.Name()
 			continue
 		}
-		success = true
-		lastProvider = messenger.Name()
+		return true, messenger.Name(), nil
 	}
+	return false, lastProvider, lastErr
+}
 
+// buildSendMessage negotiates the body format and resolves attachments and
+// secrets for one candidate adapter.
+func (s *Service) buildSendMessage(ctx context.Context, delivery *preparedDelivery, messenger adapters.Messenger) (adapters.Message, error) {
+	event, def, job, message := delivery.event, delivery.def, delivery.job, delivery.message
+
+	body, deliveredFormat, err := adapters.NegotiateFormat(messenger.Capabilities(), delivery.format, message.Body)
+	if err != nil {
+		s.logger.Debug("delivery candidate skipped",
+			"provider", messenger.Name(),
+			"channel", delivery.channel,
+			"reason", err,
+		)
+		_ = s.recordAttempt(ctx, messenger.Name(), message, domain.AttemptStatusSkipped, err.Error(), 0)
+		return adapters.Message{}, err
+	}
+
+	resolvedAttachments := delivery.attachments
+	if s.attachments != nil && len(delivery.attachments) > 0 {
+		resolved, err := s.attachments.Resolve(ctx, adapters.AttachmentJob{
+			Channel:        delivery.channel,
+			Provider:       messenger.Name(),
+			Recipient:      job.recipient,
+			EventID:        event.ID.String(),
+			DefinitionCode: def.Code,
+		}, delivery.attachments)
+		if err != nil {
+			return adapters.Message{}, err
+		}
+		resolvedAttachments = resolved
+	}
+
+	secretPayload, err := s.resolveSecrets(ctx, event, job, messenger, delivery.preferredProvider)
+	if err != nil {
+		return adapters.Message{}, err
+	}
+
+	sendMsg := adapters.Message{
+		ID:          message.ID.String(),
+		Channel:     delivery.channel,
+		Provider:    messenger.Name(),
+		Subject:     message.Subject,
+		Body:        body,
+		To:          message.Receiver,
+		Attachments: resolvedAttachments,
+		Metadata: map[string]any{
+			"event_id":        event.ID.String(),
+			"definition_code": def.Code,
+		},
+		Locale: delivery.locale,
+	}
+	if def.Severity != "" {
+		sendMsg.Metadata["severity"] = def.Severity
+	}
+	if len(secretPayload) > 0 {
+		sendMsg.Metadata["secrets"] = secretPayload
+	}
+	if deliveredFormat != "" {
+		sendMsg.Metadata["format"] = deliveredFormat
+	}
+	for k, v := range message.Metadata {
+		if _, exists := sendMsg.Metadata[k]; exists {
+			continue
+		}
+		sendMsg.Metadata[k] = v
+	}
+	return sendMsg, nil
+}
+
+// finishDelivery records the outcome on the message and emits activity.
+func (s *Service) finishDelivery(ctx context.Context, delivery *preparedDelivery, success bool, provider string, lastErr error) error {
+	message := delivery.message
+	if success {
+		if message.Metadata == nil {
+			message.Metadata = make(domain.JSONMap)
+		}
+		message.Metadata["provider"] = provider
+	}
 	if s.messages != nil {
 		if success {
 			message.Status = domain.MessageStatusDelivered
@@ -485,19 +814,23 @@ func (s *Service) processDelivery(ctx context.Context, event *domain.Notificatio
 	}
 
 	if !success {
-		s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "failed", lastProvider, renderResult.Locale, lastErr))
+		s.activity.Notify(ctx, s.buildDeliveryActivity(delivery.event, delivery.def, delivery.job, message, "failed", provider, delivery.locale, lastErr))
+		delivery.job.report(messageResult(message, ResultStatusFailed, provider, lastErr))
 		return lastErr
 	}
-	s.activity.Notify(ctx, s.buildDeliveryActivity(event, def, job, message, "delivered", lastProvider, renderResult.Locale, nil))
+	s.activity.Notify(ctx, s.buildDeliveryActivity(delivery.event, delivery.def, delivery.job, message, "delivered", provider, delivery.locale, nil))
+	delivery.job.report(messageResult(message, ResultStatusDelivered, provider, nil))
 	return nil
 }
 
 func (s *Service) deliverWithRetries(ctx context.Context, messenger adapters.Messenger, message *domain.NotificationMessage, sendMsg adapters.Message) error {
 	var lastErr error
+	attempts := 0
 	for attempt := 1; attempt <= s.cfg.MaxAttempts; attempt++ {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		attempts = attempt
 		lastErr = messenger.Send(ctx, sendMsg)
 		if lastErr == nil {
 			_ = s.recordAttempt(ctx, messenger.Name(), message, domain.AttemptStatusSucceeded, "", attempt)
@@ -509,21 +842,57 @@ func (s *Service) deliverWithRetries(ctx context.Context, messenger adapters.Mes
 		}
 		s.logger.Warn("delivery error", "attempt", attempt, "error", lastErr)
 		_ = s.recordAttempt(ctx, messenger.Name(), message, domain.AttemptStatusFailed, lastErr.Error(), attempt)
-		var delay time.Duration
-		if s.backoff != nil {
-			delay = s.backoff.Next(attempt)
-		} else {
-			delay = retry.DefaultBackoff().Next(attempt)
+		if !adapters.IsRetryable(lastErr) || attempt == s.cfg.MaxAttempts {
+			break
 		}
-		if delay > 0 {
-			time.Sleep(delay)
+		if err := waitRetry(ctx, s.retryDelay(attempt, lastErr)); err != nil {
+			return err
 		}
 	}
 	message.Status = domain.MessageStatusFailed
 	if s.messages != nil {
 		_ = s.messages.Update(ctx, message)
 	}
-	return fmt.Errorf("dispatcher: delivery failed after %d attempts: %w", s.cfg.MaxAttempts, lastErr)
+	return fmt.Errorf("dispatcher: delivery failed after %d attempts: %w", attempts, lastErr)
+}
+
+// defaultMaxRetryDelay caps retry waits when DispatcherConfig.MaxRetryDelay
+// is unset.
+const defaultMaxRetryDelay = 30 * time.Second
+
+// retryDelay returns the backoff for the next attempt, stretched to honour a
+// provider supplied Retry-After and capped at the configured MaxRetryDelay.
+func (s *Service) retryDelay(attempt int, err error) time.Duration {
+	var delay time.Duration
+	if s.backoff != nil {
+		delay = s.backoff.Next(attempt)
+	} else {
+		delay = retry.DefaultBackoff().Next(attempt)
+	}
+	if after, ok := adapters.RetryAfter(err); ok && after > delay {
+		delay = after
+	}
+	limit := s.cfg.MaxRetryDelay
+	if limit <= 0 {
+		limit = defaultMaxRetryDelay
+	}
+	return min(delay, limit)
+}
+
+// waitRetry waits delay before the next attempt, returning ctx.Err() if ctx
+// ends first.
+func waitRetry(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func (s *Service) recordAttempt(ctx context.Context, adapterName string, message *domain.NotificationMessage, status, errMsg string, attempt int) error {
@@ -600,7 +969,9 @@ func (s *Service) buildDeliveryActivity(event *domain.NotificationEvent, def *do
 	}
 }
 
-func templateCodeForChannel(def *domain.NotificationDefinition, ch string) string {
+// TemplateCodeForChannel returns the template code the definition maps to the
+// channel type of ch ("chat:slack" matches a "chat:<code>" key).
+func TemplateCodeForChannel(def *domain.NotificationDefinition, ch string) string {
 	if def == nil {
 		return ""
 	}
@@ -765,30 +1136,45 @@ func linkMetadataFromPayload(payload domain.JSONMap, channel string) map[string]
 	return metadata
 }
 
-func (s *Service) resolveLinks(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob, basePayload, payload domain.JSONMap, channel, provider, locale string, messageID uuid.UUID) (links.LinkRequest, links.ResolvedLinks, bool, bool, error) {
-	// Precedence: overrides > original (builder wins later).
-	baseResolved := mergeResolvedLinks(
-		resolvedLinksFromPayload(basePayload),
-		resolvedLinksFromOverrides(basePayload, channel),
-	)
-	baseResolved = normalizeResolvedLinks(baseResolved)
+// resolveLinks resolves the links of a single delivery through Build.
+func (s *Service) resolveLinks(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob, plan *deliveryPlan) linkOutcome {
 	if s.linkBuilder == nil {
-		return links.LinkRequest{}, baseResolved, false, false, nil
+		return linkOutcome{resolved: baseResolvedLinks(plan)}
 	}
-	req := links.LinkRequest{
+	req := linkRequest(event, def, job, plan)
+	resolved, err := s.linkBuilder.Build(ctx, req)
+	return s.settleLinks(def, job, plan, req, resolved, err)
+}
+
+// baseResolvedLinks returns the pass-through links of the event payload.
+// Precedence: overrides > original (builder wins later).
+func baseResolvedLinks(plan *deliveryPlan) links.ResolvedLinks {
+	return normalizeResolvedLinks(mergeResolvedLinks(
+		resolvedLinksFromPayload(plan.basePayload),
+		resolvedLinksFromOverrides(plan.basePayload, plan.channelType),
+	))
+}
+
+func linkRequest(event *domain.NotificationEvent, def *domain.NotificationDefinition, job deliveryJob, plan *deliveryPlan) links.LinkRequest {
+	return links.LinkRequest{
 		EventID:      event.ID.String(),
 		Definition:   def.Code,
 		Recipient:    job.recipient,
-		Channel:      channel,
-		Provider:     provider,
+		Channel:      plan.channelType,
+		Provider:     plan.resolvedProvider,
 		TemplateCode: job.templateCode,
-		MessageID:    messageID.String(),
-		Locale:       locale,
-		Payload:      cloneJSONMap(payload),
-		Metadata:     linkMetadataFromPayload(payload, channel),
-		ResolvedURLs: resolvedURLsFromPayload(payload),
+		MessageID:    plan.messageID.String(),
+		Locale:       plan.renderLocale,
+		Payload:      cloneJSONMap(plan.payload),
+		Metadata:     linkMetadataFromPayload(plan.payload, plan.channelType),
+		ResolvedURLs: resolvedURLsFromPayload(plan.payload),
 	}
-	resolved, err := s.linkBuilder.Build(ctx, req)
+}
+
+// settleLinks merges a builder result over the pass-through links, applying
+// the builder failure policy when err is set.
+func (s *Service) settleLinks(def *domain.NotificationDefinition, job deliveryJob, plan *deliveryPlan, req links.LinkRequest, resolved links.ResolvedLinks, err error) linkOutcome {
+	baseResolved := baseResolvedLinks(plan)
 	if err != nil {
 		if s.linkPolicy.Builder == links.FailureLenient {
 			s.logger.Warn("link builder failed; continuing with pass-through links",
@@ -797,16 +1183,16 @@ func (s *Service) resolveLinks(ctx context.Context, event *domain.NotificationEv
 				"recipient", job.recipient,
 				"error", err,
 			)
-			baseResolved = ensureResolvedLinkRecords(req, baseResolved)
-			return req, baseResolved, true, false, nil
+			baseResolved = assignLinkTokens(ensureResolvedLinkRecords(req, baseResolved))
+			return linkOutcome{req: req, resolved: baseResolved, attempted: true}
 		}
-		return req, links.ResolvedLinks{}, true, false, err
+		return linkOutcome{req: req, attempted: true, err: err}
 	}
 	resolved = normalizeResolvedLinks(resolved)
 	merged := mergeResolvedLinks(baseResolved, resolved)
 	merged = normalizeResolvedLinks(merged)
-	merged = ensureResolvedLinkRecords(req, merged)
-	return req, merged, true, true, nil
+	merged = assignLinkTokens(ensureResolvedLinkRecords(req, merged))
+	return linkOutcome{req: req, resolved: merged, attempted: true, ok: true}
 }
 
 func (s *Service) invokeLinkHooks(ctx context.Context, req links.LinkRequest, resolved links.ResolvedLinks, allowStore, allowObserver bool) error {
@@ -848,8 +1234,12 @@ func (s *Service) notifyLinkObserver(ctx context.Context, req links.LinkRequest,
 		}
 	}()
 	s.linkObserver.OnLinksResolved(ctx, links.LinkResolution{
-		Request:  req,
-		Resolved: resolved,
+		Request:       req,
+		Resolved:      resolved,
+		TrackingToken: trackingToken(resolved),
+		Channel:       req.Channel,
+		Recipient:     req.Recipient,
+		MessageID:     req.MessageID,
 	})
 	return nil
 }
@@ -932,6 +1322,41 @@ func buildLinkRecords(req links.LinkRequest, resolved links.ResolvedLinks) []lin
 	return records
 }
 
+// assignLinkTokens gives every record without a tracking token a fresh one.
+// Records are copied so builder-owned slices are left untouched.
+func assignLinkTokens(resolved links.ResolvedLinks) links.ResolvedLinks {
+	if len(resolved.Records) == 0 {
+		return resolved
+	}
+	records := make([]links.LinkRecord, len(resolved.Records))
+	copy(records, resolved.Records)
+	for i := range records {
+		if records[i].Token == "" {
+			records[i].Token = uuid.NewString()
+		}
+	}
+	resolved.Records = records
+	return resolved
+}
+
+// trackingToken returns the token of the action link record, falling back to
+// the first record carrying a token.
+func trackingToken(resolved links.ResolvedLinks) string {
+	fallback := ""
+	for _, record := range resolved.Records {
+		if record.Token == "" {
+			continue
+		}
+		if record.URL == resolved.ActionURL {
+			return record.Token
+		}
+		if fallback == "" {
+			fallback = record.Token
+		}
+	}
+	return fallback
+}
+
 func mergeResolvedLinks(base, override links.ResolvedLinks) links.ResolvedLinks {
 	if override.ActionURL != "" {
 		base.ActionURL = override.ActionURL
@@ -942,6 +1367,9 @@ func mergeResolvedLinks(base, override links.ResolvedLinks) links.ResolvedLinks
 	if override.URL != "" {
 		base.URL = override.URL
 	}
+	if !override.ExpiresAt.IsZero() {
+		base.ExpiresAt = override.ExpiresAt
+	}
 	if len(override.Metadata) > 0 {
 		if base.Metadata == nil {
 			base.Metadata = make(map[string]any, len(override.Metadata))
@@ -967,6 +1395,12 @@ func applyResolvedLinksToPayload(payload domain.JSONMap, resolved links.Resolved
 	if resolved.URL != "" {
 		payload[links.ResolvedURLKey] = resolved.URL
 	}
+	if !resolved.ExpiresAt.IsZero() {
+		payload[links.ResolvedExpiresAtKey] = resolved.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if token := trackingToken(resolved); token != "" {
+		payload[links.ResolvedTrackingTokenKey] = token
+	}
 }
 
 func applyResolvedLinksToMessage(message *domain.NotificationMessage, resolved links.ResolvedLinks) {
@@ -982,12 +1416,21 @@ func applyResolvedLinksToMessage(message *domain.NotificationMessage, resolved l
 	if resolved.URL != "" {
 		message.URL = resolved.URL
 	}
+	if !resolved.ExpiresAt.IsZero() {
+		message.LinkExpiresAt = resolved.ExpiresAt.UTC()
+	}
 	if len(resolved.Metadata) > 0 {
 		if message.Metadata == nil {
 			message.Metadata = make(domain.JSONMap, len(resolved.Metadata))
 		}
 		maps.Copy(message.Metadata, resolved.Metadata)
 	}
+	if token := trackingToken(resolved); token != "" {
+		if message.Metadata == nil {
+			message.Metadata = make(domain.JSONMap, 1)
+		}
+		message.Metadata[links.ResolvedTrackingTokenKey] = token
+	}
 }
 
 func resolvedURLsFromPayload(payload domain.JSONMap) map[string]string {
@@ -1084,16 +1527,23 @@ func (s *Service) handleInboxDelivery(ctx context.Context, message *domain.Notif
 	return nil
 }
 
-func (s *Service) allowDelivery(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, recipient, channel string) (bool, string, string, error) {
+// allowDelivery evaluates the recipient's preferences for the delivery and
+// returns the verdict, its reason and any preferred provider.
+func (s *Service) allowDelivery(ctx context.Context, event *domain.NotificationEvent, def *domain.NotificationDefinition, recipient, channel, locale string) (bool, string, string, error) {
 	if s.preferences == nil || def == nil || event == nil {
 		return true, "", "", nil
 	}
-	scopes := buildPreferenceScopes(event, recipient, def.Code, channel)
+	scopes := buildPreferenceScopes(event, recipient, def.Code, def.Category, channel)
 	req := prefsvc.EvaluationRequest{
 		DefinitionCode: def.Code,
 		Channel:        channel,
 		Scopes:         scopes,
 		Subscriptions:  eventSubscriptions(event),
+		Severity:       def.Severity,
+		Locale:         locale,
+	}
+	if tz, ok := event.Context["timezone"].(string); ok {
+		req.Timezone = tz
 	}
 	if !event.ScheduledAt.IsZero() {
 		req.Timestamp = event.ScheduledAt
@@ -1102,40 +1552,15 @@ func (s *Service) allowDelivery(ctx context.Context, event *domain.NotificationE
 	if err != nil {
 		return false, "", "", err
 	}
-	if !result.Allowed {
-		return false, result.Reason, result.Provider, nil
-	}
-	return true, "", result.Provider, nil
+	return result.Allowed, result.Reason, result.Provider, nil
 }
 
-func buildPreferenceScopes(event *domain.NotificationEvent, recipient, definitionCode, channel string) []pkgoptions.PreferenceScopeRef {
-	var scopes []pkgoptions.PreferenceScopeRef
-	if recipient != "" {
-		scopes = append(scopes, pkgoptions.PreferenceScopeRef{
-			Scope:          opts.NewScope("user", opts.ScopePriorityUser),
-			SubjectType:    "user",
-			SubjectID:      recipient,
-			DefinitionCode: definitionCode,
-			Channel:        channel,
-		})
-	}
-	if event != nil && event.TenantID != "" {
-		scopes = append(scopes, pkgoptions.PreferenceScopeRef{
-			Scope:          opts.NewScope("tenant", opts.ScopePriorityTenant),
-			SubjectType:    "tenant",
-			SubjectID:      event.TenantID,
-			DefinitionCode: definitionCode,
-			Channel:        channel,
-		})
+func buildPreferenceScopes(event *domain.NotificationEvent, recipient, definitionCode, category, channel string) []pkgoptions.PreferenceScopeRef {
+	subject := prefsvc.Subject{UserID: recipient, Category: category}
+	if event != nil {
+		subject.TenantID = event.TenantID
 	}
-	scopes = append(scopes, pkgoptions.PreferenceScopeRef{
-		Scope:          opts.NewScope("system", opts.ScopePrioritySystem),
-		SubjectType:    "system",
-		SubjectID:      "default",
-		DefinitionCode: definitionCode,
-		Channel:        channel,
-	})
-	return scopes
+	return prefsvc.SubjectScopes(subject, definitionCode, channel)
 }
 
 func eventSubscriptions(event *domain.NotificationEvent) []string {
@@ -1164,6 +1589,19 @@ func eventSubscriptions(event *domain.NotificationEvent) []string {
 	}
 }
 
+// inboxTags derives inbox item tags from the definition category and any
+// event.Context["tags"] entries.
+func inboxTags(def *domain.NotificationDefinition, event *domain.NotificationEvent) []string {
+	var tags []string
+	if def != nil {
+		tags = append(tags, def.Category)
+	}
+	if event != nil {
+		tags = append(tags, inbox.TagsFromValue(event.Context["tags"])...)
+	}
+	return inbox.NormalizeTags(tags)
+}
+
 func isInboxChannel(channel string) bool {
 	switch channel {
 	case "inbox", "in-app", "inapp", "in_app":
@@ -1,8 +1,15 @@
 package dispatcher
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -10,12 +17,16 @@ import (
 	i18n "github.com/goliatone/go-i18n"
 	"github.com/goliatone/go-notifications/internal/storage/memory"
 	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/ses"
+	"github.com/goliatone/go-notifications/pkg/adapters/smtp"
 	"github.com/goliatone/go-notifications/pkg/config"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/cache"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
 	"github.com/goliatone/go-notifications/pkg/links"
+	prefsvc "github.com/goliatone/go-notifications/pkg/preferences"
+	"github.com/goliatone/go-notifications/pkg/secrets"
 	"github.com/goliatone/go-notifications/pkg/templates"
 	"github.com/google/uuid"
 )
@@ -43,6 +54,22 @@ func (b *captureLinkBuilder) Build(ctx context.Context, req links.LinkRequest) (
 	return b.buildFn(req)
 }
 
+type batchLinkBuilder struct {
+	captureLinkBuilder
+	batches [][]links.LinkRequest
+}
+
+func (b *batchLinkBuilder) BuildMany(ctx context.Context, reqs []links.LinkRequest) ([]links.ResolvedLinks, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.batches = append(b.batches, reqs)
+	out := make([]links.ResolvedLinks, len(reqs))
+	for i, req := range reqs {
+		out[i] = links.ResolvedLinks{ActionURL: "signed-" + req.Channel + "-" + req.Recipient}
+	}
+	return out, nil
+}
+
 type captureStore struct {
 	mu             sync.Mutex
 	calls          int
@@ -68,6 +95,26 @@ func (s *captureStore) Save(ctx context.Context, records []links.LinkRecord) err
 	return s.err
 }
 
+func (s *captureStore) RecordClick(ctx context.Context, token string, meta map[string]any) (links.LinkRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, batch := range s.records {
+		for _, record := range batch {
+			if record.Token == token {
+				return record, nil
+			}
+		}
+	}
+	return links.LinkRecord{}, links.ErrLinkNotFound
+}
+
+func (s *captureStore) Consume(ctx context.Context, token string) (bool, error) {
+	if _, err := s.RecordClick(ctx, token, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 type captureObserver struct {
 	mu    sync.Mutex
 	calls []links.LinkResolution
@@ -80,11 +127,13 @@ func (o *captureObserver) OnLinksResolved(ctx context.Context, info links.LinkRe
 }
 
 type testAdapter struct {
-	name     string
-	channels []string
-	mu       sync.Mutex
-	sends    []adapters.Message
-	err      error
+	name          string
+	channels      []string
+	formats       []string
+	autoDowngrade bool
+	mu            sync.Mutex
+	sends         []adapters.Message
+	err           error
 }
 
 func (a *testAdapter) Name() string {
@@ -92,10 +141,15 @@ func (a *testAdapter) Name() string {
 }
 
 func (a *testAdapter) Capabilities() adapters.Capability {
+	formats := a.formats
+	if formats == nil {
+		formats = []string{"text/plain"}
+	}
 	return adapters.Capability{
-		Name:     a.name,
-		Channels: a.channels,
-		Formats:  []string{"text/plain"},
+		Name:          a.name,
+		Channels:      a.channels,
+		Formats:       formats,
+		AutoDowngrade: a.autoDowngrade,
 	}
 }
 
@@ -112,9 +166,45 @@ func (a *testAdapter) Count() int {
 	return len(a.sends)
 }
 
+type batchTestAdapter struct {
+	testAdapter
+	reject  map[string]error
+	batches [][]adapters.Message
+}
+
+func (a *batchTestAdapter) SendBatch(ctx context.Context, msgs []adapters.Message) []error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.batches = append(a.batches, msgs)
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		errs[i] = a.reject[msg.To]
+	}
+	return errs
+}
+
+// tokenBatchAdapter rejects, as a batch, every message that does not carry
+// the expected default secret.
+type tokenBatchAdapter struct {
+	batchTestAdapter
+	want string
+}
+
+func (a *tokenBatchAdapter) SendBatch(ctx context.Context, msgs []adapters.Message) []error {
+	errs := a.batchTestAdapter.SendBatch(ctx, msgs)
+	for i, msg := range msgs {
+		token, _ := msg.Metadata["secrets"].(map[string][]byte)
+		if string(token["default"]) != a.want {
+			errs[i] = adapters.CredentialsRejected(errors.New("token: unexpected status 401"))
+		}
+	}
+	return errs
+}
+
 type failingAttemptAdapter struct {
 	name  string
 	calls int
+	err   error
 }
 
 func (a *failingAttemptAdapter) Name() string { return a.name }
@@ -125,9 +215,27 @@ func (a *failingAttemptAdapter) Capabilities() adapters.Capability {
 
 func (a *failingAttemptAdapter) Send(context.Context, adapters.Message) error {
 	a.calls++
+	if a.err != nil {
+		return a.err
+	}
 	return errors.New("injected failure")
 }
 
+// tokenAdapter accepts only messages carrying the expected default secret.
+type tokenAdapter struct {
+	testAdapter
+	want string
+}
+
+func (a *tokenAdapter) Send(ctx context.Context, msg adapters.Message) error {
+	_ = a.testAdapter.Send(ctx, msg)
+	token, _ := msg.Metadata["secrets"].(map[string][]byte)
+	if string(token["default"]) != a.want {
+		return adapters.CredentialsRejected(errors.New("token: unexpected status 401"))
+	}
+	return nil
+}
+
 type zeroBackoff struct{}
 
 func (zeroBackoff) Next(int) time.Duration { return 0 }
@@ -140,12 +248,16 @@ func TestApplyResolvedLinksToPayloadAndMessage(t *testing.T) {
 		ActionURL:   "https://example.com/action",
 		ManifestURL: "https://example.com/manifest",
 		URL:         "https://example.com/url",
+		ExpiresAt:   time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
 		Metadata: map[string]any{
 			"token": "abc123",
 		},
 	}
 
 	applyResolvedLinksToPayload(payload, resolved)
+	if payload[links.ResolvedExpiresAtKey] != "2026-01-01T12:00:00Z" {
+		t.Fatalf("expected link_expires_at in payload, got %v", payload[links.ResolvedExpiresAtKey])
+	}
 	if payload[links.ResolvedURLActionKey] != resolved.ActionURL {
 		t.Fatalf("expected action_url %s, got %v", resolved.ActionURL, payload[links.ResolvedURLActionKey])
 	}
@@ -174,6 +286,9 @@ func TestApplyResolvedLinksToPayloadAndMessage(t *testing.T) {
 	if message.Metadata["token"] != "abc123" {
 		t.Fatalf("expected metadata token to be copied")
 	}
+	if !message.LinkExpiresAt.Equal(resolved.ExpiresAt) {
+		t.Fatalf("expected message link expiry %v, got %v", resolved.ExpiresAt, message.LinkExpiresAt)
+	}
 	if _, ok := message.Metadata[links.ResolvedURLActionKey]; ok {
 		t.Fatalf("did not expect action_url in message metadata")
 	}
@@ -185,6 +300,38 @@ func TestApplyResolvedLinksToPayloadAndMessage(t *testing.T) {
 	}
 }
 
+func TestInboxTagsCombineCategoryAndContext(t *testing.T) {
+	def := &domain.NotificationDefinition{Code: "invoice", Category: "billing"}
+	event := &domain.NotificationEvent{
+		Context: domain.JSONMap{"tags": []any{"finance", "billing", " "}},
+	}
+	tags := inboxTags(def, event)
+	if len(tags) != 2 || tags[0] != "billing" || tags[1] != "finance" {
+		t.Fatalf("expected [billing finance], got %v", tags)
+	}
+	if tags := inboxTags(&domain.NotificationDefinition{}, &domain.NotificationEvent{}); len(tags) != 0 {
+		t.Fatalf("expected no tags, got %v", tags)
+	}
+}
+
+func TestBuildPreferenceScopesAddsCategoryScopes(t *testing.T) {
+	event := &domain.NotificationEvent{TenantID: "acme"}
+	scopes := buildPreferenceScopes(event, "user-1", "comment.created", "Social", "email")
+	if len(scopes) != 6 {
+		t.Fatalf("expected definition and category scope per subject, got %d", len(scopes))
+	}
+	user, userCategory := scopes[0], scopes[1]
+	if user.DefinitionCode != "comment.created" || userCategory.DefinitionCode != "category:social" {
+		t.Fatalf("unexpected definition codes: %q %q", user.DefinitionCode, userCategory.DefinitionCode)
+	}
+	if userCategory.Scope.Priority >= user.Scope.Priority || userCategory.Scope.Priority <= scopes[2].Scope.Priority {
+		t.Fatalf("category scope should rank between user and tenant: %+v", scopes)
+	}
+	if scopes := buildPreferenceScopes(event, "user-1", "comment.created", "", "email"); len(scopes) != 3 {
+		t.Fatalf("expected no category scopes without a category, got %d", len(scopes))
+	}
+}
+
 func TestNewRejectsInvalidDispatcherConfig(t *testing.T) {
 	defRepo := memory.NewDefinitionRepository()
 	tplRepo := memory.NewTemplateRepository()
@@ -275,6 +422,693 @@ func TestDeliverWithRetriesHonorsMaxAttempts(t *testing.T) {
 	}
 }
 
+func TestDeliverWithRetriesStopsOnPermanentError(t *testing.T) {
+	messenger := &failingAttemptAdapter{
+		name: "failing",
+		err:  adapters.Permanent(errors.New("unregistered token")),
+	}
+	svc := &Service{
+		cfg: config.DispatcherConfig{
+			MaxAttempts: 3,
+			MaxWorkers:  1,
+		},
+		backoff: zeroBackoff{},
+		logger:  &logger.Nop{},
+	}
+	msg := &domain.NotificationMessage{}
+
+	err := svc.deliverWithRetries(context.Background(), messenger, msg, adapters.Message{})
+	if err == nil {
+		t.Fatalf("expected delivery error")
+	}
+	if messenger.calls != 1 {
+		t.Fatalf("expected a single attempt for a permanent error, got %d", messenger.calls)
+	}
+	if adapters.IsRetryable(err) {
+		t.Fatalf("expected the returned error to stay permanent")
+	}
+	if msg.Status != domain.MessageStatusFailed {
+		t.Fatalf("expected message marked failed, got %q", msg.Status)
+	}
+}
+
+func TestRetryDelayCapsRetryAfter(t *testing.T) {
+	err := adapters.Retryable(errors.New("rate limited"), time.Hour)
+	svc := &Service{backoff: zeroBackoff{}}
+	if got := svc.retryDelay(1, err); got != defaultMaxRetryDelay {
+		t.Fatalf("expected default cap %s, got %s", defaultMaxRetryDelay, got)
+	}
+	svc.cfg.MaxRetryDelay = 2 * time.Second
+	if got := svc.retryDelay(1, err); got != 2*time.Second {
+		t.Fatalf("expected configured cap 2s, got %s", got)
+	}
+	short := adapters.Retryable(errors.New("rate limited"), time.Second)
+	if got := svc.retryDelay(1, short); got != time.Second {
+		t.Fatalf("expected Retry-After below the cap to be honoured, got %s", got)
+	}
+}
+
+func TestDeliverWithRetriesStopsWaitingOnCancel(t *testing.T) {
+	messenger := &failingAttemptAdapter{
+		name: "failing",
+		err:  adapters.Retryable(errors.New("rate limited"), time.Hour),
+	}
+	svc := &Service{
+		cfg: config.DispatcherConfig{
+			MaxAttempts:   3,
+			MaxWorkers:    1,
+			MaxRetryDelay: time.Hour,
+		},
+		backoff: zeroBackoff{},
+		logger:  &logger.Nop{},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := svc.deliverWithRetries(ctx, messenger, &domain.NotificationMessage{}, adapters.Message{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the retry wait to end with ctx, took %s", elapsed)
+	}
+	if messenger.calls != 1 {
+		t.Fatalf("expected a single attempt before cancellation, got %d", messenger.calls)
+	}
+}
+
+func TestDeliverBatchWithRetriesStopsWaitingOnCancel(t *testing.T) {
+	batcher := &batchTestAdapter{
+		testAdapter: testAdapter{name: "batch"},
+		reject: map[string]error{
+			"a@example.com": adapters.Retryable(errors.New("rate limited"), time.Hour),
+		},
+	}
+	svc := &Service{
+		cfg: config.DispatcherConfig{
+			MaxAttempts:   3,
+			MaxWorkers:    1,
+			MaxRetryDelay: time.Hour,
+		},
+		backoff: zeroBackoff{},
+		logger:  &logger.Nop{},
+	}
+	deliveries := []*preparedDelivery{
+		{message: &domain.NotificationMessage{Receiver: "a@example.com"}},
+		{message: &domain.NotificationMessage{Receiver: "b@example.com"}},
+	}
+	sendMsgs := []adapters.Message{{To: "a@example.com"}, {To: "b@example.com"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	errs := svc.deliverBatchWithRetries(ctx, batcher, deliveries, []int{0, 1}, sendMsgs)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the retry wait to end with ctx, took %s", elapsed)
+	}
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error for the retried message, got %v", errs[0])
+	}
+	if errs[1] != nil {
+		t.Fatalf("expected the delivered message to succeed, got %v", errs[1])
+	}
+	if len(batcher.batches) != 1 {
+		t.Fatalf("expected a single batch before cancellation, got %d", len(batcher.batches))
+	}
+}
+
+func TestProcessDeliveryRecordsCriticalOverride(t *testing.T) {
+	ctx := context.Background()
+	adapter := &testAdapter{name: "test", channels: []string{"email"}}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "alert-email", "email")
+	prefs, err := prefsvc.New(prefsvc.Dependencies{Repository: memory.NewPreferenceRepository()})
+	if err != nil {
+		t.Fatalf("preferences: %v", err)
+	}
+	disabled := false
+	if _, err := prefs.Create(ctx, prefsvc.PreferenceInput{
+		SubjectType:    "user",
+		SubjectID:      testRecipient,
+		DefinitionCode: "security-alert",
+		Channel:        "email",
+		Enabled:        &disabled,
+	}); err != nil {
+		t.Fatalf("seed preference: %v", err)
+	}
+	svc.preferences = prefs
+
+	def := &domain.NotificationDefinition{
+		Code:         "security-alert",
+		Severity:     "critical",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:alert-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+	}
+	job := deliveryJob{event: event, channel: "email", templateCode: "alert-email", recipient: testRecipient, locale: "en"}
+	if err := svc.processDelivery(ctx, event, def, job); err != nil {
+		t.Fatalf("process delivery: %v", err)
+	}
+	if adapter.Count() != 1 {
+		t.Fatalf("expected critical delivery despite opt-out, got %d sends", adapter.Count())
+	}
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil || len(list.Items) != 1 {
+		t.Fatalf("expected stored message, got %+v (%v)", list, err)
+	}
+	if got := list.Items[0].Metadata["preference_reason"]; got != prefsvc.ReasonCriticalOverride {
+		t.Fatalf("expected critical-override recorded in metadata, got %v", got)
+	}
+}
+
+func TestProcessDeliveryNegotiatesTemplateFormat(t *testing.T) {
+	ctx := context.Background()
+	def := &domain.NotificationDefinition{
+		Code:         "digest",
+		Channels:     domain.StringList{"sms"},
+		TemplateKeys: domain.StringList{"sms:digest-sms"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+	}
+	job := deliveryJob{
+		event:        event,
+		channel:      "sms",
+		templateCode: "digest-sms",
+		recipient:    testRecipient,
+		locale:       "en",
+	}
+
+	cases := []struct {
+		name          string
+		autoDowngrade bool
+		wantSends     int
+		wantBody      string
+	}{
+		{name: "skip", autoDowngrade: false, wantSends: 0},
+		{name: "downgrade", autoDowngrade: true, wantSends: 1, wantBody: "Hello\nWorld"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			adapter := &testAdapter{name: "sms-only", channels: []string{"sms"}, autoDowngrade: tc.autoDowngrade}
+			svc, _, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+			if _, err := tplSvc.Create(ctx, templates.TemplateInput{
+				Code:    "digest-sms",
+				Channel: "sms",
+				Locale:  "en",
+				Subject: "Digest",
+				Body:    "<p>Hello</p><p>World</p>",
+				Format:  "text/html",
+			}); err != nil {
+				t.Fatalf("seed template: %v", err)
+			}
+
+			err := svc.processDelivery(ctx, event, def, job)
+			if adapter.Count() != tc.wantSends {
+				t.Fatalf("expected %d sends, got %d", tc.wantSends, adapter.Count())
+			}
+			if tc.wantSends == 0 {
+				if !errors.Is(err, adapters.ErrFormatUnsupported) {
+					t.Fatalf("expected ErrFormatUnsupported, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("process delivery: %v", err)
+			}
+			sent := adapter.sends[0]
+			if sent.Body != tc.wantBody {
+				t.Fatalf("expected downgraded body %q, got %q", tc.wantBody, sent.Body)
+			}
+			if sent.Metadata["format"] != adapters.FormatText {
+				t.Fatalf("expected delivered format text/plain, got %v", sent.Metadata["format"])
+			}
+		})
+	}
+}
+
+func TestProcessDeliverySpreadsAcrossProvidersAndRecordsChoice(t *testing.T) {
+	ctx := context.Background()
+	primary := &testAdapter{name: "primary", channels: []string{"sms"}}
+	secondary := &testAdapter{name: "secondary", channels: []string{"sms"}}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, primary)
+	svc.registry.Register(secondary)
+	svc.registry.SetStrategy("sms", adapters.NewRoundRobinStrategy())
+	seedTemplate(t, tplSvc, "alert-sms", "sms")
+
+	def := &domain.NotificationDefinition{
+		Code:         "alert",
+		Channels:     domain.StringList{"sms"},
+		TemplateKeys: domain.StringList{"sms:alert-sms"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+	}
+	job := deliveryJob{event: event, channel: "sms", templateCode: "alert-sms", recipient: testRecipient, locale: "en"}
+	for i := 0; i < 2; i++ {
+		if err := svc.processDelivery(ctx, event, def, job); err != nil {
+			t.Fatalf("process delivery %d: %v", i, err)
+		}
+	}
+
+	if primary.Count() != 1 || secondary.Count() != 1 {
+		t.Fatalf("expected one send per provider, got primary=%d secondary=%d", primary.Count(), secondary.Count())
+	}
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	providers := map[any]int{}
+	for _, msg := range list.Items {
+		providers[msg.Metadata["provider"]]++
+	}
+	if providers["primary"] != 1 || providers["secondary"] != 1 {
+		t.Fatalf("expected chosen provider recorded on each message, got %v", providers)
+	}
+}
+
+func TestProcessDeliveryRetriesWithRotatedSecret(t *testing.T) {
+	ctx := context.Background()
+	adapter := &tokenAdapter{testAdapter: testAdapter{name: "token", channels: []string{"sms"}}, want: "new"}
+	svc, _, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "alert-sms", "sms")
+
+	provider := secrets.NewStaticProvider(nil)
+	system := secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: "sms", Provider: "token", Key: "default", Version: "v1"}
+	if _, err := provider.Put(system, []byte("old")); err != nil {
+		t.Fatalf("put v1: %v", err)
+	}
+	cache := secrets.WithCache(secrets.SimpleResolver{Provider: provider}, time.Minute)
+	svc.secrets = cache
+	job := deliveryJob{channel: "sms", recipient: testRecipient}
+	if _, err := svc.resolveSecrets(ctx, nil, job, adapter, ""); err != nil {
+		t.Fatalf("warm cache: %v", err)
+	}
+
+	system.Version = "v2"
+	if _, err := provider.PutSecret(system, []byte("new"), time.Minute); err != nil {
+		t.Fatalf("put v2: %v", err)
+	}
+
+	def := &domain.NotificationDefinition{
+		Code:         "alert",
+		Channels:     domain.StringList{"sms"},
+		TemplateKeys: domain.StringList{"sms:alert-sms"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+	}
+	job = deliveryJob{event: event, channel: "sms", templateCode: "alert-sms", recipient: testRecipient, locale: "en"}
+	if err := svc.processDelivery(ctx, event, def, job); err != nil {
+		t.Fatalf("process delivery: %v", err)
+	}
+	if adapter.Count() != 2 {
+		t.Fatalf("expected a rejected send and a retry, got %d sends", adapter.Count())
+	}
+}
+
+func TestProcessBatchRetriesWithRotatedSecret(t *testing.T) {
+	ctx := context.Background()
+	adapter := &tokenBatchAdapter{batchTestAdapter: batchTestAdapter{testAdapter: testAdapter{name: "token", channels: []string{"email"}}}, want: "new"}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "digest-email", "email")
+
+	provider := secrets.NewStaticProvider(nil)
+	system := secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: "email", Provider: "token", Key: "default", Version: "v1"}
+	if _, err := provider.Put(system, []byte("old")); err != nil {
+		t.Fatalf("put v1: %v", err)
+	}
+	svc.secrets = secrets.WithCache(secrets.SimpleResolver{Provider: provider}, time.Minute)
+	recipients := []string{testRecipient, "bob@example.com"}
+	for _, recipient := range recipients {
+		if _, err := svc.resolveSecrets(ctx, nil, deliveryJob{channel: "email", recipient: recipient}, adapter, ""); err != nil {
+			t.Fatalf("warm cache: %v", err)
+		}
+	}
+
+	system.Version = "v2"
+	if _, err := provider.PutSecret(system, []byte("new"), time.Minute); err != nil {
+		t.Fatalf("put v2: %v", err)
+	}
+
+	def := &domain.NotificationDefinition{
+		Code:         "digest",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:digest-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList(recipients),
+	}
+	jobs := make([]deliveryJob, 0, len(recipients))
+	for _, recipient := range recipients {
+		jobs = append(jobs, deliveryJob{event: event, channel: "email", templateCode: "digest-email", recipient: recipient, locale: "en"})
+	}
+	if errs := svc.processBatch(ctx, event, def, jobs); len(errs) != 0 {
+		t.Fatalf("expected the rotated secret to deliver, got %v", errs)
+	}
+	if len(adapter.batches) != 2 || len(adapter.batches[1]) != 2 {
+		t.Fatalf("expected a rejected batch and a retry batch, got %v", adapter.batches)
+	}
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	for _, msg := range list.Items {
+		if msg.Status != domain.MessageStatusDelivered {
+			t.Fatalf("expected %s delivered, got %s", msg.Receiver, msg.Status)
+		}
+	}
+}
+
+func TestResolveSecretsGroupScopePrecedence(t *testing.T) {
+	ctx := context.Background()
+	adapter := &testAdapter{name: "slack", channels: []string{"chat"}}
+	svc, _, _ := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	svc.groups = func(_ context.Context, subjectType, subjectID string) ([]string, error) {
+		if subjectType != "user" {
+			t.Fatalf("unexpected subject type %q", subjectType)
+		}
+		return []string{"team-ops"}, nil
+	}
+
+	ref := func(scope secrets.Scope, subject string) secrets.Reference {
+		return secrets.Reference{Scope: scope, SubjectID: subject, Channel: "chat", Provider: "slack", Key: "default", Version: "v1"}
+	}
+	provider := secrets.NewStaticProvider(map[secrets.Reference]secrets.SecretValue{
+		ref(secrets.ScopeTenant, "acme"):         {Data: []byte("tenant"), Version: "v1"},
+		ref(secrets.ScopeGroup, "acme/team-ops"): {Data: []byte("group"), Version: "v1"},
+		ref(secrets.ScopeUser, "alice"):          {Data: []byte("user"), Version: "v1"},
+	})
+	svc.secrets = secrets.SimpleResolver{Provider: provider}
+	event := &domain.NotificationEvent{TenantID: "acme"}
+
+	got, err := svc.resolveSecrets(ctx, event, deliveryJob{channel: "chat", recipient: "bob"}, adapter, "")
+	if err != nil {
+		t.Fatalf("resolve for bob: %v", err)
+	}
+	if string(got["default"]) != "group" {
+		t.Fatalf("expected group secret to win over tenant, got %q", got["default"])
+	}
+
+	got, err = svc.resolveSecrets(ctx, event, deliveryJob{channel: "chat", recipient: "alice"}, adapter, "")
+	if err != nil {
+		t.Fatalf("resolve for alice: %v", err)
+	}
+	if string(got["default"]) != "user" {
+		t.Fatalf("expected user secret to win over group, got %q", got["default"])
+	}
+}
+
+func TestResolveSecretsGroupScopeIsTenantQualified(t *testing.T) {
+	ctx := context.Background()
+	adapter := &testAdapter{name: "slack", channels: []string{"chat"}}
+	svc, _, _ := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	svc.groups = func(context.Context, string, string) ([]string, error) {
+		return []string{"team-ops"}, nil
+	}
+
+	ref := func(subject string) secrets.Reference {
+		return secrets.Reference{Scope: secrets.ScopeGroup, SubjectID: subject, Channel: "chat", Provider: "slack", Key: "default", Version: "v1"}
+	}
+	provider := secrets.NewStaticProvider(map[secrets.Reference]secrets.SecretValue{
+		ref("acme/team-ops"):   {Data: []byte("acme"), Version: "v1"},
+		ref("globex/team-ops"): {Data: []byte("globex"), Version: "v1"},
+	})
+	svc.secrets = secrets.SimpleResolver{Provider: provider}
+
+	for _, tenant := range []string{"acme", "globex"} {
+		event := &domain.NotificationEvent{TenantID: tenant}
+		got, err := svc.resolveSecrets(ctx, event, deliveryJob{channel: "chat", recipient: "bob"}, adapter, "")
+		if err != nil {
+			t.Fatalf("resolve for %s: %v", tenant, err)
+		}
+		if string(got["default"]) != tenant {
+			t.Fatalf("expected %s group secret, got %q", tenant, got["default"])
+		}
+	}
+
+	// A group ID shared across tenants must not leak into another tenant.
+	event := &domain.NotificationEvent{TenantID: "initech"}
+	if got, err := svc.resolveSecrets(ctx, event, deliveryJob{channel: "chat", recipient: "bob"}, adapter, ""); err == nil && len(got) > 0 {
+		t.Fatalf("expected no group secret for another tenant, got %q", got["default"])
+	}
+}
+
+func TestProcessDeliveryResolvesAdapterSecretKeys(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	var (
+		form url.Values
+		auth string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		_, _ = io.WriteString(w, `<SendEmailResponse><SendEmailResult><MessageId>abc</MessageId></SendEmailResult></SendEmailResponse>`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	adapter := ses.New(&logger.Nop{},
+		ses.WithConfig(ses.Config{Region: "eu-west-1", Endpoint: server.URL + "/"}),
+		ses.WithHTTPClient(server.Client()),
+	)
+	svc, _, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "welcome-email", "email")
+
+	ref := func(scope secrets.Scope, subject, key string) secrets.Reference {
+		return secrets.Reference{Scope: scope, SubjectID: subject, Channel: "email", Provider: "ses", Key: key, Version: "v1"}
+	}
+	provider := secrets.NewStaticProvider(map[secrets.Reference]secrets.SecretValue{
+		ref(secrets.ScopeTenant, "acme", "access_key_id"):     {Data: []byte("AKIDTENANT"), Version: "v1"},
+		ref(secrets.ScopeTenant, "acme", "secret_access_key"): {Data: []byte("shh"), Version: "v1"},
+		ref(secrets.ScopeTenant, "acme", "from"):              {Data: []byte("acme@example.com"), Version: "v1"},
+		ref(secrets.ScopeSystem, "default", "access_key_id"):  {Data: []byte("AKIDSYSTEM"), Version: "v1"},
+	})
+	svc.secrets = secrets.SimpleResolver{Provider: provider}
+
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:welcome-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		TenantID:       "acme",
+		Recipients:     domain.StringList{testRecipient},
+	}
+	job := deliveryJob{event: event, channel: "email", templateCode: "welcome-email", recipient: testRecipient, locale: "en"}
+	if err := svc.processDelivery(ctx, event, def, job); err != nil {
+		t.Fatalf("process delivery: %v", err)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDTENANT/") {
+		t.Fatalf("expected tenant credentials, got authorization %q", auth)
+	}
+	if form.Get("Source") != "acme@example.com" {
+		t.Fatalf("expected tenant from address, got %q", form.Get("Source"))
+	}
+}
+
+func TestResolveSecretsTakesAdapterKeysFromOneScope(t *testing.T) {
+	ctx := context.Background()
+	adapter := adapters.WithRateLimit(smtp.New(&logger.Nop{}), 0, 1)
+	svc, _, _ := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+
+	ref := func(scope secrets.Scope, subject, key string) secrets.Reference {
+		return secrets.Reference{Scope: scope, SubjectID: subject, Channel: "email", Provider: "smtp", Key: key, Version: "v1"}
+	}
+	provider := secrets.NewStaticProvider(map[secrets.Reference]secrets.SecretValue{
+		ref(secrets.ScopeTenant, "acme", "username"):    {Data: []byte("acme-user"), Version: "v1"},
+		ref(secrets.ScopeTenant, "acme", "password"):    {Data: []byte("acme-pass"), Version: "v1"},
+		ref(secrets.ScopeSystem, "default", "from"):     {Data: []byte("system@example.com"), Version: "v1"},
+		ref(secrets.ScopeSystem, "default", "username"): {Data: []byte("system-user"), Version: "v1"},
+	})
+	svc.secrets = secrets.SimpleResolver{Provider: provider}
+
+	event := &domain.NotificationEvent{TenantID: "acme"}
+	got, err := svc.resolveSecrets(ctx, event, deliveryJob{channel: "email", recipient: testRecipient}, adapter, "")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := map[string][]byte{"username": []byte("acme-user"), "password": []byte("acme-pass")}
+	if !maps.EqualFunc(got, want, bytes.Equal) {
+		t.Fatalf("expected only tenant keys, got %q", got)
+	}
+}
+
+func TestAllowFallbackMatchesPatterns(t *testing.T) {
+	svc := &Service{cfg: config.DispatcherConfig{EnvFallbackAllowlist: []string{
+		"admin@example.com",
+		"*@test.example.com",
+		"tenant-dev-*",
+		"qa-?",
+		"[broken",
+	}}}
+	cases := []struct {
+		recipient string
+		tenant    string
+		want      bool
+	}{
+		{recipient: "admin@example.com", want: true},
+		{recipient: "Admin@example.com", want: false},
+		{recipient: "bob@test.example.com", want: true},
+		{recipient: "bob@test.example.com.evil.io", want: false},
+		{recipient: "bob@example.com", want: false},
+		{recipient: "u1", tenant: "tenant-dev-42", want: true},
+		{recipient: "u1", tenant: "tenant-dev-", want: true},
+		{recipient: "u1", tenant: "tenant-prod-1", want: false},
+		{recipient: "qa-1", want: true},
+		{recipient: "qa-12", want: false},
+		{recipient: "[broken", want: false},
+		{recipient: "", tenant: "", want: false},
+	}
+	for _, tc := range cases {
+		event := &domain.NotificationEvent{TenantID: tc.tenant}
+		if got := svc.allowFallback(tc.recipient, event); got != tc.want {
+			t.Fatalf("allowFallback(%q, tenant %q) = %v, want %v", tc.recipient, tc.tenant, got, tc.want)
+		}
+	}
+
+	wildcard := &Service{cfg: config.DispatcherConfig{EnvFallbackAllowlist: []string{"*"}}}
+	if wildcard.allowFallback("", &domain.NotificationEvent{}) {
+		t.Fatalf("expected empty recipient and tenant never to match")
+	}
+}
+
+func TestProcessBatchMapsPerRecipientErrors(t *testing.T) {
+	ctx := context.Background()
+	bulk := &batchTestAdapter{
+		testAdapter: testAdapter{name: "bulk", channels: []string{"email"}},
+		reject:      map[string]error{"bob@example.com": adapters.Permanent(errors.New("invalid address"))},
+	}
+	backup := &testAdapter{name: "backup", channels: []string{"email"}}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, bulk)
+	svc.registry.Register(backup)
+	recipients := []string{testRecipient, "bob@example.com", "carol@example.com"}
+	svc.cfg.EnvFallbackAllowlist = recipients
+	seedTemplate(t, tplSvc, "digest-email", "email")
+
+	def := &domain.NotificationDefinition{
+		Code:         "digest",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:digest-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList(recipients),
+	}
+	if !svc.supportsBatch("email", len(recipients)) {
+		t.Fatalf("expected batch path for a batch capable adapter")
+	}
+	jobs := make([]deliveryJob, 0, len(recipients))
+	for _, recipient := range recipients {
+		jobs = append(jobs, deliveryJob{event: event, channel: "email", templateCode: "digest-email", recipient: recipient, locale: "en"})
+	}
+	if errs := svc.processBatch(ctx, event, def, jobs); len(errs) != 0 {
+		t.Fatalf("expected all deliveries to succeed, got %v", errs)
+	}
+
+	if len(bulk.batches) != 1 || len(bulk.batches[0]) != 3 {
+		t.Fatalf("expected one batch of 3 messages, got %v", bulk.batches)
+	}
+	if bulk.Count() != 0 {
+		t.Fatalf("expected no single sends on the batch adapter, got %d", bulk.Count())
+	}
+	if backup.Count() != 1 || backup.sends[0].To != "bob@example.com" {
+		t.Fatalf("expected only the rejected recipient to fall back, got %v", backup.sends)
+	}
+
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	for _, msg := range list.Items {
+		attempts, err := svc.attempts.ListByMessage(ctx, msg.ID)
+		if err != nil {
+			t.Fatalf("list attempts: %v", err)
+		}
+		want := "bulk"
+		if msg.Receiver == "bob@example.com" {
+			want = "backup"
+			if len(attempts) != 2 || attempts[0].Status != domain.AttemptStatusFailed || attempts[0].Adapter != "bulk" {
+				t.Fatalf("expected failed bulk attempt then backup for bob, got %+v", attempts)
+			}
+		} else if len(attempts) != 1 || attempts[0].Status != domain.AttemptStatusSucceeded {
+			t.Fatalf("expected one successful attempt for %s, got %+v", msg.Receiver, attempts)
+		}
+		if msg.Status != domain.MessageStatusDelivered || msg.Metadata["provider"] != want {
+			t.Fatalf("expected %s delivered via %s, got status=%s provider=%v", msg.Receiver, want, msg.Status, msg.Metadata["provider"])
+		}
+	}
+}
+
+func TestUpdateDeliveryStatusFlipsDeliveredMessage(t *testing.T) {
+	ctx := context.Background()
+	svc, msgRepo, _ := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, &testAdapter{name: "twilio", channels: []string{"sms"}})
+	message := &domain.NotificationMessage{
+		RecordMeta: domain.RecordMeta{ID: uuid.New()},
+		Channel:    "sms",
+		Receiver:   testRecipient,
+		Status:     domain.MessageStatusDelivered,
+		Metadata:   domain.JSONMap{"provider": "twilio"},
+	}
+	if err := msgRepo.Create(ctx, message); err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+
+	sent := adapters.DeliveryStatusUpdate{MessageID: message.ID.String(), ProviderMessageID: "SM123", Status: "sent"}
+	if err := svc.UpdateDeliveryStatus(ctx, sent); err != nil {
+		t.Fatalf("update sent: %v", err)
+	}
+	stored, _ := msgRepo.GetByID(ctx, message.ID)
+	if stored.Status != domain.MessageStatusDelivered || stored.Metadata["provider_status"] != "sent" {
+		t.Fatalf("expected intermediate status to only update metadata, got %s %v", stored.Status, stored.Metadata)
+	}
+
+	undelivered := adapters.DeliveryStatusUpdate{
+		MessageID:         message.ID.String(),
+		ProviderMessageID: "SM123",
+		Status:            adapters.DeliveryStatusUndelivered,
+		Error:             "twilio error 30003",
+	}
+	if err := svc.UpdateDeliveryStatus(ctx, undelivered); err != nil {
+		t.Fatalf("update undelivered: %v", err)
+	}
+	stored, _ = msgRepo.GetByID(ctx, message.ID)
+	if stored.Status != domain.MessageStatusUndelivered || stored.Metadata["provider_message_id"] != "SM123" {
+		t.Fatalf("expected message flipped to undelivered, got %s %v", stored.Status, stored.Metadata)
+	}
+	attempts, err := svc.attempts.ListByMessage(ctx, message.ID)
+	if err != nil {
+		t.Fatalf("list attempts: %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Status != domain.AttemptStatusFailed || attempts[0].Adapter != "twilio" || attempts[0].Error != "twilio error 30003" {
+		t.Fatalf("expected a failed attempt from the report, got %+v", attempts)
+	}
+
+	if err := svc.UpdateDeliveryStatus(ctx, adapters.DeliveryStatusUpdate{MessageID: "not-a-uuid"}); err == nil {
+		t.Fatalf("expected invalid message id to fail")
+	}
+}
+
 func TestDispatcherPerChannelLinkBuilderUsesOverrides(t *testing.T) {
 	ctx := context.Background()
 	builder := &captureLinkBuilder{
@@ -365,6 +1199,223 @@ func TestDispatcherPerChannelLinkBuilderUsesOverrides(t *testing.T) {
 	}
 }
 
+func TestDispatcherLinkResolutionAttributesDelivery(t *testing.T) {
+	ctx := context.Background()
+	builder := &captureLinkBuilder{
+		buildFn: func(req links.LinkRequest) (links.ResolvedLinks, error) {
+			return links.ResolvedLinks{ActionURL: "builder-" + req.Channel}, nil
+		},
+	}
+	adapter := &testAdapter{name: "test", channels: []string{"email", "sms"}}
+	observer := &captureObserver{}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, builder, nil, observer, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "welcome-email", "email")
+	seedTemplate(t, tplSvc, "welcome-sms", "sms")
+
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email", "sms"},
+		TemplateKeys: domain.StringList{"email:welcome-email", "sms:welcome-sms"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+		Context:        domain.JSONMap{},
+	}
+	for _, channel := range []string{"email", "sms"} {
+		job := deliveryJob{event: event, channel: channel, templateCode: "welcome-" + channel, recipient: testRecipient, locale: "en"}
+		if err := svc.processDelivery(ctx, event, def, job); err != nil {
+			t.Fatalf("process %s delivery: %v", channel, err)
+		}
+	}
+
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	messageIDs := make(map[string]string, len(list.Items))
+	for _, msg := range list.Items {
+		messageIDs[msg.Channel] = msg.ID.String()
+	}
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.calls) != 2 {
+		t.Fatalf("expected 2 observer calls, got %d", len(observer.calls))
+	}
+	for _, info := range observer.calls {
+		if info.Channel != "email" && info.Channel != "sms" {
+			t.Fatalf("expected channel on resolution, got %+v", info)
+		}
+		if info.Recipient != testRecipient {
+			t.Fatalf("expected recipient %s, got %q", testRecipient, info.Recipient)
+		}
+		if info.MessageID == "" || info.MessageID != messageIDs[info.Channel] {
+			t.Fatalf("expected %s message id %s, got %q", info.Channel, messageIDs[info.Channel], info.MessageID)
+		}
+		if info.Resolved.ActionURL != "builder-"+info.Channel {
+			t.Fatalf("expected %s resolution, got %s", info.Channel, info.Resolved.ActionURL)
+		}
+	}
+	if observer.calls[0].Channel == observer.calls[1].Channel {
+		t.Fatalf("expected one resolution per channel, got %s twice", observer.calls[0].Channel)
+	}
+}
+
+func TestDispatchUsesBatchLinkBuilder(t *testing.T) {
+	ctx := context.Background()
+	builder := &batchLinkBuilder{}
+	adapter := &testAdapter{name: "test", channels: []string{"email", "sms"}}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, builder, nil, nil, links.FailurePolicy{}, adapter)
+	recipients := []string{testRecipient, "bob@example.com", "carol@example.com"}
+	svc.cfg.EnvFallbackAllowlist = recipients
+	seedTemplate(t, tplSvc, "welcome-email", "email")
+	seedTemplate(t, tplSvc, "welcome-sms", "sms")
+
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email", "sms"},
+		TemplateKeys: domain.StringList{"email:welcome-email", "sms:welcome-sms"},
+	}
+	if err := svc.definitions.Create(ctx, def); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList(recipients),
+		Context:        domain.JSONMap{},
+	}
+	if err := svc.Dispatch(ctx, event, DispatchOptions{Locale: "en"}); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	builder.mu.Lock()
+	if len(builder.calls) != 0 {
+		t.Fatalf("expected no per-delivery Build calls, got %d", len(builder.calls))
+	}
+	if len(builder.batches) != 1 || len(builder.batches[0]) != 6 {
+		t.Fatalf("expected one BuildMany call with 6 requests, got %+v", builder.batches)
+	}
+	builder.mu.Unlock()
+
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	if list.Total != 6 {
+		t.Fatalf("expected 6 messages, got %d", list.Total)
+	}
+	for _, msg := range list.Items {
+		if want := "signed-" + msg.Channel + "-" + msg.Receiver; msg.ActionURL != want {
+			t.Fatalf("expected %s, got %s", want, msg.ActionURL)
+		}
+	}
+}
+
+func TestDispatcherAssignsLinkTrackingTokens(t *testing.T) {
+	ctx := context.Background()
+	builder := &captureLinkBuilder{
+		buildFn: func(req links.LinkRequest) (links.ResolvedLinks, error) {
+			return links.ResolvedLinks{ActionURL: "https://example.com/action", ManifestURL: "https://example.com/manifest"}, nil
+		},
+	}
+	adapter := &testAdapter{name: "test", channels: []string{"email"}}
+	storeSpy := &captureStore{}
+	observer := &captureObserver{}
+	svc, _, tplSvc := newTestDispatcher(t, builder, storeSpy, observer, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "welcome-email", "email")
+
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:welcome-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+		Context:        domain.JSONMap{},
+	}
+	job := deliveryJob{event: event, channel: "email", templateCode: "welcome-email", recipient: testRecipient, locale: "en"}
+	if err := svc.processDelivery(ctx, event, def, job); err != nil {
+		t.Fatalf("process delivery: %v", err)
+	}
+
+	if len(storeSpy.records) != 1 || len(storeSpy.records[0]) != 2 {
+		t.Fatalf("expected 2 stored records, got %+v", storeSpy.records)
+	}
+	for _, record := range storeSpy.records[0] {
+		if record.Token == "" {
+			t.Fatalf("expected tracking token on %s", record.URL)
+		}
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.calls) != 1 {
+		t.Fatalf("expected observer call, got %d", len(observer.calls))
+	}
+	token := observer.calls[0].TrackingToken
+	record, err := storeSpy.RecordClick(ctx, token, nil)
+	if err != nil || record.URL != "https://example.com/action" {
+		t.Fatalf("expected tracking token to resolve action link, got %+v (%v)", record, err)
+	}
+}
+
+func TestDispatcherRendersLinkTrackingToken(t *testing.T) {
+	ctx := context.Background()
+	builder := &captureLinkBuilder{
+		buildFn: func(req links.LinkRequest) (links.ResolvedLinks, error) {
+			return links.ResolvedLinks{ActionURL: "https://example.com/action"}, nil
+		},
+	}
+	adapter := &testAdapter{name: "test", channels: []string{"email"}}
+	storeSpy := &captureStore{}
+	svc, msgRepo, tplSvc := newTestDispatcher(t, builder, storeSpy, nil, links.FailurePolicy{}, adapter)
+	if _, err := tplSvc.Create(ctx, templates.TemplateInput{
+		Code:    "welcome-email",
+		Channel: "email",
+		Locale:  "en",
+		Subject: "Subject",
+		Body:    "https://example.com/l/{{ tracking_token }}",
+		Format:  "text/plain",
+	}); err != nil {
+		t.Fatalf("seed template: %v", err)
+	}
+
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:welcome-email"},
+	}
+	event := &domain.NotificationEvent{
+		RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+		DefinitionCode: def.Code,
+		Recipients:     domain.StringList{testRecipient},
+		Context:        domain.JSONMap{},
+	}
+	job := deliveryJob{event: event, channel: "email", templateCode: "welcome-email", recipient: testRecipient, locale: "en"}
+	if err := svc.processDelivery(ctx, event, def, job); err != nil {
+		t.Fatalf("process delivery: %v", err)
+	}
+
+	if len(storeSpy.records) != 1 || len(storeSpy.records[0]) != 1 {
+		t.Fatalf("expected 1 stored record, got %+v", storeSpy.records)
+	}
+	token := storeSpy.records[0][0].Token
+	if len(adapter.sends) != 1 || adapter.sends[0].Body != "https://example.com/l/"+token {
+		t.Fatalf("expected rendered tracking token %q, got %+v", token, adapter.sends)
+	}
+	list, err := msgRepo.List(ctx, store.ListOptions{})
+	if err != nil || len(list.Items) != 1 {
+		t.Fatalf("expected stored message, got %+v (%v)", list, err)
+	}
+	if got := list.Items[0].Metadata[links.ResolvedTrackingTokenKey]; got != token {
+		t.Fatalf("expected tracking_token metadata %q, got %v", token, got)
+	}
+}
+
 func TestDispatcherLinkHooksErrorHandling(t *testing.T) {
 	t.Run("lenient store error continues", func(t *testing.T) {
 		ctx := context.Background()
@@ -647,3 +1698,68 @@ func newCatalog(locale string, entries map[string]string) *i18n.TranslationCatal
 	}
 	return catalog
 }
+
+type blockingAdapter struct {
+	testAdapter
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAdapter) Send(ctx context.Context, msg adapters.Message) error {
+	close(a.started)
+	<-a.release
+	return a.testAdapter.Send(ctx, msg)
+}
+
+func TestShutdownDrainsInFlightDispatches(t *testing.T) {
+	ctx := context.Background()
+	adapter := &blockingAdapter{
+		testAdapter: testAdapter{name: "test", channels: []string{"email"}},
+		started:     make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	svc, _, tplSvc := newTestDispatcher(t, nil, nil, nil, links.FailurePolicy{}, adapter)
+	seedTemplate(t, tplSvc, "welcome-email", "email")
+	def := &domain.NotificationDefinition{
+		Code:         "welcome",
+		Channels:     domain.StringList{"email"},
+		TemplateKeys: domain.StringList{"email:welcome-email"},
+	}
+	if err := svc.definitions.Create(ctx, def); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+	newEvent := func() *domain.NotificationEvent {
+		return &domain.NotificationEvent{
+			RecordMeta:     domain.RecordMeta{ID: uuid.New()},
+			DefinitionCode: def.Code,
+			Recipients:     domain.StringList{testRecipient},
+			Context:        domain.JSONMap{},
+		}
+	}
+
+	dispatched := make(chan error, 1)
+	go func() {
+		dispatched <- svc.Dispatch(ctx, newEvent(), DispatchOptions{Locale: "en"})
+	}()
+	<-adapter.started
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := svc.Shutdown(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected shutdown to time out while a send is in flight, got %v", err)
+	}
+	if err := svc.Dispatch(ctx, newEvent(), DispatchOptions{Locale: "en"}); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("expected ErrShuttingDown for new dispatch, got %v", err)
+	}
+
+	close(adapter.release)
+	if err := svc.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-dispatched; err != nil {
+		t.Fatalf("in-flight dispatch: %v", err)
+	}
+	if adapter.Count() != 1 {
+		t.Fatalf("expected the in-flight send to complete, got %d sends", adapter.Count())
+	}
+}
@@ -0,0 +1,61 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrShuttingDown is returned by Dispatch once Shutdown has been called.
+var ErrShuttingDown = errors.New("dispatcher: shutting down")
+
+// inflight tracks running Dispatch calls so Shutdown can wait for them.
+type inflight struct {
+	mu      sync.Mutex
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// begin registers a dispatch, or reports false once closing.
+func (f *inflight) begin() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closing {
+		return false
+	}
+	f.wg.Add(1)
+	return true
+}
+
+func (f *inflight) done() {
+	f.wg.Done()
+}
+
+// ShuttingDown reports whether Shutdown has been called.
+func (s *Service) ShuttingDown() bool {
+	s.inflight.mu.Lock()
+	defer s.inflight.mu.Unlock()
+	return s.inflight.closing
+}
+
+// Shutdown stops the dispatcher from accepting new events and waits for
+// in-flight dispatches to finish. It returns ctx.Err() if ctx ends first;
+// the remaining dispatches keep running and new ones stay rejected. Calling
+// it again waits again.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.inflight.mu.Lock()
+	s.inflight.closing = true
+	s.inflight.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.inflight.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
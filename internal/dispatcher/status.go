@@ -0,0 +1,65 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/domain"
+	"github.com/google/uuid"
+)
+
+var _ adapters.DeliveryStatusUpdater = (*Service)(nil)
+
+// UpdateDeliveryStatus applies an asynchronous provider report to the stored
+// message. Failed and undelivered reports flip a delivered message and are
+// recorded as a failed attempt; intermediate states only update metadata.
+func (s *Service) UpdateDeliveryStatus(ctx context.Context, update adapters.DeliveryStatusUpdate) error {
+	if s.messages == nil {
+		return errors.New("dispatcher: message repository not configured")
+	}
+	id, err := uuid.Parse(update.MessageID)
+	if err != nil {
+		return fmt.Errorf("dispatcher: delivery status message id: %w", err)
+	}
+	message, err := s.messages.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("dispatcher: load message: %w", err)
+	}
+
+	if message.Metadata == nil {
+		message.Metadata = make(domain.JSONMap)
+	}
+	message.Metadata["provider_status"] = update.Status
+	if update.ProviderMessageID != "" {
+		message.Metadata["provider_message_id"] = update.ProviderMessageID
+	}
+	if update.Error != "" {
+		message.Metadata["provider_error"] = update.Error
+	}
+
+	attemptStatus := ""
+	switch update.Status {
+	case adapters.DeliveryStatusDelivered:
+		message.Status = domain.MessageStatusDelivered
+		attemptStatus = domain.AttemptStatusSucceeded
+	case adapters.DeliveryStatusFailed:
+		message.Status = domain.MessageStatusFailed
+		attemptStatus = domain.AttemptStatusFailed
+	case adapters.DeliveryStatusUndelivered:
+		message.Status = domain.MessageStatusUndelivered
+		attemptStatus = domain.AttemptStatusFailed
+	}
+	if err := s.messages.Update(ctx, message); err != nil {
+		return fmt.Errorf("dispatcher: update message: %w", err)
+	}
+	if attemptStatus != "" {
+		provider := update.Provider
+		if provider == "" {
+			provider, _ = message.Metadata["provider"].(string)
+		}
+		_ = s.recordAttempt(ctx, provider, message, attemptStatus, update.Error, 0)
+	}
+	return nil
+}
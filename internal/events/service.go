@@ -58,6 +58,7 @@ type Dependencies struct {
 
 type dispatcherInterface interface {
 	Dispatch(ctx context.Context, event *domain.NotificationEvent, opts dispatcher.DispatchOptions) error
+	ShuttingDown() bool
 }
 
 // Service accepts inbound events, validates them, and schedules work.
@@ -147,6 +148,9 @@ func (s *Service) ProcessDigest(ctx context.Context, payload DigestJobPayload) e
 }
 
 func (s *Service) dispatchNow(ctx context.Context, req IntakeRequest) error {
+	if s.dispatcher.ShuttingDown() {
+		return dispatcher.ErrShuttingDown
+	}
 	record := &domain.NotificationEvent{
 		DefinitionCode: req.DefinitionCode,
 		TenantID:       req.TenantID,
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
 	"github.com/goliatone/go-notifications/pkg/interfaces/queue"
+	"github.com/goliatone/go-notifications/pkg/interfaces/store"
 )
 
 func TestEnqueueImmediateDispatch(t *testing.T) {
@@ -30,6 +32,27 @@ func TestEnqueueImmediateDispatch(t *testing.T) {
 	}
 }
 
+func TestEnqueueRejectedWhileShuttingDown(t *testing.T) {
+	ctx := context.Background()
+	defRepo, evtRepo, disp, q := setupDeps(t)
+	service := newTestService(t, defRepo, evtRepo, disp, q)
+	disp.shuttingDown = true
+
+	err := service.Enqueue(ctx, IntakeRequest{
+		DefinitionCode: "welcome",
+		Recipients:     []string{"user@example.com"},
+	})
+	if !errors.Is(err, dispatcher.ErrShuttingDown) {
+		t.Fatalf("expected ErrShuttingDown, got %v", err)
+	}
+	if len(disp.events) != 0 {
+		t.Fatalf("expected no dispatch, got %d", len(disp.events))
+	}
+	if events, _ := evtRepo.List(ctx, store.ListOptions{}); events.Total != 0 {
+		t.Fatalf("expected no event persisted, got %d", events.Total)
+	}
+}
+
 func TestEnqueueSchedulesFutureJob(t *testing.T) {
 	ctx := context.Background()
 	defRepo, evtRepo, disp, q := setupDeps(t)
@@ -119,9 +142,12 @@ func newTestService(t *testing.T, defRepo *memory.DefinitionRepository, evtRepo
 }
 
 type stubDispatcher struct {
-	events []*domain.NotificationEvent
+	events       []*domain.NotificationEvent
+	shuttingDown bool
 }
 
+func (s *stubDispatcher) ShuttingDown() bool { return s.shuttingDown }
+
 func (s *stubDispatcher) Dispatch(ctx context.Context, event *domain.NotificationEvent, opts dispatcher.DispatchOptions) error {
 	s.events = append(s.events, event)
 	return nil
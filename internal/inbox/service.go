@@ -3,6 +3,7 @@ package inbox
 import (
 	"context"
 	"errors"
+	"fmt"
 	"maps"
 	"strings"
 	"time"
@@ -25,15 +26,24 @@ type CreateInput struct {
 	ActionURL string
 	Pinned    bool
 	Metadata  domain.JSONMap
+	Tags      []string
+	// LinkExpiresAt is when ActionURL stops working; zero when it does not expire.
+	LinkExpiresAt time.Time
 }
 
 // ListFilters allow callers to refine mailbox queries.
 type ListFilters struct {
 	UnreadOnly       bool
 	IncludeDismissed bool
+	IncludeArchived  bool
+	ArchivedOnly     bool
 	PinnedOnly       bool
 	SnoozedOnly      bool
 	Before           time.Time
+	// Tags restricts results to items carrying any of the listed tags.
+	Tags []string
+	// FlagExpiredLinks sets LinkExpired on items whose action link expired.
+	FlagExpiredLinks bool
 }
 
 // Dependencies wires repositories and realtime hooks into the service.
@@ -42,6 +52,8 @@ type Dependencies struct {
 	Broadcaster broadcaster.Broadcaster
 	Logger      logger.Logger
 	Activity    activity.Hooks
+	// MaxPinned caps pinned items per user; zero disables the limit.
+	MaxPinned int
 }
 
 // Service manages inbox CRUD and realtime fan-out.
@@ -50,12 +62,35 @@ type Service struct {
 	broadcaster broadcaster.Broadcaster
 	logger      logger.Logger
 	activity    activity.Hooks
+	maxPinned   int
 }
 
 var (
 	errRepositoryRequired = errors.New("inbox: repository is required")
+
+	// releaseBatchSize bounds how many snoozed items ReleaseDue loads per query.
+	releaseBatchSize = 100
+
+	// ErrPinLimitExceeded is returned (wrapped in PinLimitError) when pinning
+	// would push a user past the configured pin limit.
+	ErrPinLimitExceeded = errors.New("inbox: pin limit exceeded")
 )
 
+// PinLimitError reports the configured pin limit alongside the current count.
+type PinLimitError struct {
+	Limit   int
+	Current int
+}
+
+func (e PinLimitError) Error() string {
+	return fmt.Sprintf("inbox: pin limit exceeded (limit %d, pinned %d)", e.Limit, e.Current)
+}
+
+// Unwrap allows errors.Is(err, ErrPinLimitExceeded).
+func (e PinLimitError) Unwrap() error {
+	return ErrPinLimitExceeded
+}
+
 // NewService constructs the inbox service.
 func NewService(deps Dependencies) (*Service, error) {
 	if deps.Repository == nil {
@@ -72,25 +107,38 @@ func NewService(deps Dependencies) (*Service, error) {
 		broadcaster: deps.Broadcaster,
 		logger:      deps.Logger,
 		activity:    deps.Activity,
+		maxPinned:   max(deps.MaxPinned, 0),
 	}, nil
 }
 
+// PinLimit returns the maximum number of pinned items per user (0 = unlimited).
+func (s *Service) PinLimit() int {
+	return s.maxPinned
+}
+
 // Create inserts a new inbox entry.
 func (s *Service) Create(ctx context.Context, input CreateInput) (*domain.InboxItem, error) {
 	if err := validateCreateInput(input); err != nil {
 		return nil, err
 	}
+	if input.Pinned {
+		if err := s.ensurePinCapacity(ctx, strings.TrimSpace(input.UserID), 1); err != nil {
+			return nil, err
+		}
+	}
 	item := &domain.InboxItem{
-		UserID:       strings.TrimSpace(input.UserID),
-		MessageID:    input.MessageID,
-		Title:        input.Title,
-		Body:         input.Body,
-		Locale:       input.Locale,
-		ActionURL:    input.ActionURL,
-		Metadata:     cloneJSON(input.Metadata),
-		Unread:       true,
-		Pinned:       input.Pinned,
-		SnoozedUntil: time.Time{},
+		UserID:        strings.TrimSpace(input.UserID),
+		MessageID:     input.MessageID,
+		Title:         input.Title,
+		Body:          input.Body,
+		Locale:        input.Locale,
+		ActionURL:     input.ActionURL,
+		Metadata:      cloneJSON(input.Metadata),
+		Tags:          NormalizeTags(input.Tags),
+		Unread:        true,
+		Pinned:        input.Pinned,
+		SnoozedUntil:  time.Time{},
+		LinkExpiresAt: input.LinkExpiresAt,
 	}
 	if err := s.repo.Create(ctx, item); err != nil {
 		return nil, err
@@ -113,15 +161,32 @@ func (s *Service) Create(ctx context.Context, input CreateInput) (*domain.InboxI
 
 // List returns inbox items for the given user applying the supplied filters.
 func (s *Service) List(ctx context.Context, userID string, opts store.ListOptions, filters ListFilters) (store.ListResult[domain.InboxItem], error) {
-	result, err := s.repo.ListByUser(ctx, strings.TrimSpace(userID), opts)
+	userID = strings.TrimSpace(userID)
+	var (
+		result store.ListResult[domain.InboxItem]
+		err    error
+	)
+	if tags := NormalizeTags(filters.Tags); len(tags) > 0 {
+		result, err = s.repo.ListByUserTags(ctx, userID, tags, opts)
+	} else {
+		result, err = s.repo.ListByUser(ctx, userID, opts)
+	}
 	if err != nil {
 		return store.ListResult[domain.InboxItem]{}, err
 	}
+	now := time.Now().UTC()
 	items := make([]domain.InboxItem, 0, len(result.Items))
 	for _, item := range result.Items {
 		if !filters.IncludeDismissed && !item.DismissedAt.IsZero() {
 			continue
 		}
+		archived := !item.ArchivedAt.IsZero()
+		if filters.ArchivedOnly && !archived {
+			continue
+		}
+		if archived && !filters.IncludeArchived && !filters.ArchivedOnly {
+			continue
+		}
 		if filters.UnreadOnly && !item.Unread {
 			continue
 		}
@@ -134,13 +199,17 @@ func (s *Service) List(ctx context.Context, userID string, opts store.ListOption
 		if filters.SnoozedOnly && item.SnoozedUntil.IsZero() {
 			continue
 		}
+		if filters.FlagExpiredLinks {
+			item.LinkExpired = !item.LinkExpiresAt.IsZero() && !now.Before(item.LinkExpiresAt)
+		}
 		items = append(items, item)
 	}
 	return store.ListResult[domain.InboxItem]{Items: items, Total: len(items)}, nil
 }
 
 // MarkRead toggles the unread flag for the provided items. IDs that do not
-// belong to the user are ignored to avoid leaking existence checks.
+// belong to the user are ignored to avoid leaking existence checks. The first
+// read transition is preserved in ReadAt; later reads only move LastReadAt.
 func (s *Service) MarkRead(ctx context.Context, userID string, ids []uuid.UUID, read bool) error {
 	userID = strings.TrimSpace(userID)
 	for _, id := range ids {
@@ -151,13 +220,17 @@ func (s *Service) MarkRead(ctx context.Context, userID string, ids []uuid.UUID,
 		if item.UserID != userID {
 			continue
 		}
+		firstRead := read && item.ReadAt.IsZero()
 		if err := s.repo.MarkRead(ctx, id, read); err != nil {
 			return err
 		}
+		item.Unread = !read
 		s.emit(ctx, "inbox.updated", item)
 		verb := "notification.unread"
+		var metadata map[string]any
 		if read {
 			verb = "notification.read"
+			metadata = map[string]any{"first_read": firstRead}
 		}
 		s.activity.Notify(ctx, activity.Event{
 			Verb:       verb,
@@ -165,6 +238,7 @@ func (s *Service) MarkRead(ctx context.Context, userID string, ids []uuid.UUID,
 			UserID:     item.UserID,
 			ObjectType: "inbox_item",
 			ObjectID:   item.ID.String(),
+			Metadata:   metadata,
 		})
 	}
 	return nil
@@ -197,6 +271,72 @@ func (s *Service) Snooze(ctx context.Context, userID string, id uuid.UUID, until
 	return nil
 }
 
+// ReleaseDue wakes every item whose snooze expired at or before now: the
+// snooze is cleared, the item is marked unread again, and an inbox.updated
+// event is broadcast. Dismissed and archived items stay as they are. It
+// returns the number of released items.
+func (s *Service) ReleaseDue(ctx context.Context, now time.Time) (int, error) {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+	released := 0
+	for {
+		due, err := s.repo.ListSnoozedBefore(ctx, now, releaseBatchSize)
+		if err != nil {
+			return released, err
+		}
+		batch := released
+		for i := range due {
+			item := &due[i]
+			ok, err := s.repo.ReleaseSnooze(ctx, item.ID, now)
+			if err != nil {
+				return released, err
+			}
+			if !ok {
+				continue
+			}
+			item.SnoozedUntil = time.Time{}
+			item.Unread = true
+			released++
+			s.emit(ctx, "inbox.updated", item)
+			s.activity.Notify(ctx, activity.Event{
+				Verb:       "notification.snooze_released",
+				UserID:     item.UserID,
+				ObjectType: "inbox_item",
+				ObjectID:   item.ID.String(),
+			})
+		}
+		// Stop when nothing in a full batch could be released (items changed
+		// concurrently) instead of fetching the same batch again.
+		if len(due) < releaseBatchSize || released == batch {
+			return released, nil
+		}
+	}
+}
+
+// RunReleaser invokes ReleaseDue on every tick of interval until ctx is
+// cancelled. It blocks, so callers typically run it in a goroutine.
+func (s *Service) RunReleaser(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if count, err := s.ReleaseDue(ctx, now); err != nil {
+				s.logger.Warn("inbox snooze release failed", "error", err)
+			} else if count > 0 {
+				s.logger.Debug("inbox snoozed items released", "count", count)
+			}
+		}
+	}
+}
+
 // Dismiss marks an inbox item as dismissed and clears the unread flag.
 func (s *Service) Dismiss(ctx context.Context, userID string, id uuid.UUID) error {
 	item, err := s.repo.GetByID(ctx, id)
@@ -222,6 +362,224 @@ func (s *Service) Dismiss(ctx context.Context, userID string, id uuid.UUID) erro
 	return nil
 }
 
+// Archive moves an inbox item out of the active list without touching its
+// unread or dismissed state.
+func (s *Service) Archive(ctx context.Context, userID string, id uuid.UUID) error {
+	return s.setArchived(ctx, userID, id, true)
+}
+
+// Unarchive restores an archived inbox item to the active list.
+func (s *Service) Unarchive(ctx context.Context, userID string, id uuid.UUID) error {
+	return s.setArchived(ctx, userID, id, false)
+}
+
+func (s *Service) setArchived(ctx context.Context, userID string, id uuid.UUID, archived bool) error {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item.UserID != strings.TrimSpace(userID) {
+		return nil
+	}
+	if err := s.repo.SetArchived(ctx, id, archived); err != nil {
+		return err
+	}
+	verb := "notification.unarchived"
+	if archived {
+		item.ArchivedAt = time.Now().UTC()
+		verb = "notification.archived"
+	} else {
+		item.ArchivedAt = time.Time{}
+	}
+	s.emit(ctx, "inbox.updated", item)
+	s.activity.Notify(ctx, activity.Event{
+		Verb:       verb,
+		ActorID:    userID,
+		UserID:     item.UserID,
+		ObjectType: "inbox_item",
+		ObjectID:   item.ID.String(),
+	})
+	return nil
+}
+
+// SetPinned pins or unpins a single inbox item, enforcing the pin limit.
+func (s *Service) SetPinned(ctx context.Context, userID string, id uuid.UUID, pinned bool) error {
+	userID = strings.TrimSpace(userID)
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item.UserID != userID {
+		return nil
+	}
+	if pinned && !item.Pinned {
+		if err := s.ensurePinCapacity(ctx, userID, 1); err != nil {
+			return err
+		}
+	}
+	item.Pinned = pinned
+	if err := s.repo.Update(ctx, item); err != nil {
+		return err
+	}
+	s.emit(ctx, "inbox.updated", item)
+	verb := "notification.unpinned"
+	if pinned {
+		verb = "notification.pinned"
+	}
+	s.activity.Notify(ctx, activity.Event{
+		Verb:       verb,
+		ActorID:    userID,
+		UserID:     item.UserID,
+		ObjectType: "inbox_item",
+		ObjectID:   item.ID.String(),
+	})
+	return nil
+}
+
+// BulkDismiss dismisses every provided item owned by the user and emits a
+// single summary broadcast instead of one event per item.
+func (s *Service) BulkDismiss(ctx context.Context, userID string, ids []uuid.UUID) error {
+	userID = strings.TrimSpace(userID)
+	affected, err := s.bulkApply(ctx, userID, ids, func(item *domain.InboxItem) error {
+		if err := s.repo.Dismiss(ctx, item.ID); err != nil {
+			return err
+		}
+		s.activity.Notify(ctx, activity.Event{
+			Verb:       "notification.dismissed",
+			ActorID:    userID,
+			UserID:     item.UserID,
+			ObjectType: "inbox_item",
+			ObjectID:   item.ID.String(),
+		})
+		return nil
+	})
+	s.emitBulk(ctx, userID, "dismissed", affected, nil)
+	return err
+}
+
+// BulkSnooze defers every provided item owned by the user until the timestamp.
+func (s *Service) BulkSnooze(ctx context.Context, userID string, ids []uuid.UUID, until time.Time) error {
+	userID = strings.TrimSpace(userID)
+	affected, err := s.bulkApply(ctx, userID, ids, func(item *domain.InboxItem) error {
+		if err := s.repo.Snooze(ctx, item.ID, until); err != nil {
+			return err
+		}
+		s.activity.Notify(ctx, activity.Event{
+			Verb:       "notification.snoozed",
+			ActorID:    userID,
+			UserID:     item.UserID,
+			ObjectType: "inbox_item",
+			ObjectID:   item.ID.String(),
+			Metadata: map[string]any{
+				"until": until,
+			},
+		})
+		return nil
+	})
+	s.emitBulk(ctx, userID, "snoozed", affected, map[string]any{"snoozed_until": until})
+	return err
+}
+
+// BulkSetPinned pins or unpins every provided item owned by the user. When a
+// pin limit is configured the whole call is rejected with PinLimitError if it
+// would push the user over the limit.
+func (s *Service) BulkSetPinned(ctx context.Context, userID string, ids []uuid.UUID, pinned bool) error {
+	userID = strings.TrimSpace(userID)
+	if pinned && s.maxPinned > 0 {
+		additional, err := s.countNewPins(ctx, userID, ids)
+		if err != nil {
+			return err
+		}
+		if err := s.ensurePinCapacity(ctx, userID, additional); err != nil {
+			return err
+		}
+	}
+	affected, err := s.bulkApply(ctx, userID, ids, func(item *domain.InboxItem) error {
+		item.Pinned = pinned
+		if err := s.repo.Update(ctx, item); err != nil {
+			return err
+		}
+		verb := "notification.unpinned"
+		if pinned {
+			verb = "notification.pinned"
+		}
+		s.activity.Notify(ctx, activity.Event{
+			Verb:       verb,
+			ActorID:    userID,
+			UserID:     item.UserID,
+			ObjectType: "inbox_item",
+			ObjectID:   item.ID.String(),
+		})
+		return nil
+	})
+	action := "unpinned"
+	if pinned {
+		action = "pinned"
+	}
+	s.emitBulk(ctx, userID, action, affected, map[string]any{"pinned": pinned})
+	return err
+}
+
+func (s *Service) countNewPins(ctx context.Context, userID string, ids []uuid.UUID) (int, error) {
+	count := 0
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		item, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if item.UserID != userID || item.Pinned {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (s *Service) ensurePinCapacity(ctx context.Context, userID string, additional int) error {
+	if s.maxPinned <= 0 || additional <= 0 {
+		return nil
+	}
+	current, err := s.repo.CountPinned(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if current+additional > s.maxPinned {
+		return PinLimitError{Limit: s.maxPinned, Current: current}
+	}
+	return nil
+}
+
+// bulkApply loads each item, skips the ones not owned by the user, and runs fn
+// on the rest. It returns the IDs that were mutated before any error occurred
+// so callers can still broadcast partial progress.
+func (s *Service) bulkApply(ctx context.Context, userID string, ids []uuid.UUID, fn func(item *domain.InboxItem) error) ([]uuid.UUID, error) {
+	affected := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		item, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return affected, err
+		}
+		if item.UserID != userID {
+			continue
+		}
+		if err := fn(item); err != nil {
+			return affected, err
+		}
+		affected = append(affected, id)
+	}
+	return affected, nil
+}
+
 // BadgeCount returns the unread count for the given user.
 func (s *Service) BadgeCount(ctx context.Context, userID string) (int, error) {
 	return s.repo.CountUnread(ctx, strings.TrimSpace(userID))
@@ -233,11 +591,13 @@ func (s *Service) DeliverFromMessage(ctx context.Context, msg *domain.Notificati
 		return errors.New("inbox: message is required")
 	}
 	input := CreateInput{
-		UserID:    msg.Receiver,
-		MessageID: msg.ID,
-		Title:     msg.Subject,
-		Body:      msg.Body,
-		Locale:    msg.Locale,
+		UserID:        msg.Receiver,
+		MessageID:     msg.ID,
+		Title:         msg.Subject,
+		Body:          msg.Body,
+		Locale:        msg.Locale,
+		Tags:          TagsFromValue(msg.Metadata["tags"]),
+		LinkExpiresAt: msg.LinkExpiresAt,
 	}
 	if msg.ActionURL != "" {
 		input.ActionURL = msg.ActionURL
@@ -264,6 +624,7 @@ func (s *Service) emit(ctx context.Context, topic string, item *domain.InboxItem
 			"title":      item.Title,
 			"unread":     item.Unread,
 			"dismissed":  !item.DismissedAt.IsZero(),
+			"archived":   !item.ArchivedAt.IsZero(),
 			"snoozed_at": item.SnoozedUntil,
 		},
 	}
@@ -272,6 +633,30 @@ func (s *Service) emit(ctx context.Context, topic string, item *domain.InboxItem
 	}
 }
 
+func (s *Service) emitBulk(ctx context.Context, userID, action string, ids []uuid.UUID, extra map[string]any) {
+	if len(ids) == 0 {
+		return
+	}
+	idList := make([]string, len(ids))
+	for i, id := range ids {
+		idList[i] = id.String()
+	}
+	data := map[string]any{
+		"user_id": userID,
+		"action":  action,
+		"ids":     idList,
+		"count":   len(idList),
+	}
+	maps.Copy(data, extra)
+	payload := broadcaster.Event{
+		Topic:   "inbox.bulk_updated",
+		Payload: data,
+	}
+	if err := s.broadcaster.Broadcast(ctx, payload); err != nil {
+		s.logger.Warn("broadcast inbox bulk event failed", "error", err)
+	}
+}
+
 func validateCreateInput(input CreateInput) error {
 	if strings.TrimSpace(input.UserID) == "" {
 		return errors.New("inbox: user_id is required")
@@ -285,6 +670,56 @@ func validateCreateInput(input CreateInput) error {
 	return nil
 }
 
+// TagsFromValue converts loosely typed tag payloads ([]string, []any, a
+// comma-separated string) into a normalized tag list.
+func TagsFromValue(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return NormalizeTags(v)
+	case domain.StringList:
+		return NormalizeTags(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, entry := range v {
+			if str, ok := entry.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return NormalizeTags(out)
+	case string:
+		return NormalizeTags(strings.Split(v, ","))
+	default:
+		return nil
+	}
+}
+
+// NormalizeTags trims tags and drops empty and duplicate entries, keeping the
+// first occurrence order. It returns nil when no tag remains.
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
 func cloneJSON(src domain.JSONMap) domain.JSONMap {
 	if len(src) == 0 {
 		return nil
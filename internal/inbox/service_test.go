@@ -2,11 +2,13 @@ package inbox
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/goliatone/go-notifications/internal/storage/memory"
+	"github.com/goliatone/go-notifications/pkg/activity"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/broadcaster"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
@@ -76,6 +78,50 @@ func TestServiceMarkReadSnoozeDismiss(t *testing.T) {
 	}
 }
 
+func TestServiceListFiltersByTags(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	svc := newTestService(t, repo, captureBroadcaster())
+
+	inputs := []CreateInput{
+		{UserID: "user-10", Title: "Invoice", Body: "Body", Tags: []string{"billing"}},
+		{UserID: "user-10", Title: "Mention", Body: "Body", Tags: []string{"social", "mentions"}},
+		{UserID: "user-10", Title: "Plain", Body: "Body"},
+		{UserID: "user-11", Title: "Other", Body: "Body", Tags: []string{"billing"}},
+	}
+	for _, input := range inputs {
+		if _, err := svc.Create(ctx, input); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	result, err := svc.List(ctx, "user-10", storeOpts(), ListFilters{Tags: []string{"billing", "mentions"}})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected 2 tagged items, got %d", result.Total)
+	}
+
+	msg := &domain.NotificationMessage{
+		RecordMeta: domain.RecordMeta{ID: uuid.New()},
+		Receiver:   "user-10",
+		Subject:    "Tagged",
+		Body:       "Body",
+		Metadata:   domain.JSONMap{"tags": []any{"billing", " "}},
+	}
+	if err := svc.DeliverFromMessage(ctx, msg); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	result, err = svc.List(ctx, "user-10", storeOpts(), ListFilters{Tags: []string{"billing"}})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected delivered message tags to be stored, got %d billing items", result.Total)
+	}
+}
+
 func TestDeliverFromMessage(t *testing.T) {
 	ctx := context.Background()
 	repo := memory.NewInboxRepository()
@@ -94,6 +140,456 @@ func TestDeliverFromMessage(t *testing.T) {
 	}
 }
 
+func TestServiceListFlagsExpiredLinks(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	svc := newTestService(t, repo, captureBroadcaster())
+
+	expired := &domain.NotificationMessage{
+		RecordMeta:    domain.RecordMeta{ID: uuid.New()},
+		Receiver:      "user-4",
+		Subject:       "Reset",
+		Body:          "Reset your password",
+		ActionURL:     "https://example.com/reset/old",
+		LinkExpiresAt: time.Now().Add(-time.Minute),
+	}
+	live := &domain.NotificationMessage{
+		RecordMeta:    domain.RecordMeta{ID: uuid.New()},
+		Receiver:      "user-4",
+		Subject:       "Reset",
+		Body:          "Reset your password",
+		ActionURL:     "https://example.com/reset/new",
+		LinkExpiresAt: time.Now().Add(time.Hour),
+	}
+	for _, msg := range []*domain.NotificationMessage{expired, live} {
+		if err := svc.DeliverFromMessage(ctx, msg); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+
+	result, err := svc.List(ctx, "user-4", storeOpts(), ListFilters{FlagExpiredLinks: true})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected 2 items, got %d", result.Total)
+	}
+	for _, item := range result.Items {
+		if item.LinkExpiresAt.IsZero() {
+			t.Fatalf("expected link expiry copied from message, got %+v", item)
+		}
+		want := item.ActionURL == expired.ActionURL
+		if item.LinkExpired != want {
+			t.Fatalf("expected LinkExpired=%v for %s", want, item.ActionURL)
+		}
+	}
+
+	unflagged, err := svc.List(ctx, "user-4", storeOpts(), ListFilters{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, item := range unflagged.Items {
+		if item.LinkExpired {
+			t.Fatalf("expected no flag without FlagExpiredLinks, got %+v", item)
+		}
+	}
+}
+
+func TestServiceArchiveIndependentOfDismiss(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	svc := newTestService(t, repo, captureBroadcaster())
+
+	item, err := svc.Create(ctx, CreateInput{UserID: "user-6", Title: "Later", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.Archive(ctx, "user-6", item.ID); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	stored, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.Unread || !stored.DismissedAt.IsZero() || stored.ArchivedAt.IsZero() {
+		t.Fatalf("expected archived unread item, got %+v", stored)
+	}
+
+	active, err := svc.List(ctx, "user-6", storeOpts(), ListFilters{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if active.Total != 0 {
+		t.Fatalf("expected archived item hidden from default list, got %d", active.Total)
+	}
+	archived, err := svc.List(ctx, "user-6", storeOpts(), ListFilters{ArchivedOnly: true})
+	if err != nil {
+		t.Fatalf("list archived: %v", err)
+	}
+	if archived.Total != 1 {
+		t.Fatalf("expected 1 archived item, got %d", archived.Total)
+	}
+
+	if err := svc.Unarchive(ctx, "user-6", item.ID); err != nil {
+		t.Fatalf("unarchive: %v", err)
+	}
+	active, err = svc.List(ctx, "user-6", storeOpts(), ListFilters{UnreadOnly: true})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if active.Total != 1 {
+		t.Fatalf("expected unarchived item back in unread list, got %d", active.Total)
+	}
+}
+
+func TestServiceReleaseDue(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	events := captureBroadcaster()
+	svc := newTestService(t, repo, events)
+
+	now := time.Now().UTC()
+	due, err := svc.Create(ctx, CreateInput{UserID: "user-8", Title: "Due", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	later, err := svc.Create(ctx, CreateInput{UserID: "user-8", Title: "Later", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.MarkRead(ctx, "user-8", []uuid.UUID{due.ID}, true); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := svc.Snooze(ctx, "user-8", due.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("snooze due: %v", err)
+	}
+	if err := svc.Snooze(ctx, "user-8", later.ID, now.Add(time.Hour)); err != nil {
+		t.Fatalf("snooze later: %v", err)
+	}
+	events.events = nil
+
+	released, err := svc.ReleaseDue(ctx, now)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("expected 1 released item, got %d", released)
+	}
+	stored, err := repo.GetByID(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.SnoozedUntil.IsZero() || !stored.Unread {
+		t.Fatalf("expected snooze cleared and unread restored, got %+v", stored)
+	}
+	pending, err := repo.GetByID(ctx, later.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if pending.SnoozedUntil.IsZero() {
+		t.Fatalf("expected future snooze to remain")
+	}
+	if len(events.events) != 1 || events.events[0].Topic != "inbox.updated" {
+		t.Fatalf("expected one inbox.updated broadcast, got %+v", events.events)
+	}
+}
+
+func TestServiceReleaseDueKeepsLaterSnooze(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+	repo := &resnoozingRepo{InboxRepository: memory.NewInboxRepository(), until: later}
+	events := captureBroadcaster()
+	svc, err := NewService(Dependencies{Repository: repo, Broadcaster: events, Logger: &logger.Nop{}})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	item, err := svc.Create(ctx, CreateInput{UserID: "user-10", Title: "Resnoozed", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := svc.MarkRead(ctx, "user-10", []uuid.UUID{item.ID}, true); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := svc.Snooze(ctx, "user-10", item.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("snooze: %v", err)
+	}
+	events.events = nil
+
+	released, err := svc.ReleaseDue(ctx, now)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if released != 0 || len(events.events) != 0 {
+		t.Fatalf("expected nothing released, got %d (%+v)", released, events.events)
+	}
+	stored, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.SnoozedUntil.Equal(later) || stored.Unread {
+		t.Fatalf("expected later snooze kept and item still read, got %+v", stored)
+	}
+}
+
+func TestServiceReleaseDueSkipsDismissedAndArchived(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	events := captureBroadcaster()
+	svc := newTestService(t, repo, events)
+
+	now := time.Now().UTC()
+	dismissed, err := svc.Create(ctx, CreateInput{UserID: "user-9", Title: "Dismissed", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	archived, err := svc.Create(ctx, CreateInput{UserID: "user-9", Title: "Archived", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, id := range []uuid.UUID{dismissed.ID, archived.ID} {
+		if err := svc.Snooze(ctx, "user-9", id, now.Add(-time.Minute)); err != nil {
+			t.Fatalf("snooze: %v", err)
+		}
+	}
+	if err := repo.Dismiss(ctx, dismissed.ID); err != nil {
+		t.Fatalf("dismiss: %v", err)
+	}
+	if err := repo.SetArchived(ctx, archived.ID, true); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	events.events = nil
+
+	released, err := svc.ReleaseDue(ctx, now)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if released != 0 || len(events.events) != 0 {
+		t.Fatalf("expected nothing released, got %d (%+v)", released, events.events)
+	}
+	for _, id := range []uuid.UUID{dismissed.ID, archived.ID} {
+		stored, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if stored.SnoozedUntil.IsZero() {
+			t.Fatalf("expected %s to keep its snooze, got %+v", stored.Title, stored)
+		}
+	}
+}
+
+func TestServiceMarkReadPreservesFirstRead(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	hook := &captureHook{}
+	svc, err := NewService(Dependencies{
+		Repository: repo,
+		Logger:     &logger.Nop{},
+		Activity:   activity.Hooks{hook},
+	})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	item, err := svc.Create(ctx, CreateInput{UserID: "user-9", Title: "Read me", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := svc.MarkRead(ctx, "user-9", []uuid.UUID{item.ID}, true); err != nil {
+		t.Fatalf("first read: %v", err)
+	}
+	first, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if first.ReadAt.IsZero() || !first.LastReadAt.Equal(first.ReadAt) {
+		t.Fatalf("expected ReadAt and LastReadAt set on first read, got %+v", first)
+	}
+
+	if err := svc.MarkRead(ctx, "user-9", []uuid.UUID{item.ID}, false); err != nil {
+		t.Fatalf("unread: %v", err)
+	}
+	unread, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !unread.Unread || !unread.ReadAt.Equal(first.ReadAt) {
+		t.Fatalf("expected ReadAt preserved while unread, got %+v", unread)
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := svc.MarkRead(ctx, "user-9", []uuid.UUID{item.ID}, true); err != nil {
+		t.Fatalf("re-read: %v", err)
+	}
+	reread, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !reread.ReadAt.Equal(first.ReadAt) {
+		t.Fatalf("expected ReadAt to remain %v, got %v", first.ReadAt, reread.ReadAt)
+	}
+	if !reread.LastReadAt.After(first.LastReadAt) {
+		t.Fatalf("expected LastReadAt to advance, got %v", reread.LastReadAt)
+	}
+
+	var reads []bool
+	for _, evt := range hook.events {
+		if evt.Verb == "notification.read" {
+			reads = append(reads, evt.Metadata["first_read"].(bool))
+		}
+	}
+	if len(reads) != 2 || !reads[0] || reads[1] {
+		t.Fatalf("expected first_read=true then false, got %v", reads)
+	}
+}
+
+func TestServiceBulkMutations(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	events := captureBroadcaster()
+	svc := newTestService(t, repo, events)
+
+	var ids []uuid.UUID
+	for _, title := range []string{"One", "Two"} {
+		item, err := svc.Create(ctx, CreateInput{UserID: "user-4", Title: title, Body: "Body"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+	foreign, err := svc.Create(ctx, CreateInput{UserID: "user-5", Title: "Other", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create foreign: %v", err)
+	}
+	all := append(append([]uuid.UUID{}, ids...), foreign.ID)
+	events.events = nil
+
+	if err := svc.BulkSetPinned(ctx, "user-4", all, true); err != nil {
+		t.Fatalf("bulk pin: %v", err)
+	}
+	if err := svc.BulkSnooze(ctx, "user-4", all, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("bulk snooze: %v", err)
+	}
+	if err := svc.BulkDismiss(ctx, "user-4", all); err != nil {
+		t.Fatalf("bulk dismiss: %v", err)
+	}
+
+	if len(events.events) != 3 {
+		t.Fatalf("expected one broadcast per bulk call, got %d", len(events.events))
+	}
+	for _, evt := range events.events {
+		if evt.Topic != "inbox.bulk_updated" {
+			t.Fatalf("unexpected topic %q", evt.Topic)
+		}
+		payload := evt.Payload.(map[string]any)
+		if payload["count"] != 2 {
+			t.Fatalf("expected foreign item to be skipped, got %+v", payload)
+		}
+	}
+
+	for _, id := range ids {
+		item, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if !item.Pinned || item.SnoozedUntil.IsZero() || item.DismissedAt.IsZero() {
+			t.Fatalf("expected item to be pinned, snoozed and dismissed: %+v", item)
+		}
+	}
+	other, err := repo.GetByID(ctx, foreign.ID)
+	if err != nil {
+		t.Fatalf("get foreign: %v", err)
+	}
+	if other.Pinned || !other.DismissedAt.IsZero() {
+		t.Fatalf("expected foreign item to be untouched: %+v", other)
+	}
+}
+
+func TestServicePinLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	svc, err := NewService(Dependencies{
+		Repository: repo,
+		Logger:     &logger.Nop{},
+		MaxPinned:  2,
+	})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	if _, err := svc.Create(ctx, CreateInput{UserID: "user-7", Title: "A", Body: "Body", Pinned: true}); err != nil {
+		t.Fatalf("create pinned: %v", err)
+	}
+	var ids []uuid.UUID
+	for _, title := range []string{"B", "C"} {
+		item, err := svc.Create(ctx, CreateInput{UserID: "user-7", Title: title, Body: "Body"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	err = svc.BulkSetPinned(ctx, "user-7", ids, true)
+	var limitErr PinLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrPinLimitExceeded) {
+		t.Fatalf("expected PinLimitError, got %v", err)
+	}
+	if limitErr.Limit != 2 || limitErr.Current != 1 {
+		t.Fatalf("unexpected limit error %+v", limitErr)
+	}
+	if count, _ := repo.CountPinned(ctx, "user-7"); count != 1 {
+		t.Fatalf("expected rejected bulk call to leave pins untouched, got %d", count)
+	}
+
+	if err := svc.SetPinned(ctx, "user-7", ids[0], true); err != nil {
+		t.Fatalf("pin within limit: %v", err)
+	}
+	if err := svc.SetPinned(ctx, "user-7", ids[0], true); err != nil {
+		t.Fatalf("re-pinning an already pinned item should not count: %v", err)
+	}
+	if _, err := svc.Create(ctx, CreateInput{UserID: "user-7", Title: "D", Body: "Body", Pinned: true}); !errors.Is(err, ErrPinLimitExceeded) {
+		t.Fatalf("expected create to hit pin limit, got %v", err)
+	}
+}
+
+func TestServiceSetPinnedEmitsUpdated(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInboxRepository()
+	events := captureBroadcaster()
+	svc := newTestService(t, repo, events)
+
+	item, err := svc.Create(ctx, CreateInput{UserID: "user-11", Title: "Pin me", Body: "Body"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	events.events = nil
+
+	if err := svc.SetPinned(ctx, "user-11", item.ID, true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if len(events.events) != 1 || events.events[0].Topic != "inbox.updated" {
+		t.Fatalf("expected one inbox.updated broadcast, got %+v", events.events)
+	}
+	stored, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.Pinned {
+		t.Fatalf("expected item pinned")
+	}
+}
+
+type captureHook struct {
+	events []activity.Event
+}
+
+func (h *captureHook) Notify(_ context.Context, evt activity.Event) {
+	h.events = append(h.events, evt)
+}
+
 type capturedEvents struct {
 	mu     sync.Mutex
 	events []broadcaster.Event
@@ -111,6 +607,21 @@ func (c *capturedEvents) Broadcast(ctx context.Context, event broadcaster.Event)
 	return nil
 }
 
+// resnoozingRepo snoozes the first due item again, to a later time, right
+// after listing it, as a user acting between the list and the release would.
+type resnoozingRepo struct {
+	*memory.InboxRepository
+	until time.Time
+}
+
+func (r *resnoozingRepo) ListSnoozedBefore(ctx context.Context, before time.Time, limit int) ([]domain.InboxItem, error) {
+	due, err := r.InboxRepository.ListSnoozedBefore(ctx, before, limit)
+	if err != nil || len(due) == 0 {
+		return due, err
+	}
+	return due, r.Snooze(ctx, due[0].ID, r.until)
+}
+
 func newTestService(t *testing.T, repo *memory.InboxRepository, br broadcaster.Broadcaster) *Service {
 	t.Helper()
 	svc, err := NewService(Dependencies{
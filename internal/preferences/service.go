@@ -22,13 +22,47 @@ const (
 	ReasonQuietHours         = "quiet-hours"
 	ReasonChannelOverride    = "channel-override"
 	ReasonSubscriptionFilter = "subscription-filter"
+	ReasonCriticalOverride   = "critical-override"
+	ReasonDoNotDisturb       = "do-not-disturb"
+	ReasonGlobalMute         = "global-mute"
 )
 
+// GlobalMuteCode is the definition code and channel of the per-subject
+// preference that silences every definition and channel at once. Storing
+// enabled=false or a future rules.snooze_until on it mutes all non-critical
+// notifications for the subject.
+const GlobalMuteCode = "*"
+
+// categoryPrefix namespaces category-level preferences inside the definition
+// code dimension.
+const categoryPrefix = "category:"
+
+// groupSubjectType identifies group-level preference records.
+const groupSubjectType = "group"
+
+// CategoryCode returns the definition code under which category-level
+// preferences are stored, e.g. "category:social". Empty categories yield "".
+func CategoryCode(category string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return ""
+	}
+	return categoryPrefix + category
+}
+
+// DefaultCriticalSeverities lists the definition severities that bypass
+// opt-outs and quiet hours when no explicit set is configured.
+var DefaultCriticalSeverities = []string{"critical"}
+
 // QuietHoursWindow models a quiet hours schedule relative to a timezone.
+// Weekdays optionally overrides Start/End for specific days, keyed by
+// short weekday name ("mon" through "sun"); days without an entry fall back
+// to the top-level window.
 type QuietHoursWindow struct {
 	Start    string
 	End      string
 	Timezone string
+	Weekdays map[string]QuietHoursWindow
 }
 
 // PreferenceInput captures persistence fields for CRUD operations.
@@ -52,6 +86,14 @@ type EvaluationRequest struct {
 	Subscriptions  []string
 	Timestamp      time.Time
 	DefaultEnabled *bool
+	// Severity is the definition severity; critical severities bypass user
+	// opt-outs and quiet hours but not rules.do_not_disturb or definitions
+	// disabled below the user scope.
+	Severity string
+	// Timezone and Locale describe the recipient and are used to place
+	// quiet-hours windows that do not name a timezone (see quietHoursLocation).
+	Timezone string
+	Locale   string
 }
 
 // EvaluationResult returns the computed state along with traces.
@@ -60,6 +102,7 @@ type EvaluationResult struct {
 	Reason            string
 	QuietHoursActive  bool
 	ChannelOverride   bool
+	GlobalMuted       bool
 	Provider          string
 	Trace             opts.Trace
 	ChannelTrace      opts.Trace
@@ -69,22 +112,75 @@ type EvaluationResult struct {
 	SubscriptionTrace opts.Trace
 }
 
+// ProviderLookup returns the provider names known for a channel. An empty
+// result means the channel has no registered providers to validate against.
+type ProviderLookup func(channel string) []string
+
+// Subject identifies a recipient for scope construction. Category is the
+// definition category and, when set, adds category-level scopes. Severity is
+// the definition severity and applies the same critical bypass as Evaluate.
+type Subject struct {
+	UserID        string
+	TenantID      string
+	Category      string
+	Severity      string
+	Subscriptions []string
+	Timezone      string
+	Locale        string
+}
+
+// EffectiveState summarises whether a definition/channel would be delivered
+// to a subject right now and why.
+type EffectiveState struct {
+	Allowed          bool     `json:"allowed"`
+	Reason           string   `json:"reason"`
+	Provider         string   `json:"provider,omitempty"`
+	QuietHoursActive bool     `json:"quiet_hours_active"`
+	GlobalMuted      bool     `json:"global_muted"`
+	RequiredSubs     []string `json:"required_subscriptions,omitempty"`
+}
+
+// GroupResolver returns the group IDs a subject belongs to, ordered from
+// highest to lowest precedence.
+type GroupResolver func(ctx context.Context, subjectType, subjectID string) ([]string, error)
+
+// SubscriptionResolver returns the subscription group codes a subject
+// belongs to.
+type SubscriptionResolver func(ctx context.Context, subjectType, subjectID string) ([]string, error)
+
 // Dependencies wires repositories and logging into the service.
 type Dependencies struct {
 	Repository store.NotificationPreferenceRepository
 	Logger     logger.Logger
 	Clock      func() time.Time
+	// KnownProviders enables provider validation on writes when set.
+	KnownProviders ProviderLookup
+	// CriticalSeverities overrides DefaultCriticalSeverities.
+	CriticalSeverities []string
+	// Groups adds group scopes for user subjects during evaluation when set.
+	Groups GroupResolver
+	// Subscriptions, when set, supplies the recipient's subscriptions for
+	// rules.subscriptions checks instead of EvaluationRequest.Subscriptions.
+	Subscriptions SubscriptionResolver
 }
 
 // Service persists preferences and evaluates scope-aware rules.
 type Service struct {
-	repo  store.NotificationPreferenceRepository
-	log   logger.Logger
-	clock func() time.Time
+	repo      store.NotificationPreferenceRepository
+	log       logger.Logger
+	clock     func() time.Time
+	providers ProviderLookup
+	critical  map[string]struct{}
+	groups    GroupResolver
+	subs      SubscriptionResolver
 }
 
 var (
 	errRepositoryRequired = errors.New("preferences: repository is required")
+
+	// ErrUnknownProvider is returned when a preference references a provider
+	// that is not registered for the channel.
+	ErrUnknownProvider = errors.New("preferences: unknown provider")
 )
 
 // NewService constructs the preferences service.
@@ -98,16 +194,30 @@ func NewService(deps Dependencies) (*Service, error) {
 	if deps.Clock == nil {
 		deps.Clock = time.Now
 	}
+	severities := deps.CriticalSeverities
+	if severities == nil {
+		severities = DefaultCriticalSeverities
+	}
+	critical := make(map[string]struct{}, len(severities))
+	for _, severity := range severities {
+		if severity = strings.ToLower(strings.TrimSpace(severity)); severity != "" {
+			critical[severity] = struct{}{}
+		}
+	}
 	return &Service{
-		repo:  deps.Repository,
-		log:   deps.Logger,
-		clock: deps.Clock,
+		repo:      deps.Repository,
+		log:       deps.Logger,
+		clock:     deps.Clock,
+		providers: deps.KnownProviders,
+		critical:  critical,
+		groups:    deps.Groups,
+		subs:      deps.Subscriptions,
 	}, nil
 }
 
 // Create persists a brand new preference record.
 func (s *Service) Create(ctx context.Context, input PreferenceInput) (*domain.NotificationPreference, error) {
-	if err := validateInput(input); err != nil {
+	if err := s.validate(input); err != nil {
 		return nil, err
 	}
 	if _, err := s.repo.GetBySubject(ctx, input.SubjectType, input.SubjectID, input.DefinitionCode, input.Channel); err == nil {
@@ -124,7 +234,7 @@ func (s *Service) Create(ctx context.Context, input PreferenceInput) (*domain.No
 
 // Update mutates an existing preference record.
 func (s *Service) Update(ctx context.Context, input PreferenceInput) (*domain.NotificationPreference, error) {
-	if err := validateInput(input); err != nil {
+	if err := s.validate(input); err != nil {
 		return nil, err
 	}
 	current, err := s.repo.GetBySubject(ctx, input.SubjectType, input.SubjectID, input.DefinitionCode, input.Channel)
@@ -140,20 +250,62 @@ func (s *Service) Update(ctx context.Context, input PreferenceInput) (*domain.No
 
 // Upsert creates or updates a preference record.
 func (s *Service) Upsert(ctx context.Context, input PreferenceInput) (*domain.NotificationPreference, error) {
-	if err := validateInput(input); err != nil {
+	if err := s.validate(input); err != nil {
 		return nil, err
 	}
-	current, err := s.repo.GetBySubject(ctx, input.SubjectType, input.SubjectID, input.DefinitionCode, input.Channel)
+	return upsert(ctx, s.repo, input)
+}
+
+// UpsertMany validates every input and then upserts them in order. When the
+// repository implements store.PreferenceTxRepository the writes are applied
+// atomically; otherwise they are applied one by one and a failure leaves
+// earlier writes in place.
+func (s *Service) UpsertMany(ctx context.Context, inputs []PreferenceInput) ([]*domain.NotificationPreference, error) {
+	for i, input := range inputs {
+		if err := s.validate(input); err != nil {
+			return nil, fmt.Errorf("preferences: input %d: %w", i, err)
+		}
+	}
+	apply := func(ctx context.Context, repo store.PreferenceWriter) ([]*domain.NotificationPreference, error) {
+		records := make([]*domain.NotificationPreference, 0, len(inputs))
+		for i, input := range inputs {
+			record, err := upsert(ctx, repo, input)
+			if err != nil {
+				return nil, fmt.Errorf("preferences: input %d: %w", i, err)
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	}
+
+	txRepo, ok := s.repo.(store.PreferenceTxRepository)
+	if !ok {
+		return apply(ctx, s.repo)
+	}
+	var records []*domain.NotificationPreference
+	err := txRepo.RunInTx(ctx, func(ctx context.Context, repo store.PreferenceWriter) error {
+		var err error
+		records, err = apply(ctx, repo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func upsert(ctx context.Context, repo store.PreferenceWriter, input PreferenceInput) (*domain.NotificationPreference, error) {
+	current, err := repo.GetBySubject(ctx, input.SubjectType, input.SubjectID, input.DefinitionCode, input.Channel)
 	switch {
 	case err == nil:
 		applyInput(current, input)
-		if err := s.repo.Update(ctx, current); err != nil {
+		if err := repo.Update(ctx, current); err != nil {
 			return nil, err
 		}
 		return current, nil
 	case errors.Is(err, store.ErrNotFound):
 		record := newPreferenceRecord(input)
-		if err := s.repo.Create(ctx, record); err != nil {
+		if err := repo.Create(ctx, record); err != nil {
 			return nil, err
 		}
 		return record, nil
@@ -181,6 +333,65 @@ func (s *Service) List(ctx context.Context, opts store.ListOptions) (store.ListR
 	return s.repo.List(ctx, opts)
 }
 
+// Effective evaluates the subject's preferences for definitionCode/channel at
+// now using the same scopes the dispatcher builds, without dispatching.
+func (s *Service) Effective(ctx context.Context, subject Subject, definitionCode, channel string, now time.Time) (EffectiveState, error) {
+	result, err := s.Evaluate(ctx, EvaluationRequest{
+		DefinitionCode: definitionCode,
+		Channel:        channel,
+		Scopes:         SubjectScopes(subject, definitionCode, channel),
+		Subscriptions:  subject.Subscriptions,
+		Timestamp:      now,
+		Severity:       subject.Severity,
+		Timezone:       subject.Timezone,
+		Locale:         subject.Locale,
+	})
+	if err != nil {
+		return EffectiveState{}, err
+	}
+	return EffectiveState{
+		Allowed:          result.Allowed,
+		Reason:           result.Reason,
+		Provider:         result.Provider,
+		QuietHoursActive: result.QuietHoursActive,
+		GlobalMuted:      result.GlobalMuted,
+		RequiredSubs:     result.RequiredSubs,
+	}, nil
+}
+
+// SubjectScopes returns the user, tenant, and system scopes for a subject.
+// When the subject carries a category, each scope is followed by a category
+// scope ranked just below it.
+func SubjectScopes(subject Subject, definitionCode, channel string) []pkgoptions.PreferenceScopeRef {
+	var scopes []pkgoptions.PreferenceScopeRef
+	add := func(name string, priority int, subjectType, subjectID string) {
+		scopes = append(scopes, pkgoptions.PreferenceScopeRef{
+			Scope:          opts.NewScope(name, priority),
+			SubjectType:    subjectType,
+			SubjectID:      subjectID,
+			DefinitionCode: definitionCode,
+			Channel:        channel,
+		})
+		if code := CategoryCode(subject.Category); code != "" {
+			scopes = append(scopes, pkgoptions.PreferenceScopeRef{
+				Scope:          opts.NewScope(name+"-category", priority-1),
+				SubjectType:    subjectType,
+				SubjectID:      subjectID,
+				DefinitionCode: code,
+				Channel:        channel,
+			})
+		}
+	}
+	if userID := strings.TrimSpace(subject.UserID); userID != "" {
+		add("user", opts.ScopePriorityUser, "user", userID)
+	}
+	if tenantID := strings.TrimSpace(subject.TenantID); tenantID != "" {
+		add("tenant", opts.ScopePriorityTenant, "tenant", tenantID)
+	}
+	add("system", opts.ScopePrioritySystem, "system", "default")
+	return scopes
+}
+
 // Evaluate merges scope snapshots and enforces opt-out rules prior to dispatch.
 func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (EvaluationResult, error) {
 	result := EvaluationResult{
@@ -197,6 +408,12 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 		return result, errors.New("preferences: channel is required")
 	}
 
+	groupScopes, err := s.groupScopes(ctx, req.Scopes)
+	if err != nil {
+		return result, err
+	}
+	req.Scopes = append(append([]pkgoptions.PreferenceScopeRef(nil), req.Scopes...), groupScopes...)
+
 	refScopes := normalizeScopes(req)
 	store := pkgoptions.PreferenceSnapshotStore{Repository: s.repo}
 	snapshots, err := store.Load(ctx, refScopes)
@@ -221,14 +438,46 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 	}
 	result.Resolver = resolver
 
+	ts := req.Timestamp
+	if ts.IsZero() {
+		ts = s.clock()
+	}
+
+	// Critical notifications record suppressions they bypass instead of
+	// being blocked by them. Only the recipient's own mutes, opt-outs and
+	// quiet hours are bypassed; mutes and definitions disabled by tenant,
+	// system or default scopes stay in force.
+	critical := s.isCritical(req.Severity)
+	overridden := false
+
+	global, err := s.evaluateGlobalMute(ctx, refScopes, ts)
+	if err != nil {
+		return result, err
+	}
+	if global.muted {
+		result.GlobalMuted = true
+		if critical && global.userScoped {
+			overridden = true
+		} else {
+			result.Allowed = false
+			result.Reason = ReasonGlobalMute
+		}
+	}
+
 	if enabled, trace, err := resolver.ResolveBool("enabled"); err == nil {
-		result.Allowed = enabled
 		result.Trace = trace
 		if !enabled {
-			result.Reason = ReasonOptOut
+			if critical && userScoped(trace) {
+				overridden = true
+			} else {
+				if result.Allowed || result.Reason == ReasonDefault {
+					result.Reason = ReasonOptOut
+				}
+				result.Allowed = false
+			}
 		}
-	} else {
-		result.Allowed = defaultState
+	} else if !defaultState {
+		result.Allowed = false
 	}
 
 	if req.Channel != "" {
@@ -237,10 +486,14 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 			result.ChannelOverride = true
 			result.ChannelTrace = trace
 			if !channelState {
-				if result.Allowed || result.Reason == ReasonDefault {
-					result.Reason = ReasonChannelOverride
+				if critical && userScoped(trace) {
+					overridden = true
+				} else {
+					if result.Allowed || result.Reason == ReasonDefault {
+						result.Reason = ReasonChannelOverride
+					}
+					result.Allowed = false
 				}
-				result.Allowed = false
 			}
 		}
 		// Provider override at channel level
@@ -258,23 +511,27 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 	}
 
 	if window, ok := resolveQuietHours(resolver); ok {
-		ts := req.Timestamp
-		if ts.IsZero() {
-			ts = s.clock()
-		}
-		if window.contains(ts) {
-			if result.Allowed || result.Reason == ReasonDefault {
-				result.Reason = ReasonQuietHours
-			}
-			result.Allowed = false
+		if window.contains(ts, quietHoursLocation(resolver, req)) {
 			result.QuietHoursActive = true
+			if critical {
+				overridden = true
+			} else {
+				if result.Allowed || result.Reason == ReasonDefault {
+					result.Reason = ReasonQuietHours
+				}
+				result.Allowed = false
+			}
 		}
 	}
 
 	if subs, trace, err := resolver.ResolveStringSlice("rules.subscriptions"); err == nil && len(subs) > 0 {
 		result.RequiredSubs = subs
 		result.SubscriptionTrace = trace
-		if !intersects(subs, req.Subscriptions) {
+		provided, err := s.recipientSubscriptions(ctx, req)
+		if err != nil {
+			return result, err
+		}
+		if !intersects(subs, provided) {
 			if result.Allowed || result.Reason == ReasonDefault {
 				result.Reason = ReasonSubscriptionFilter
 			}
@@ -282,9 +539,197 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 		}
 	}
 
+	if overridden && result.Allowed {
+		result.Reason = ReasonCriticalOverride
+	}
+	// do_not_disturb is a hard stop that even critical severities respect.
+	if dnd, _, err := resolver.ResolveBool("rules.do_not_disturb"); (err == nil && dnd) || global.doNotDisturb {
+		result.Allowed = false
+		result.Reason = ReasonDoNotDisturb
+	}
+
 	return result, nil
 }
 
+type globalMute struct {
+	muted bool
+	// userScoped reports that every mute in effect comes from a user scope,
+	// so a critical notification may bypass it.
+	userScoped   bool
+	doNotDisturb bool
+}
+
+// evaluateGlobalMute resolves the GlobalMuteCode preference for the same
+// subjects as the definition-level lookup.
+func (s *Service) evaluateGlobalMute(ctx context.Context, refs []pkgoptions.PreferenceScopeRef, now time.Time) (globalMute, error) {
+	// Several scopes may share a subject (e.g. definition and category
+	// scopes); load each subject once at its highest priority.
+	globalRefs := make([]pkgoptions.PreferenceScopeRef, 0, len(refs))
+	index := make(map[string]int, len(refs))
+	for _, ref := range refs {
+		ref.DefinitionCode = GlobalMuteCode
+		ref.Channel = GlobalMuteCode
+		key := ref.SubjectType + "\x00" + ref.SubjectID
+		if i, ok := index[key]; ok {
+			if ref.Scope.Priority > globalRefs[i].Scope.Priority {
+				globalRefs[i] = ref
+			}
+			continue
+		}
+		index[key] = len(globalRefs)
+		globalRefs = append(globalRefs, ref)
+	}
+	store := pkgoptions.PreferenceSnapshotStore{Repository: s.repo}
+	snapshots, err := store.Load(ctx, globalRefs)
+	if err != nil {
+		return globalMute{}, err
+	}
+	if len(snapshots) == 0 {
+		return globalMute{}, nil
+	}
+	resolver, err := pkgoptions.NewResolver(snapshots...)
+	if err != nil {
+		return globalMute{}, err
+	}
+
+	state := globalMute{userScoped: true}
+	if enabled, trace, err := resolver.ResolveBool("enabled"); err == nil && !enabled {
+		state.muted = true
+		state.userScoped = userScoped(trace)
+	}
+	if value, trace, err := resolver.Resolve("rules.snooze_until"); err == nil {
+		if until, ok := asTime(value); ok && until.After(now) {
+			state.muted = true
+			state.userScoped = state.userScoped && userScoped(trace)
+		}
+	}
+	if dnd, _, err := resolver.ResolveBool("rules.do_not_disturb"); err == nil && dnd {
+		state.doNotDisturb = true
+	}
+	return state, nil
+}
+
+func asTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, !v.IsZero()
+	case string:
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(v))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// recipientSubscriptions returns the subscriptions of the highest-priority
+// user scope via the configured resolver, falling back to the subscriptions
+// supplied on the request.
+func (s *Service) recipientSubscriptions(ctx context.Context, req EvaluationRequest) ([]string, error) {
+	if s.subs == nil {
+		return req.Subscriptions, nil
+	}
+	var recipient *pkgoptions.PreferenceScopeRef
+	for i := range req.Scopes {
+		ref := &req.Scopes[i]
+		if ref.SubjectType != "user" || strings.TrimSpace(ref.SubjectID) == "" {
+			continue
+		}
+		if recipient == nil || ref.Scope.Priority > recipient.Scope.Priority {
+			recipient = ref
+		}
+	}
+	if recipient == nil {
+		return req.Subscriptions, nil
+	}
+	subs, err := s.subs(ctx, recipient.SubjectType, recipient.SubjectID)
+	if err != nil {
+		return nil, fmt.Errorf("preferences: resolve subscriptions for %s: %w", recipient.SubjectID, err)
+	}
+	return subs, nil
+}
+
+func (s *Service) isCritical(severity string) bool {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	if severity == "" {
+		return false
+	}
+	_, ok := s.critical[severity]
+	return ok
+}
+
+// userScoped reports whether the value traced was supplied by a user scope
+// (including its category scope) rather than a group, tenant, system or
+// default scope.
+func userScoped(trace opts.Trace) bool {
+	for _, layer := range trace.Layers {
+		if layer.Found {
+			return layer.Scope.Priority > opts.ScopePriorityTeam
+		}
+	}
+	return false
+}
+
+// groupScopes resolves group memberships for the user subjects in scopes and
+// returns one scope per group, ranked below every user scope and above the
+// tenant scope so explicit user settings always win over group defaults.
+func (s *Service) groupScopes(ctx context.Context, scopes []pkgoptions.PreferenceScopeRef) ([]pkgoptions.PreferenceScopeRef, error) {
+	if s.groups == nil {
+		return nil, nil
+	}
+	seen := make(map[string]struct{})
+	for _, ref := range scopes {
+		if ref.SubjectType == groupSubjectType {
+			seen[ref.SubjectID] = struct{}{}
+		}
+	}
+
+	var out []pkgoptions.PreferenceScopeRef
+	users := make(map[string]struct{})
+	for _, ref := range scopes {
+		if ref.SubjectType != "user" || strings.TrimSpace(ref.SubjectID) == "" {
+			continue
+		}
+		if _, ok := users[ref.SubjectID]; ok {
+			continue
+		}
+		users[ref.SubjectID] = struct{}{}
+
+		groups, err := s.groups(ctx, ref.SubjectType, ref.SubjectID)
+		if err != nil {
+			return nil, fmt.Errorf("preferences: resolve groups for %s: %w", ref.SubjectID, err)
+		}
+		for _, group := range groups {
+			group = strings.TrimSpace(group)
+			if group == "" {
+				continue
+			}
+			if _, ok := seen[group]; ok {
+				continue
+			}
+			priority := opts.ScopePriorityTeam - len(out)
+			if priority <= opts.ScopePriorityTenant {
+				s.log.Warn("preferences: group scope limit reached", "subject_id", ref.SubjectID)
+				return out, nil
+			}
+			seen[group] = struct{}{}
+			out = append(out, pkgoptions.PreferenceScopeRef{
+				Scope:       opts.NewScope(groupSubjectType+":"+group, priority, opts.WithScopeLabel("Group "+group)),
+				SubjectType: groupSubjectType,
+				SubjectID:   group,
+			})
+		}
+	}
+	return out, nil
+}
+
 func normalizeScopes(req EvaluationRequest) []pkgoptions.PreferenceScopeRef {
 	out := make([]pkgoptions.PreferenceScopeRef, len(req.Scopes))
 	for i, scope := range req.Scopes {
@@ -325,6 +770,8 @@ func applyInput(record *domain.NotificationPreference, input PreferenceInput) {
 	if record == nil {
 		return
 	}
+	// Copy before mutating so stored records never share the caller's map.
+	record.AdditionalRules = copyJSONMap(record.AdditionalRules)
 	if record.AdditionalRules == nil {
 		record.AdditionalRules = make(domain.JSONMap)
 	}
@@ -351,16 +798,26 @@ func quietHoursToJSON(window *QuietHoursWindow) (domain.JSONMap, bool) {
 	}
 	start := strings.TrimSpace(window.Start)
 	end := strings.TrimSpace(window.End)
-	if start == "" && end == "" {
+	if start == "" && end == "" && len(window.Weekdays) == 0 {
 		return nil, true
 	}
-	result := domain.JSONMap{
-		"start": start,
-		"end":   end,
+	result := domain.JSONMap{}
+	if start != "" || end != "" {
+		result["start"] = start
+		result["end"] = end
 	}
 	if tz := strings.TrimSpace(window.Timezone); tz != "" {
 		result["timezone"] = tz
 	}
+	for key, day := range window.Weekdays {
+		if _, ok := parseWeekday(key); !ok {
+			continue
+		}
+		result[strings.ToLower(strings.TrimSpace(key))] = map[string]any{
+			"start": strings.TrimSpace(day.Start),
+			"end":   strings.TrimSpace(day.End),
+		}
+	}
 	return result, true
 }
 
@@ -373,6 +830,65 @@ func copyJSONMap(src domain.JSONMap) domain.JSONMap {
 	return dst
 }
 
+func (s *Service) validate(input PreferenceInput) error {
+	if err := validateInput(input); err != nil {
+		return err
+	}
+	return s.validateProviders(input)
+}
+
+// validateProviders checks root and per-channel provider overrides against the
+// known provider set. Channels without registered providers are not checked.
+func (s *Service) validateProviders(input PreferenceInput) error {
+	if s.providers == nil {
+		return nil
+	}
+	if input.Provider != nil {
+		if err := s.checkProvider(input.Channel, *input.Provider); err != nil {
+			return err
+		}
+	}
+	if provider, ok := input.Rules["provider"].(string); ok {
+		if err := s.checkProvider(input.Channel, provider); err != nil {
+			return err
+		}
+	}
+	channels, ok := input.Rules["channels"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	for channel, raw := range channels {
+		entry, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		if provider, ok := entry["provider"].(string); ok {
+			if err := s.checkProvider(channel, provider); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) checkProvider(channel, provider string) error {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return nil
+	}
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	known := s.providers(channel)
+	if len(known) == 0 {
+		return nil
+	}
+	for _, candidate := range known {
+		if strings.EqualFold(strings.TrimSpace(candidate), provider) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q is not registered for channel %s (known: %s)", ErrUnknownProvider, provider, channel, strings.Join(known, ", "))
+}
+
 func validateInput(input PreferenceInput) error {
 	if strings.TrimSpace(input.SubjectType) == "" {
 		return errors.New("preferences: subject type is required")
@@ -389,10 +905,35 @@ func validateInput(input PreferenceInput) error {
 	return nil
 }
 
+type quietWindow struct {
+	start string
+	end   string
+}
+
 type quietHours struct {
 	start    string
 	end      string
 	timezone string
+	days     map[time.Weekday]quietWindow
+}
+
+var weekdayKeys = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseWeekday(key string) (time.Weekday, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if len(key) > 3 {
+		key = key[:3]
+	}
+	day, ok := weekdayKeys[key]
+	return day, ok
 }
 
 func resolveQuietHours(resolver *pkgoptions.Resolver) (quietHours, bool) {
@@ -405,56 +946,190 @@ func resolveQuietHours(resolver *pkgoptions.Resolver) (quietHours, bool) {
 	}
 	switch v := value.(type) {
 	case map[string]any:
-		return quietHours{
-			start:    asString(v["start"]),
-			end:      asString(v["end"]),
-			timezone: asString(v["timezone"]),
-		}, true
+		return parseQuietHours(v), true
 	case domain.JSONMap:
-		return quietHours{
-			start:    asString(v["start"]),
-			end:      asString(v["end"]),
-			timezone: asString(v["timezone"]),
-		}, true
+		return parseQuietHours(v), true
 	default:
 		return quietHours{}, false
 	}
 }
 
-func (q quietHours) contains(ts time.Time) bool {
-	if q.start == "" || q.end == "" {
-		return false
+func parseQuietHours(raw map[string]any) quietHours {
+	q := quietHours{
+		start:    asString(raw["start"]),
+		end:      asString(raw["end"]),
+		timezone: asString(raw["timezone"]),
+	}
+	for key, value := range raw {
+		day, ok := parseWeekday(key)
+		if !ok {
+			continue
+		}
+		var entry map[string]any
+		switch v := value.(type) {
+		case map[string]any:
+			entry = v
+		case domain.JSONMap:
+			entry = v
+		default:
+			continue
+		}
+		if q.days == nil {
+			q.days = make(map[time.Weekday]quietWindow, 7)
+		}
+		q.days[day] = quietWindow{start: asString(entry["start"]), end: asString(entry["end"])}
+	}
+	return q
+}
+
+// window returns the schedule for the given weekday, falling back to the
+// top-level start/end when the day has no entry.
+func (q quietHours) window(day time.Weekday) quietWindow {
+	if w, ok := q.days[day]; ok {
+		return w
+	}
+	return quietWindow{start: q.start, end: q.end}
+}
+
+// quietHoursLocation returns the zone used for quiet-hours windows that do
+// not carry their own timezone. The fallback chain is:
+//
+//  1. rules.timezone from the subject's stored preferences
+//  2. EvaluationRequest.Timezone
+//  3. the region of the stored preference locale, then of
+//     EvaluationRequest.Locale (e.g. "es-MX" -> America/Mexico_City)
+//  4. UTC
+func quietHoursLocation(resolver *pkgoptions.Resolver, req EvaluationRequest) *time.Location {
+	var candidates []string
+	if tz, _, err := resolver.ResolveString("rules.timezone"); err == nil {
+		candidates = append(candidates, tz)
+	}
+	candidates = append(candidates, req.Timezone)
+	if locale, _, err := resolver.ResolveString("locale"); err == nil {
+		candidates = append(candidates, timezoneForLocale(locale))
+	}
+	candidates = append(candidates, timezoneForLocale(req.Locale))
+	for _, name := range candidates {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// regionTimezones maps ISO 3166 regions to a representative zone. Regions
+// spanning several zones use their most populous one.
+var regionTimezones = map[string]string{
+	"AR": "America/Argentina/Buenos_Aires",
+	"AU": "Australia/Sydney",
+	"BR": "America/Sao_Paulo",
+	"CA": "America/Toronto",
+	"CH": "Europe/Zurich",
+	"CL": "America/Santiago",
+	"CN": "Asia/Shanghai",
+	"CO": "America/Bogota",
+	"DE": "Europe/Berlin",
+	"ES": "Europe/Madrid",
+	"FR": "Europe/Paris",
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"IN": "Asia/Kolkata",
+	"IT": "Europe/Rome",
+	"JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul",
+	"MX": "America/Mexico_City",
+	"NL": "Europe/Amsterdam",
+	"NZ": "Pacific/Auckland",
+	"PE": "America/Lima",
+	"PL": "Europe/Warsaw",
+	"PT": "Europe/Lisbon",
+	"SE": "Europe/Stockholm",
+	"US": "America/New_York",
+	"ZA": "Africa/Johannesburg",
+}
+
+// timezoneForLocale derives a zone from the region subtag of a locale such
+// as "es-MX" or "pt_BR". Language-only locales yield "".
+func timezoneForLocale(locale string) string {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for i := len(parts) - 1; i > 0; i-- {
+		if len(parts[i]) == 2 {
+			return regionTimezones[strings.ToUpper(parts[i])]
+		}
+	}
+	return ""
+}
+
+// contains reports whether ts falls inside quiet hours. The window's own
+// timezone wins over fallback. Windows are compared against the wall clock
+// in that zone rather than anchored instants, so DST transitions neither
+// shift the boundaries nor skip or double-count the repeated hour: a
+// 22:00-06:00 window ends when local clocks read 06:00, however long the
+// night actually was.
+func (q quietHours) contains(ts time.Time, fallback *time.Location) bool {
+	loc := fallback
+	if loc == nil {
+		loc = time.UTC
 	}
-	loc := time.UTC
 	if q.timezone != "" {
 		if location, err := time.LoadLocation(q.timezone); err == nil {
 			loc = location
 		}
 	}
 	now := ts.In(loc)
-	startClock, err := time.Parse("15:04", q.start)
+	minute := now.Hour()*60 + now.Minute()
+	if len(q.days) == 0 {
+		return quietWindow{start: q.start, end: q.end}.contains(minute)
+	}
+
+	// A window that wraps midnight is attributed to the day it starts on, so
+	// the early hours are governed by yesterday's schedule.
+	if start, end, ok := q.window(now.Weekday()).clock(); ok {
+		if start < end {
+			if minute >= start && minute < end {
+				return true
+			}
+		} else if minute >= start {
+			return true
+		}
+	}
+	yesterday := (now.Weekday() + 6) % 7
+	if start, end, ok := q.window(yesterday).clock(); ok && start >= end {
+		return minute < end
+	}
+	return false
+}
+
+// clock returns the window boundaries as minutes since local midnight.
+func (w quietWindow) clock() (int, int, bool) {
+	if w.start == "" || w.end == "" {
+		return 0, 0, false
+	}
+	start, err := time.Parse("15:04", w.start)
 	if err != nil {
-		return false
+		return 0, 0, false
 	}
-	endClock, err := time.Parse("15:04", q.end)
+	end, err := time.Parse("15:04", w.end)
 	if err != nil {
-		return false
+		return 0, 0, false
 	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), true
+}
 
-	start := time.Date(now.Year(), now.Month(), now.Day(), startClock.Hour(), startClock.Minute(), 0, 0, loc)
-	end := time.Date(now.Year(), now.Month(), now.Day(), endClock.Hour(), endClock.Minute(), 0, 0, loc)
-
-	if !end.After(start) {
-		// Wrap around midnight.
-		end = end.Add(24 * time.Hour)
-		if now.Before(start) {
-			start = start.Add(-24 * time.Hour)
-		}
-	}
-	if now.Before(start) || !now.Before(end) {
+func (w quietWindow) contains(minute int) bool {
+	start, end, ok := w.clock()
+	if !ok {
 		return false
 	}
-	return true
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// Wraps midnight; equal boundaries cover the whole day.
+	return minute >= start || minute < end
 }
 
 func intersects(allowed, provided []string) bool {
@@ -2,12 +2,14 @@ package preferences
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/goliatone/go-notifications/internal/storage/memory"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+	"github.com/goliatone/go-notifications/pkg/interfaces/store"
 	pkgoptions "github.com/goliatone/go-notifications/pkg/options"
 	opts "github.com/goliatone/go-options"
 )
@@ -65,6 +67,81 @@ func TestServiceUpsertCreatesAndUpdates(t *testing.T) {
 	}
 }
 
+func TestServiceUpsertManyIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	existing, err := service.Upsert(ctx, PreferenceInput{
+		SubjectType:    "user",
+		SubjectID:      "bulk",
+		DefinitionCode: "digest",
+		Channel:        "email",
+		Enabled:        boolPtr(true),
+	})
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	records, err := service.UpsertMany(ctx, []PreferenceInput{
+		{SubjectType: "user", SubjectID: "bulk", DefinitionCode: "digest", Channel: "email", Enabled: boolPtr(false)},
+		{SubjectType: "user", SubjectID: "bulk", DefinitionCode: "digest", Channel: "sms", Enabled: boolPtr(true)},
+	})
+	if err != nil {
+		t.Fatalf("upsert many: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != existing.ID || records[0].Enabled {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	// A failing write rolls back the earlier ones.
+	failing := &failingCreateRepo{PreferenceRepository: repo, failCode: "broken"}
+	service.repo = failing
+	_, err = service.UpsertMany(ctx, []PreferenceInput{
+		{SubjectType: "user", SubjectID: "bulk", DefinitionCode: "digest", Channel: "email", Enabled: boolPtr(true)},
+		{SubjectType: "user", SubjectID: "bulk", DefinitionCode: "broken", Channel: "email"},
+	})
+	if err == nil {
+		t.Fatalf("expected upsert many to fail")
+	}
+	current, err := repo.GetBySubject(ctx, "user", "bulk", "digest", "email")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if current.Enabled {
+		t.Fatalf("expected first write to be rolled back")
+	}
+
+	if _, err := service.UpsertMany(ctx, []PreferenceInput{{SubjectType: "user"}}); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+// failingCreateRepo fails creates for one definition code while keeping the
+// memory repository's transaction support.
+type failingCreateRepo struct {
+	*memory.PreferenceRepository
+	failCode string
+}
+
+func (r *failingCreateRepo) RunInTx(ctx context.Context, fn func(ctx context.Context, repo store.PreferenceWriter) error) error {
+	return r.PreferenceRepository.RunInTx(ctx, func(ctx context.Context, tx store.PreferenceWriter) error {
+		return fn(ctx, &failingCreateWriter{PreferenceWriter: tx, failCode: r.failCode})
+	})
+}
+
+type failingCreateWriter struct {
+	store.PreferenceWriter
+	failCode string
+}
+
+func (w *failingCreateWriter) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	if pref.DefinitionCode == w.failCode {
+		return errors.New("create failed")
+	}
+	return w.PreferenceWriter.Create(ctx, pref)
+}
+
 func TestServiceEvaluateOptOut(t *testing.T) {
 	ctx := context.Background()
 	repo := memory.NewPreferenceRepository()
@@ -169,6 +246,668 @@ func TestServiceEvaluateQuietHours(t *testing.T) {
 	}
 }
 
+func TestServiceEvaluateWeekdayQuietHours(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	record := &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "weekday",
+		DefinitionCode: "status.update",
+		Channel:        "sms",
+		Enabled:        true,
+		QuietHours: domain.JSONMap{
+			"start":    "22:00",
+			"end":      "07:00",
+			"timezone": "America/New_York",
+			"sat":      map[string]any{"start": "20:00", "end": "11:00"},
+			"sun":      map[string]any{"start": "", "end": ""},
+		},
+	}
+	if err := repo.Create(ctx, record); err != nil {
+		t.Fatalf("seed preference: %v", err)
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	cases := []struct {
+		name  string
+		at    time.Time
+		quiet bool
+	}{
+		{"weekday inside default window", time.Date(2024, 10, 9, 23, 0, 0, 0, ny), true},
+		{"weekday outside default window", time.Date(2024, 10, 9, 12, 0, 0, 0, ny), false},
+		{"saturday evening uses saturday window", time.Date(2024, 10, 12, 21, 0, 0, 0, ny), true},
+		{"friday evening before default start", time.Date(2024, 10, 11, 21, 0, 0, 0, ny), false},
+		{"sunday morning continues saturday window", time.Date(2024, 10, 13, 10, 0, 0, 0, ny), true},
+		{"sunday night has no quiet hours", time.Date(2024, 10, 13, 23, 0, 0, 0, ny), false},
+		{"monday morning after quiet sunday", time.Date(2024, 10, 14, 6, 0, 0, 0, ny), false},
+		{"tuesday morning continues monday window", time.Date(2024, 10, 15, 6, 0, 0, 0, ny), true},
+	}
+	for _, tc := range cases {
+		res, err := service.Evaluate(ctx, EvaluationRequest{
+			DefinitionCode: "status.update",
+			Channel:        "sms",
+			Timestamp:      tc.at.UTC(),
+			Scopes: []pkgoptions.PreferenceScopeRef{
+				{
+					Scope:       opts.NewScope("user", opts.ScopePriorityUser),
+					SubjectType: "user",
+					SubjectID:   "weekday",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: evaluate: %v", tc.name, err)
+		}
+		if res.QuietHoursActive != tc.quiet {
+			t.Fatalf("%s: expected quiet=%v, got %+v", tc.name, tc.quiet, res)
+		}
+	}
+}
+
+func TestServiceEvaluateCriticalOverride(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	record := &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "critical",
+		DefinitionCode: "security.alert",
+		Channel:        "sms",
+		Enabled:        false,
+		QuietHours: domain.JSONMap{
+			"start": "00:00",
+			"end":   "23:59",
+		},
+	}
+	if err := repo.Create(ctx, record); err != nil {
+		t.Fatalf("seed preference: %v", err)
+	}
+	req := EvaluationRequest{
+		DefinitionCode: "security.alert",
+		Channel:        "sms",
+		Scopes: []pkgoptions.PreferenceScopeRef{
+			{
+				Scope:       opts.NewScope("user", opts.ScopePriorityUser),
+				SubjectType: "user",
+				SubjectID:   "critical",
+			},
+		},
+	}
+
+	res, err := service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonOptOut {
+		t.Fatalf("expected opt-out for non-critical severity, got %+v", res)
+	}
+
+	req.Severity = "CRITICAL"
+	res, err = service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !res.Allowed || res.Reason != ReasonCriticalOverride {
+		t.Fatalf("expected critical override, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+	if !res.QuietHoursActive {
+		t.Fatalf("expected quiet hours to still be reported")
+	}
+
+	record.AdditionalRules = domain.JSONMap{"do_not_disturb": true}
+	if err := repo.Update(ctx, record); err != nil {
+		t.Fatalf("update preference: %v", err)
+	}
+	res, err = service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonDoNotDisturb {
+		t.Fatalf("expected do-not-disturb to block critical, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+}
+
+func TestServiceEvaluateCriticalKeepsDefinitionDisabled(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	record := &domain.NotificationPreference{
+		SubjectType:    "system",
+		SubjectID:      "default",
+		DefinitionCode: "security.alert",
+		Channel:        "sms",
+		Enabled:        false,
+	}
+	if err := repo.Create(ctx, record); err != nil {
+		t.Fatalf("seed preference: %v", err)
+	}
+	disabled := false
+	req := EvaluationRequest{
+		DefinitionCode: "security.alert",
+		Channel:        "sms",
+		Severity:       "critical",
+		Scopes: []pkgoptions.PreferenceScopeRef{
+			{Scope: opts.NewScope("user", opts.ScopePriorityUser), SubjectType: "user", SubjectID: "u1"},
+			{Scope: opts.NewScope("system", opts.ScopePrioritySystem), SubjectType: "system", SubjectID: "default"},
+		},
+	}
+
+	res, err := service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonOptOut {
+		t.Fatalf("expected system disabled definition to block critical, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	if err := repo.SoftDelete(ctx, record.ID); err != nil {
+		t.Fatalf("delete preference: %v", err)
+	}
+	req.DefaultEnabled = &disabled
+	if res, err = service.Evaluate(ctx, req); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed {
+		t.Fatalf("expected definition disabled by default to block critical, got reason=%s", res.Reason)
+	}
+}
+
+func TestServiceEvaluateCriticalKeepsTenantGlobalMute(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	mute := &domain.NotificationPreference{
+		SubjectType:    "tenant",
+		SubjectID:      "acme",
+		DefinitionCode: GlobalMuteCode,
+		Channel:        GlobalMuteCode,
+		Enabled:        false,
+	}
+	if err := repo.Create(ctx, mute); err != nil {
+		t.Fatalf("seed tenant mute: %v", err)
+	}
+	req := EvaluationRequest{
+		DefinitionCode: "security.alert",
+		Channel:        "sms",
+		Severity:       "critical",
+		Scopes: []pkgoptions.PreferenceScopeRef{
+			{Scope: opts.NewScope("user", opts.ScopePriorityUser), SubjectType: "user", SubjectID: "u1"},
+			{Scope: opts.NewScope("tenant", opts.ScopePriorityTenant), SubjectType: "tenant", SubjectID: "acme"},
+		},
+	}
+
+	res, err := service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonGlobalMute || !res.GlobalMuted {
+		t.Fatalf("expected tenant mute to block critical, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	// The recipient's own mute is still bypassed once the tenant unmutes.
+	mute.Enabled = true
+	if err := repo.Update(ctx, mute); err != nil {
+		t.Fatalf("update tenant mute: %v", err)
+	}
+	if err := repo.Create(ctx, &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "u1",
+		DefinitionCode: GlobalMuteCode,
+		Channel:        GlobalMuteCode,
+		Enabled:        false,
+	}); err != nil {
+		t.Fatalf("seed user mute: %v", err)
+	}
+	res, err = service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !res.Allowed || res.Reason != ReasonCriticalOverride || !res.GlobalMuted {
+		t.Fatalf("expected critical to bypass the user mute, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+}
+
+func TestServiceEvaluateGlobalMute(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	global := &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "muted",
+		DefinitionCode: GlobalMuteCode,
+		Channel:        GlobalMuteCode,
+		Enabled:        true,
+		AdditionalRules: domain.JSONMap{
+			"snooze_until": "2024-10-10T15:00:00Z",
+		},
+	}
+	if err := repo.Create(ctx, global); err != nil {
+		t.Fatalf("seed global preference: %v", err)
+	}
+	req := EvaluationRequest{
+		DefinitionCode: "comment.created",
+		Channel:        "email",
+		Scopes: []pkgoptions.PreferenceScopeRef{
+			{
+				Scope:       opts.NewScope("user", opts.ScopePriorityUser),
+				SubjectType: "user",
+				SubjectID:   "muted",
+			},
+		},
+	}
+
+	res, err := service.Evaluate(ctx, req)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonGlobalMute || !res.GlobalMuted {
+		t.Fatalf("expected global mute while snoozed, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	req.Severity = "critical"
+	if res, err = service.Evaluate(ctx, req); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !res.Allowed || res.Reason != ReasonCriticalOverride {
+		t.Fatalf("expected critical to bypass global mute, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	req.Severity = ""
+	req.Timestamp = time.Date(2024, 10, 10, 16, 0, 0, 0, time.UTC)
+	if res, err = service.Evaluate(ctx, req); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !res.Allowed || res.GlobalMuted {
+		t.Fatalf("expected delivery after snooze expiry, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	// Definition-level rules still apply when the global switch is off.
+	if err := repo.Create(ctx, &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "muted",
+		DefinitionCode: "comment.created",
+		Channel:        "email",
+		Enabled:        false,
+	}); err != nil {
+		t.Fatalf("seed definition preference: %v", err)
+	}
+	if res, err = service.Evaluate(ctx, req); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonOptOut {
+		t.Fatalf("expected definition opt-out, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+}
+
+func TestServiceEvaluateCategoryCascade(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	if err := repo.Create(ctx, &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "social",
+		DefinitionCode: CategoryCode("Social"),
+		Channel:        "email",
+		Enabled:        false,
+	}); err != nil {
+		t.Fatalf("seed category preference: %v", err)
+	}
+	request := func(definition string) EvaluationRequest {
+		return EvaluationRequest{
+			DefinitionCode: definition,
+			Channel:        "email",
+			Scopes: []pkgoptions.PreferenceScopeRef{
+				{
+					Scope:       opts.NewScope("user", opts.ScopePriorityUser),
+					SubjectType: "user",
+					SubjectID:   "social",
+				},
+				{
+					Scope:          opts.NewScope("user-category", opts.ScopePriorityUser-1),
+					SubjectType:    "user",
+					SubjectID:      "social",
+					DefinitionCode: CategoryCode("social"),
+				},
+			},
+		}
+	}
+
+	res, err := service.Evaluate(ctx, request("comment.created"))
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if res.Allowed || res.Reason != ReasonOptOut {
+		t.Fatalf("expected category opt-out to cascade, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+
+	if err := repo.Create(ctx, &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "social",
+		DefinitionCode: "mention.created",
+		Channel:        "email",
+		Enabled:        true,
+	}); err != nil {
+		t.Fatalf("seed definition preference: %v", err)
+	}
+	res, err = service.Evaluate(ctx, request("mention.created"))
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !res.Allowed {
+		t.Fatalf("expected definition preference to override category, got reason=%s", res.Reason)
+	}
+}
+
+func TestServiceEvaluateGroupInheritance(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service, err := NewService(Dependencies{
+		Repository: repo,
+		Logger:     &logger.Nop{},
+		Groups: func(_ context.Context, subjectType, subjectID string) ([]string, error) {
+			if subjectType == "user" && (subjectID == "member" || subjectID == "override") {
+				return []string{"team-a"}, nil
+			}
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	seed := []domain.NotificationPreference{
+		{SubjectType: "tenant", SubjectID: "acme", DefinitionCode: "digest", Channel: "email", Enabled: true},
+		{SubjectType: "group", SubjectID: "team-a", DefinitionCode: "digest", Channel: "email", Enabled: false},
+		{SubjectType: "user", SubjectID: "override", DefinitionCode: "digest", Channel: "email", Enabled: true},
+	}
+	for i := range seed {
+		if err := repo.Create(ctx, &seed[i]); err != nil {
+			t.Fatalf("seed preference: %v", err)
+		}
+	}
+	evaluate := func(user string) EvaluationResult {
+		t.Helper()
+		res, err := service.Evaluate(ctx, EvaluationRequest{
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Scopes: []pkgoptions.PreferenceScopeRef{
+				{Scope: opts.NewScope("user", opts.ScopePriorityUser), SubjectType: "user", SubjectID: user},
+				{Scope: opts.NewScope("tenant", opts.ScopePriorityTenant), SubjectType: "tenant", SubjectID: "acme"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("evaluate %s: %v", user, err)
+		}
+		return res
+	}
+
+	if res := evaluate("member"); res.Allowed {
+		t.Fatalf("expected group default to override tenant for member")
+	}
+	if res := evaluate("override"); !res.Allowed {
+		t.Fatalf("expected explicit user setting to win over group, got reason=%s", res.Reason)
+	}
+	if res := evaluate("outsider"); !res.Allowed {
+		t.Fatalf("expected tenant default for non-member, got reason=%s", res.Reason)
+	}
+}
+
+func TestQuietHoursAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	window := parseQuietHours(map[string]any{
+		"start":    "22:00",
+		"end":      "06:00",
+		"timezone": "America/New_York",
+	})
+	utc := func(day, hour, minute int, month time.Month) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		name  string
+		at    time.Time
+		quiet bool
+	}{
+		// Spring forward: 2024-03-10 02:00 EST jumps to 03:00 EDT.
+		{"spring evening before start", time.Date(2024, 3, 9, 21, 30, 0, 0, ny), false},
+		{"spring inside before jump", time.Date(2024, 3, 10, 1, 59, 0, 0, ny), true},
+		{"spring first instant after jump", utc(10, 7, 0, time.March), true},
+		{"spring just before end", time.Date(2024, 3, 10, 5, 59, 0, 0, ny), true},
+		{"spring at end", time.Date(2024, 3, 10, 6, 0, 0, 0, ny), false},
+		{"spring midday", time.Date(2024, 3, 10, 12, 0, 0, 0, ny), false},
+		// Fall back: 2024-11-03 02:00 EDT repeats 01:00-02:00 as EST.
+		{"fall first 01:30 (EDT)", utc(3, 5, 30, time.November), true},
+		{"fall second 01:30 (EST)", utc(3, 6, 30, time.November), true},
+		{"fall just before end", utc(3, 10, 59, time.November), true},
+		{"fall at end", utc(3, 11, 0, time.November), false},
+		{"fall midday", time.Date(2024, 11, 3, 12, 0, 0, 0, ny), false},
+		{"fall night start", time.Date(2024, 11, 3, 22, 0, 0, 0, ny), true},
+	}
+	for _, tc := range cases {
+		if got := window.contains(tc.at, nil); got != tc.quiet {
+			t.Fatalf("%s (%s): expected quiet=%v, got %v", tc.name, tc.at.In(ny), tc.quiet, got)
+		}
+	}
+
+	// A window ending inside the repeated hour ends at the wall clock on
+	// both passes through it.
+	short := parseQuietHours(map[string]any{"start": "23:00", "end": "01:30", "timezone": "America/New_York"})
+	if !short.contains(utc(3, 6, 15, time.November), nil) {
+		t.Fatalf("expected 01:15 EST to be inside a window ending at 01:30")
+	}
+	if short.contains(utc(3, 6, 45, time.November), nil) {
+		t.Fatalf("expected 01:45 EST to be outside a window ending at 01:30")
+	}
+}
+
+func TestServiceEvaluateQuietHoursTimezoneFallback(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	seed := []domain.NotificationPreference{
+		{
+			SubjectType:    "user",
+			SubjectID:      "no-tz",
+			DefinitionCode: "status.update",
+			Channel:        "sms",
+			Enabled:        true,
+			QuietHours:     domain.JSONMap{"start": "22:00", "end": "06:00"},
+		},
+		{
+			SubjectType:    "user",
+			SubjectID:      "stored-locale",
+			DefinitionCode: "status.update",
+			Channel:        "sms",
+			Enabled:        true,
+			Locale:         "ja-JP",
+			QuietHours:     domain.JSONMap{"start": "22:00", "end": "06:00"},
+		},
+	}
+	for i := range seed {
+		if err := repo.Create(ctx, &seed[i]); err != nil {
+			t.Fatalf("seed preference: %v", err)
+		}
+	}
+
+	// 15:00 UTC is outside the window in UTC and New York, but midnight in Tokyo.
+	at := time.Date(2024, 10, 10, 15, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		subject  string
+		timezone string
+		locale   string
+		quiet    bool
+	}{
+		{"defaults to UTC", "no-tz", "", "", false},
+		{"request locale region", "no-tz", "", "ja-JP", true},
+		{"request timezone beats locale", "no-tz", "America/New_York", "ja-JP", false},
+		{"request timezone", "no-tz", "Asia/Tokyo", "", true},
+		{"stored preference locale", "stored-locale", "", "", true},
+		{"unknown zone is skipped", "no-tz", "Not/AZone", "ja-JP", true},
+	}
+	for _, tc := range cases {
+		res, err := service.Evaluate(ctx, EvaluationRequest{
+			DefinitionCode: "status.update",
+			Channel:        "sms",
+			Timestamp:      at,
+			Timezone:       tc.timezone,
+			Locale:         tc.locale,
+			Scopes: []pkgoptions.PreferenceScopeRef{
+				{
+					Scope:       opts.NewScope("user", opts.ScopePriorityUser),
+					SubjectType: "user",
+					SubjectID:   tc.subject,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: evaluate: %v", tc.name, err)
+		}
+		if res.QuietHoursActive != tc.quiet {
+			t.Fatalf("%s: expected quiet=%v, got %+v", tc.name, tc.quiet, res)
+		}
+	}
+}
+
+func TestTimezoneForLocale(t *testing.T) {
+	cases := map[string]string{
+		"es-MX":      "America/Mexico_City",
+		"en_US":      "America/New_York",
+		"ja-JP":      "Asia/Tokyo",
+		"zh-Hans-CN": "Asia/Shanghai",
+		"fr":         "",
+		"":           "",
+		"xx-ZZ":      "",
+	}
+	for locale, want := range cases {
+		if got := timezoneForLocale(locale); got != want {
+			t.Fatalf("timezoneForLocale(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestServiceEffective(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	seed := []domain.NotificationPreference{
+		{
+			SubjectType:    "tenant",
+			SubjectID:      "acme",
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Enabled:        true,
+			AdditionalRules: domain.JSONMap{
+				"channels": map[string]any{"email": map[string]any{"provider": "sendgrid"}},
+			},
+		},
+		{
+			SubjectType:    "user",
+			SubjectID:      "user-1",
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Enabled:        true,
+			QuietHours:     domain.JSONMap{"start": "22:00", "end": "06:00"},
+		},
+	}
+	for i := range seed {
+		if err := repo.Create(ctx, &seed[i]); err != nil {
+			t.Fatalf("seed preference: %v", err)
+		}
+	}
+	subject := Subject{UserID: "user-1", TenantID: "acme"}
+
+	state, err := service.Effective(ctx, subject, "digest", "email", time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("effective: %v", err)
+	}
+	if !state.Allowed || state.Provider != "sendgrid" {
+		t.Fatalf("expected allowed via sendgrid, got %+v", state)
+	}
+
+	state, err = service.Effective(ctx, subject, "digest", "email", time.Date(2024, 10, 10, 23, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("effective: %v", err)
+	}
+	if state.Allowed || state.Reason != ReasonQuietHours || !state.QuietHoursActive {
+		t.Fatalf("expected quiet hours to explain suppression, got %+v", state)
+	}
+}
+
+func TestServiceEffectiveCriticalBypass(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service := newTestService(t, repo)
+
+	seed := []domain.NotificationPreference{
+		{
+			SubjectType:    "user",
+			SubjectID:      "user-1",
+			DefinitionCode: "security-alert",
+			Channel:        "email",
+			Enabled:        false,
+			QuietHours:     domain.JSONMap{"start": "22:00", "end": "06:00"},
+		},
+		{
+			SubjectType:    "user",
+			SubjectID:      "user-2",
+			DefinitionCode: "security-alert",
+			Channel:        "email",
+			Enabled:        true,
+			AdditionalRules: domain.JSONMap{
+				"do_not_disturb": true,
+			},
+		},
+	}
+	for i := range seed {
+		if err := repo.Create(ctx, &seed[i]); err != nil {
+			t.Fatalf("seed preference: %v", err)
+		}
+	}
+	night := time.Date(2024, 10, 10, 23, 0, 0, 0, time.UTC)
+
+	state, err := service.Effective(ctx, Subject{UserID: "user-1"}, "security-alert", "email", night)
+	if err != nil {
+		t.Fatalf("effective: %v", err)
+	}
+	if state.Allowed {
+		t.Fatalf("expected non-critical opt-out to suppress, got %+v", state)
+	}
+
+	state, err = service.Effective(ctx, Subject{UserID: "user-1", Severity: "critical"}, "security-alert", "email", night)
+	if err != nil {
+		t.Fatalf("effective: %v", err)
+	}
+	if !state.Allowed || state.Reason != ReasonCriticalOverride || !state.QuietHoursActive {
+		t.Fatalf("expected critical to bypass opt-out and quiet hours, got %+v", state)
+	}
+
+	state, err = service.Effective(ctx, Subject{UserID: "user-2", Severity: "critical"}, "security-alert", "email", night)
+	if err != nil {
+		t.Fatalf("effective: %v", err)
+	}
+	if state.Allowed || state.Reason != ReasonDoNotDisturb {
+		t.Fatalf("expected do_not_disturb to hold for critical, got %+v", state)
+	}
+}
+
 func TestServiceEvaluateSubscriptionFilter(t *testing.T) {
 	ctx := context.Background()
 	repo := memory.NewPreferenceRepository()
@@ -230,6 +969,110 @@ func TestServiceEvaluateSubscriptionFilter(t *testing.T) {
 	}
 }
 
+func TestServiceEvaluateSubscriptionResolver(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	service, err := NewService(Dependencies{
+		Repository: repo,
+		Logger:     &logger.Nop{},
+		Subscriptions: func(_ context.Context, subjectType, subjectID string) ([]string, error) {
+			if subjectType == "user" && subjectID == "subscriber" {
+				return []string{"ops"}, nil
+			}
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if err := repo.Create(ctx, &domain.NotificationPreference{
+		SubjectType:     "tenant",
+		SubjectID:       "acme",
+		DefinitionCode:  "digest",
+		Channel:         "email",
+		Enabled:         true,
+		AdditionalRules: domain.JSONMap{"subscriptions": []string{"ops"}},
+	}); err != nil {
+		t.Fatalf("seed preference: %v", err)
+	}
+	evaluate := func(user string, supplied []string) EvaluationResult {
+		t.Helper()
+		res, err := service.Evaluate(ctx, EvaluationRequest{
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Scopes: []pkgoptions.PreferenceScopeRef{
+				{Scope: opts.NewScope("user", opts.ScopePriorityUser), SubjectType: "user", SubjectID: user},
+				{Scope: opts.NewScope("tenant", opts.ScopePriorityTenant), SubjectType: "tenant", SubjectID: "acme"},
+			},
+			Subscriptions: supplied,
+		})
+		if err != nil {
+			t.Fatalf("evaluate %s: %v", user, err)
+		}
+		return res
+	}
+
+	if res := evaluate("subscriber", nil); !res.Allowed {
+		t.Fatalf("expected resolved subscription to satisfy filter, got reason=%s", res.Reason)
+	}
+	// Caller-supplied subscriptions are ignored once a resolver is configured.
+	if res := evaluate("outsider", []string{"ops"}); res.Allowed || res.Reason != ReasonSubscriptionFilter {
+		t.Fatalf("expected filter to use recipient subscriptions, got allowed=%v reason=%s", res.Allowed, res.Reason)
+	}
+}
+
+func TestServiceUpsertValidatesProviders(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPreferenceRepository()
+	svc, err := NewService(Dependencies{
+		Repository: repo,
+		Logger:     &logger.Nop{},
+		KnownProviders: func(channel string) []string {
+			if channel == "email" {
+				return []string{"sendgrid", "smtp"}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	base := PreferenceInput{
+		SubjectType:    "tenant",
+		SubjectID:      "acme",
+		DefinitionCode: "billing.alert",
+		Channel:        "email",
+	}
+
+	bad := base
+	bad.Rules = domain.JSONMap{
+		"channels": map[string]any{
+			"email": map[string]any{"provider": "sendgird"},
+		},
+	}
+	if _, err := svc.Upsert(ctx, bad); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("expected ErrUnknownProvider, got %v", err)
+	}
+
+	root := base
+	root.Provider = new("mailgun")
+	if _, err := svc.Upsert(ctx, root); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("expected ErrUnknownProvider for root provider, got %v", err)
+	}
+
+	good := base
+	good.Rules = domain.JSONMap{
+		"channels": map[string]any{
+			"email": map[string]any{"provider": "SendGrid"},
+			"chat":  map[string]any{"provider": "anything"},
+		},
+	}
+	if _, err := svc.Upsert(ctx, good); err != nil {
+		t.Fatalf("expected known provider to be accepted: %v", err)
+	}
+}
+
 func newTestService(t *testing.T, repo *memory.PreferenceRepository) *Service {
 	t.Helper()
 	svc, err := NewService(Dependencies{
@@ -1,6 +1,9 @@
 package bunrepo
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
 	repository "github.com/goliatone/go-repository-bun"
 	"github.com/google/uuid"
@@ -39,3 +42,27 @@ func withListOptions(opts store.ListOptions) repository.SelectCriteria {
 		return q.Order("created_at ASC")
 	}
 }
+
+// likeEscaper escapes LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// withAnyTag matches rows whose JSON-encoded tags column contains any of the
+// provided tags. The column is compared as text so the same query works for
+// SQLite and Postgres jsonb; LIKE wildcards in tags match literally.
+func withAnyTag(tags []string) repository.SelectCriteria {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if len(tags) == 0 {
+			return q
+		}
+		return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, tag := range tags {
+				encoded, err := json.Marshal(tag)
+				if err != nil {
+					continue
+				}
+				q = q.WhereOr(`CAST(tags AS TEXT) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(string(encoded))+"%")
+			}
+			return q
+		})
+	}
+}
@@ -66,6 +66,25 @@ func (r *InboxRepository) ListByUser(ctx context.Context, userID string, opts st
 	return store.ListResult[domain.InboxItem]{Items: items, Total: total}, nil
 }
 
+func (r *InboxRepository) ListByUserTags(ctx context.Context, userID string, tags []string, opts store.ListOptions) (store.ListResult[domain.InboxItem], error) {
+	criteria := []repository.SelectCriteria{
+		func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("user_id = ?", userID)
+		},
+		withAnyTag(tags),
+		withListOptions(opts),
+	}
+	records, total, err := r.base.repo.List(ctx, criteria...)
+	if err != nil {
+		return store.ListResult[domain.InboxItem]{}, mapError(err)
+	}
+	items := make([]domain.InboxItem, len(records))
+	for i, rec := range records {
+		items[i] = *rec
+	}
+	return store.ListResult[domain.InboxItem]{Items: items, Total: total}, nil
+}
+
 func (r *InboxRepository) MarkRead(ctx context.Context, id uuid.UUID, read bool) error {
 	record, err := r.base.getByID(ctx, id, false)
 	if err != nil {
@@ -73,9 +92,11 @@ func (r *InboxRepository) MarkRead(ctx context.Context, id uuid.UUID, read bool)
 	}
 	record.Unread = !read
 	if read {
-		record.ReadAt = time.Now().UTC()
-	} else {
-		record.ReadAt = time.Time{}
+		now := time.Now().UTC()
+		if record.ReadAt.IsZero() {
+			record.ReadAt = now
+		}
+		record.LastReadAt = now
 	}
 	return r.base.update(ctx, record)
 }
@@ -90,6 +111,54 @@ func (r *InboxRepository) Snooze(ctx context.Context, id uuid.UUID, until time.T
 	return mapError(err)
 }
 
+func (r *InboxRepository) ListSnoozedBefore(ctx context.Context, before time.Time, limit int) ([]domain.InboxItem, error) {
+	criteria := []repository.SelectCriteria{
+		func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("snoozed_until IS NOT NULL").
+				Where("snoozed_until <= ?", before.UTC()).
+				Where("dismissed_at IS NULL").
+				Where("archived_at IS NULL").
+				Order("snoozed_until ASC")
+		},
+	}
+	if limit > 0 {
+		criteria = append(criteria, func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Limit(limit)
+		})
+	}
+	records, _, err := r.base.repo.List(ctx, criteria...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	items := make([]domain.InboxItem, len(records))
+	for i, rec := range records {
+		items[i] = *rec
+	}
+	return items, nil
+}
+
+func (r *InboxRepository) ReleaseSnooze(ctx context.Context, id uuid.UUID, before time.Time) (bool, error) {
+	res, err := r.base.db.
+		NewUpdate().
+		Model((*domain.InboxItem)(nil)).
+		Set("snoozed_until = NULL").
+		Set("unread = ?", true).
+		Where("id = ?", id).
+		Where("snoozed_until IS NOT NULL").
+		Where("snoozed_until <= ?", before).
+		Where("dismissed_at IS NULL").
+		Where("archived_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, mapError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, mapError(err)
+	}
+	return rows > 0, nil
+}
+
 func (r *InboxRepository) Dismiss(ctx context.Context, id uuid.UUID) error {
 	now := time.Now().UTC()
 	_, err := r.base.db.
@@ -102,6 +171,20 @@ func (r *InboxRepository) Dismiss(ctx context.Context, id uuid.UUID) error {
 	return mapError(err)
 }
 
+func (r *InboxRepository) SetArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	var archivedAt any
+	if archived {
+		archivedAt = time.Now().UTC()
+	}
+	_, err := r.base.db.
+		NewUpdate().
+		Model((*domain.InboxItem)(nil)).
+		Set("archived_at = ?", archivedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return mapError(err)
+}
+
 func (r *InboxRepository) CountUnread(ctx context.Context, userID string) (int, error) {
 	count, err := r.base.db.
 		NewSelect().
@@ -109,6 +192,20 @@ func (r *InboxRepository) CountUnread(ctx context.Context, userID string) (int,
 		Where("user_id = ?", userID).
 		Where("unread = TRUE").
 		Where("dismissed_at IS NULL").
+		Where("archived_at IS NULL").
+		Count(ctx)
+	return count, mapError(err)
+}
+
+func (r *InboxRepository) CountPinned(ctx context.Context, userID string) (int, error) {
+	count, err := r.base.db.
+		NewSelect().
+		Model((*domain.InboxItem)(nil)).
+		Where("user_id = ?", userID).
+		Where("pinned = TRUE").
+		Where("deleted_at IS NULL").
+		Where("dismissed_at IS NULL").
+		Where("archived_at IS NULL").
 		Count(ctx)
 	return count, mapError(err)
 }
@@ -2,7 +2,10 @@ package bunrepo
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
@@ -50,17 +53,7 @@ func (r *PreferenceRepository) SoftDelete(ctx context.Context, id uuid.UUID) err
 
 func (r *PreferenceRepository) GetBySubject(ctx context.Context, subjectType, subjectID, definitionCode, channel string) (*domain.NotificationPreference, error) {
 	record, err := r.base.repo.Get(ctx,
-		func(q *bun.SelectQuery) *bun.SelectQuery {
-			q = q.Where("LOWER(subject_type) = ?", strings.ToLower(subjectType)).
-				Where("subject_id = ?", subjectID)
-			if definitionCode != "" {
-				q = q.Where("LOWER(definition_code) = ?", strings.ToLower(definitionCode))
-			}
-			if channel != "" {
-				q = q.Where("LOWER(channel) = ?", strings.ToLower(channel))
-			}
-			return q
-		},
+		withPreferenceSubject(subjectType, subjectID, definitionCode, channel),
 		withoutDeleted(),
 	)
 	if err != nil {
@@ -68,3 +61,60 @@ func (r *PreferenceRepository) GetBySubject(ctx context.Context, subjectType, su
 	}
 	return record, nil
 }
+
+// RunInTx runs fn against a writer bound to a single database transaction.
+func (r *PreferenceRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo store.PreferenceWriter) error) error {
+	return r.base.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, preferenceTx{tx: tx})
+	})
+}
+
+func withPreferenceSubject(subjectType, subjectID, definitionCode, channel string) repository.SelectCriteria {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		q = q.Where("LOWER(subject_type) = ?", strings.ToLower(subjectType)).
+			Where("subject_id = ?", subjectID)
+		if definitionCode != "" {
+			q = q.Where("LOWER(definition_code) = ?", strings.ToLower(definitionCode))
+		}
+		if channel != "" {
+			q = q.Where("LOWER(channel) = ?", strings.ToLower(channel))
+		}
+		return q
+	}
+}
+
+// preferenceTx implements store.PreferenceWriter on top of a bun transaction.
+type preferenceTx struct {
+	tx bun.Tx
+}
+
+func (t preferenceTx) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	pref.EnsureID()
+	now := time.Now().UTC()
+	if pref.CreatedAt.IsZero() {
+		pref.CreatedAt = now
+	}
+	pref.UpdatedAt = now
+	_, err := t.tx.NewInsert().Model(pref).Exec(ctx)
+	return mapError(err)
+}
+
+func (t preferenceTx) Update(ctx context.Context, pref *domain.NotificationPreference) error {
+	pref.UpdatedAt = time.Now().UTC()
+	_, err := t.tx.NewUpdate().Model(pref).WherePK().Exec(ctx)
+	return mapError(err)
+}
+
+func (t preferenceTx) GetBySubject(ctx context.Context, subjectType, subjectID, definitionCode, channel string) (*domain.NotificationPreference, error) {
+	record := &domain.NotificationPreference{}
+	q := t.tx.NewSelect().Model(record)
+	q = withPreferenceSubject(subjectType, subjectID, definitionCode, channel)(q)
+	q = withoutDeleted()(q)
+	if err := q.Limit(1).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrNotFound
+		}
+		return nil, mapError(err)
+	}
+	return record, nil
+}
@@ -3,7 +3,10 @@ package bunrepo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
@@ -24,6 +27,8 @@ func setupSQLiteDB(t *testing.T) *bun.DB {
 	ctx := context.Background()
 	models := []any{
 		(*domain.NotificationDefinition)(nil),
+		(*domain.NotificationPreference)(nil),
+		(*domain.InboxItem)(nil),
 	}
 	for _, model := range models {
 		_, err := db.NewCreateTable().Model(model).IfNotExists().Exec(ctx)
@@ -64,3 +69,212 @@ func TestDefinitionRepositoryBun(t *testing.T) {
 		t.Fatalf("expected total 1, got %d", list.Total)
 	}
 }
+
+func TestInboxRepositoryReleaseSnooze(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := NewInboxRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	create := func(title string) *domain.InboxItem {
+		item := &domain.InboxItem{UserID: "release-user", Title: title, Body: "Body"}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := repo.Snooze(ctx, item.ID, now.Add(-time.Minute)); err != nil {
+			t.Fatalf("snooze: %v", err)
+		}
+		return item
+	}
+	live := create("live")
+	dismissed := create("dismissed")
+	archived := create("archived")
+	if err := repo.Dismiss(ctx, dismissed.ID); err != nil {
+		t.Fatalf("dismiss: %v", err)
+	}
+	if err := repo.SetArchived(ctx, archived.ID, true); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	due, err := repo.ListSnoozedBefore(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("list snoozed: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != live.ID {
+		t.Fatalf("expected only the live item due, got %+v", due)
+	}
+	for _, item := range []*domain.InboxItem{live, dismissed, archived} {
+		ok, err := repo.ReleaseSnooze(ctx, item.ID, now)
+		if err != nil {
+			t.Fatalf("release %s: %v", item.Title, err)
+		}
+		if ok != (item == live) {
+			t.Fatalf("release %s: unexpected result %v", item.Title, ok)
+		}
+	}
+	stored, err := repo.GetByID(ctx, live.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.SnoozedUntil.IsZero() || !stored.Unread || stored.Title != "live" {
+		t.Fatalf("expected snooze cleared and unread restored, got %+v", stored)
+	}
+	stored, err = repo.GetByID(ctx, dismissed.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.SnoozedUntil.IsZero() || stored.Unread {
+		t.Fatalf("expected dismissed item left alone, got %+v", stored)
+	}
+}
+
+func TestInboxRepositoryListByUserTagsMatchesLiterally(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := NewInboxRepository(db)
+	ctx := context.Background()
+
+	for _, tag := range []string{"a_b", "axb", "50%", "500", `c\d`} {
+		item := &domain.InboxItem{UserID: "tag-user", Title: tag, Body: "Body", Tags: domain.StringList{tag}}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	cases := map[string]string{
+		"a_b": "a_b",
+		"50%": "50%",
+		`c\d`: `c\d`,
+		"a%":  "",
+		"_":   "",
+		`\`:   "",
+	}
+	for query, want := range cases {
+		result, err := repo.ListByUserTags(ctx, "tag-user", []string{query}, store.ListOptions{})
+		if err != nil {
+			t.Fatalf("list %q: %v", query, err)
+		}
+		if want == "" {
+			if result.Total != 0 {
+				t.Fatalf("expected no match for %q, got %+v", query, result.Items)
+			}
+			continue
+		}
+		if result.Total != 1 || result.Items[0].Title != want {
+			t.Fatalf("expected only %q for %q, got %+v", want, query, result.Items)
+		}
+	}
+}
+
+func TestInboxRepositoryReleaseSnoozeSkipsResnoozedItem(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := NewInboxRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	item := &domain.InboxItem{UserID: "resnooze-user", Title: "Resnoozed", Body: "Body"}
+	if err := repo.Create(ctx, item); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.MarkRead(ctx, item.ID, true); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := repo.Snooze(ctx, item.ID, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("snooze: %v", err)
+	}
+	due, err := repo.ListSnoozedBefore(ctx, now, 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("expected the item due, got %+v (%v)", due, err)
+	}
+	// The user snoozes again before the release runs.
+	later := now.Add(time.Hour)
+	if err := repo.Snooze(ctx, item.ID, later); err != nil {
+		t.Fatalf("resnooze: %v", err)
+	}
+
+	ok, err := repo.ReleaseSnooze(ctx, item.ID, now)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected a later snooze not to be released")
+	}
+	stored, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !stored.SnoozedUntil.After(now) || stored.Unread {
+		t.Fatalf("expected later snooze kept and item still read, got %+v", stored)
+	}
+}
+
+func TestInboxRepositoryCountPinnedSkipsDeleted(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := NewInboxRepository(db)
+	ctx := context.Background()
+
+	var items []*domain.InboxItem
+	for _, title := range []string{"kept", "deleted"} {
+		item := &domain.InboxItem{UserID: "pin-user", Title: title, Body: "Body", Pinned: true}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		items = append(items, item)
+	}
+	if err := repo.SoftDelete(ctx, items[1].ID); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	count, err := repo.CountPinned(ctx, "pin-user")
+	if err != nil {
+		t.Fatalf("count pinned: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected soft deleted pin not counted, got %d", count)
+	}
+}
+
+func TestPreferenceRepositoryRunInTxRollsBack(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := NewPreferenceRepository(db)
+	ctx := context.Background()
+
+	errBoom := errors.New("boom")
+	err := repo.RunInTx(ctx, func(ctx context.Context, tx store.PreferenceWriter) error {
+		if err := tx.Create(ctx, &domain.NotificationPreference{
+			SubjectType:    "user",
+			SubjectID:      "tx-user",
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Enabled:        true,
+		}); err != nil {
+			return err
+		}
+		if _, err := tx.GetBySubject(ctx, "user", "tx-user", "digest", "email"); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if _, err := repo.GetBySubject(ctx, "user", "tx-user", "digest", "email"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected rollback, got %v", err)
+	}
+
+	err = repo.RunInTx(ctx, func(ctx context.Context, tx store.PreferenceWriter) error {
+		if _, err := tx.GetBySubject(ctx, "user", "tx-user", "digest", "email"); !errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("expected not found inside tx, got %v", err)
+		}
+		return tx.Create(ctx, &domain.NotificationPreference{
+			SubjectType:    "user",
+			SubjectID:      "tx-user",
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Enabled:        true,
+		})
+	})
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if _, err := repo.GetBySubject(ctx, "user", "tx-user", "digest", "email"); err != nil {
+		t.Fatalf("expected committed preference, got %v", err)
+	}
+}
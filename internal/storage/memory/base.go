@@ -57,6 +57,26 @@ func (r *baseMemoryRepo[T]) update(ctx context.Context, record *T) error {
 	return nil
 }
 
+// peek returns the stored record for id as is, including soft deleted ones.
+func (r *baseMemoryRepo[T]) peek(id uuid.UUID) (T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	record, ok := r.records[id]
+	return record, ok
+}
+
+// revert puts back a record captured by peek, or removes id when it was not
+// stored at the time.
+func (r *baseMemoryRepo[T]) revert(id uuid.UUID, record T, existed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existed {
+		r.records[id] = record
+		return
+	}
+	delete(r.records, id)
+}
+
 func (r *baseMemoryRepo[T]) getByID(ctx context.Context, id uuid.UUID, includeDeleted bool) (*T, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
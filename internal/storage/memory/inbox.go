@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/goliatone/go-notifications/pkg/domain"
@@ -53,6 +54,39 @@ func (r *InboxRepository) ListByUser(ctx context.Context, userID string, opts st
 	return store.ListResult[domain.InboxItem]{Items: filtered, Total: len(filtered)}, nil
 }
 
+func (r *InboxRepository) ListByUserTags(ctx context.Context, userID string, tags []string, opts store.ListOptions) (store.ListResult[domain.InboxItem], error) {
+	result, err := r.base.list(ctx, store.ListOptions{
+		Since:              opts.Since,
+		Until:              opts.Until,
+		IncludeSoftDeleted: opts.IncludeSoftDeleted,
+	})
+	if err != nil {
+		return store.ListResult[domain.InboxItem]{}, err
+	}
+	filtered := make([]domain.InboxItem, 0, len(result.Items))
+	for _, item := range result.Items {
+		if item.UserID == userID && hasAnyTag(item.Tags, tags) {
+			filtered = append(filtered, item)
+		}
+	}
+	total := len(filtered)
+	start := min(opts.Offset, total)
+	end := total
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+	return store.ListResult[domain.InboxItem]{Items: filtered[start:end], Total: total}, nil
+}
+
+func hasAnyTag(itemTags domain.StringList, tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(itemTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *InboxRepository) MarkRead(ctx context.Context, id uuid.UUID, read bool) error {
 	item, err := r.base.getByID(ctx, id, false)
 	if err != nil {
@@ -60,9 +94,11 @@ func (r *InboxRepository) MarkRead(ctx context.Context, id uuid.UUID, read bool)
 	}
 	item.Unread = !read
 	if read {
-		item.ReadAt = time.Now().UTC()
-	} else {
-		item.ReadAt = time.Time{}
+		now := time.Now().UTC()
+		if item.ReadAt.IsZero() {
+			item.ReadAt = now
+		}
+		item.LastReadAt = now
 	}
 	return r.base.update(ctx, item)
 }
@@ -76,6 +112,48 @@ func (r *InboxRepository) Snooze(ctx context.Context, id uuid.UUID, until time.T
 	return r.base.update(ctx, item)
 }
 
+func (r *InboxRepository) ListSnoozedBefore(ctx context.Context, before time.Time, limit int) ([]domain.InboxItem, error) {
+	result, err := r.base.list(ctx, store.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	due := make([]domain.InboxItem, 0)
+	for _, item := range result.Items {
+		if item.SnoozedUntil.IsZero() || item.SnoozedUntil.After(before) {
+			continue
+		}
+		if !item.DismissedAt.IsZero() || !item.ArchivedAt.IsZero() {
+			continue
+		}
+		due = append(due, item)
+		if limit > 0 && len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (r *InboxRepository) ReleaseSnooze(ctx context.Context, id uuid.UUID, before time.Time) (bool, error) {
+	r.base.mu.Lock()
+	defer r.base.mu.Unlock()
+
+	item, ok := r.base.records[id]
+	if !ok || !item.DeletedAt.IsZero() {
+		return false, store.ErrNotFound
+	}
+	if item.SnoozedUntil.IsZero() || item.SnoozedUntil.After(before) {
+		return false, nil
+	}
+	if !item.DismissedAt.IsZero() || !item.ArchivedAt.IsZero() {
+		return false, nil
+	}
+	item.SnoozedUntil = time.Time{}
+	item.Unread = true
+	item.UpdatedAt = time.Now().UTC()
+	r.base.records[id] = item
+	return true, nil
+}
+
 func (r *InboxRepository) Dismiss(ctx context.Context, id uuid.UUID) error {
 	item, err := r.base.getByID(ctx, id, false)
 	if err != nil {
@@ -86,13 +164,39 @@ func (r *InboxRepository) Dismiss(ctx context.Context, id uuid.UUID) error {
 	return r.base.update(ctx, item)
 }
 
+func (r *InboxRepository) SetArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	item, err := r.base.getByID(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	if archived {
+		item.ArchivedAt = time.Now().UTC()
+	} else {
+		item.ArchivedAt = time.Time{}
+	}
+	return r.base.update(ctx, item)
+}
+
 func (r *InboxRepository) CountUnread(ctx context.Context, userID string) (int, error) {
 	r.base.mu.RLock()
 	defer r.base.mu.RUnlock()
 
 	count := 0
 	for _, item := range r.base.records {
-		if item.UserID == userID && item.Unread && item.DismissedAt.IsZero() {
+		if item.UserID == userID && item.Unread && item.DismissedAt.IsZero() && item.ArchivedAt.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InboxRepository) CountPinned(ctx context.Context, userID string) (int, error) {
+	r.base.mu.RLock()
+	defer r.base.mu.RUnlock()
+
+	count := 0
+	for _, item := range r.base.records {
+		if item.UserID == userID && item.Pinned && item.DeletedAt.IsZero() && item.DismissedAt.IsZero() && item.ArchivedAt.IsZero() {
 			count++
 		}
 	}
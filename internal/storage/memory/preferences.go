@@ -62,3 +62,78 @@ func (r *PreferenceRepository) GetBySubject(ctx context.Context, subjectType, su
 	}
 	return r.base.getByID(ctx, id, false)
 }
+
+// RunInTx runs fn against the repository and, when fn fails, reverts the
+// records and subject keys fn wrote, emulating an all-or-nothing transaction.
+// Writes made outside fn in the meantime are left alone.
+func (r *PreferenceRepository) RunInTx(ctx context.Context, fn func(ctx context.Context, repo store.PreferenceWriter) error) error {
+	tx := &preferenceTx{
+		repo:    r,
+		records: make(map[uuid.UUID]preferenceUndo),
+		keys:    make(map[string]bool),
+	}
+	if err := fn(ctx, tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	return nil
+}
+
+type preferenceUndo struct {
+	record  domain.NotificationPreference
+	existed bool
+}
+
+// preferenceTx journals the prior state of every record and subject key it
+// touches so a failed transaction can undo just those.
+type preferenceTx struct {
+	repo    *PreferenceRepository
+	records map[uuid.UUID]preferenceUndo
+	keys    map[string]bool
+}
+
+func (tx *preferenceTx) Create(ctx context.Context, pref *domain.NotificationPreference) error {
+	key := prefKey(pref.SubjectType, pref.SubjectID, pref.DefinitionCode, pref.Channel)
+	if _, seen := tx.keys[key]; !seen {
+		_, existed := tx.repo.bySubject[key]
+		tx.keys[key] = existed
+	}
+	if pref.ID != uuid.Nil {
+		tx.remember(pref.ID)
+	}
+	if err := tx.repo.Create(ctx, pref); err != nil {
+		return err
+	}
+	if _, seen := tx.records[pref.ID]; !seen {
+		tx.records[pref.ID] = preferenceUndo{}
+	}
+	return nil
+}
+
+func (tx *preferenceTx) Update(ctx context.Context, pref *domain.NotificationPreference) error {
+	tx.remember(pref.ID)
+	return tx.repo.Update(ctx, pref)
+}
+
+func (tx *preferenceTx) GetBySubject(ctx context.Context, subjectType, subjectID, definitionCode, channel string) (*domain.NotificationPreference, error) {
+	return tx.repo.GetBySubject(ctx, subjectType, subjectID, definitionCode, channel)
+}
+
+func (tx *preferenceTx) remember(id uuid.UUID) {
+	if _, seen := tx.records[id]; seen {
+		return
+	}
+	record, existed := tx.repo.base.peek(id)
+	tx.records[id] = preferenceUndo{record: record, existed: existed}
+}
+
+func (tx *preferenceTx) rollback() {
+	for id, undo := range tx.records {
+		tx.repo.base.revert(id, undo.record, undo.existed)
+	}
+	for key, existed := range tx.keys {
+		if !existed {
+			delete(tx.repo.bySubject, key)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/goliatone/go-notifications/pkg/domain"
@@ -37,3 +38,65 @@ func TestDefinitionRepositoryMemory(t *testing.T) {
 		t.Fatalf("expected total 1, got %d", result.Total)
 	}
 }
+
+func TestPreferenceRepositoryRunInTxRollsBackOnlyTouchedKeys(t *testing.T) {
+	repo := NewPreferenceRepository()
+	ctx := context.Background()
+
+	existing := &domain.NotificationPreference{
+		SubjectType:    "user",
+		SubjectID:      "tx-user",
+		DefinitionCode: "digest",
+		Channel:        "email",
+		Enabled:        true,
+	}
+	if err := repo.Create(ctx, existing); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	errBoom := errors.New("boom")
+	err := repo.RunInTx(ctx, func(ctx context.Context, tx store.PreferenceWriter) error {
+		updated := *existing
+		updated.Enabled = false
+		if err := tx.Update(ctx, &updated); err != nil {
+			return err
+		}
+		if err := tx.Create(ctx, &domain.NotificationPreference{
+			SubjectType:    "user",
+			SubjectID:      "tx-user",
+			DefinitionCode: "digest",
+			Channel:        "sms",
+			Enabled:        true,
+		}); err != nil {
+			return err
+		}
+		// A write made outside the transaction while it runs.
+		if err := repo.Create(ctx, &domain.NotificationPreference{
+			SubjectType:    "user",
+			SubjectID:      "other-user",
+			DefinitionCode: "digest",
+			Channel:        "email",
+			Enabled:        true,
+		}); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+
+	got, err := repo.GetBySubject(ctx, "user", "tx-user", "digest", "email")
+	if err != nil {
+		t.Fatalf("get existing: %v", err)
+	}
+	if !got.Enabled {
+		t.Fatalf("expected update to be rolled back")
+	}
+	if _, err := repo.GetBySubject(ctx, "user", "tx-user", "digest", "sms"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected created preference to be rolled back, got %v", err)
+	}
+	if _, err := repo.GetBySubject(ctx, "user", "other-user", "digest", "email"); err != nil {
+		t.Fatalf("expected outside write to survive rollback, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MetadataBlocksKey is the rendered metadata key chat adapters read Block Kit
+// layouts from. Templates declare blocks under the same metadata key, or under
+// "slack_blocks" in a gocms-block source payload (where "blocks" already holds
+// CMS content blocks).
+const MetadataBlocksKey = "blocks"
+
+const sourceBlocksKey = "slack_blocks"
+
+// Blocks returns the raw, unrendered chat blocks declared by the variant.
+func (v *templateVariant) Blocks() any {
+	if v == nil {
+		return nil
+	}
+	if raw, ok := v.template.Metadata[MetadataBlocksKey]; ok && raw != nil {
+		return raw
+	}
+	if strings.EqualFold(v.template.Source.Type, sourceTypeGoCMSBlock) && v.template.Source.Payload != nil {
+		return v.template.Source.Payload[sourceBlocksKey]
+	}
+	return nil
+}
+
+// renderBlocks renders every string in a block structure with the template
+// payload, so values are interpolated without breaking JSON escaping. A
+// block list given as a JSON string is rendered first and then decoded.
+// Callers must hold renderMu.
+func (s *Service) renderBlocks(raw any, payload map[string]any) (any, error) {
+	if text, ok := raw.(string); ok {
+		rendered, err := s.renderer.RenderString(text, payload)
+		if err != nil {
+			return nil, err
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(rendered), &decoded); err != nil {
+			return nil, fmt.Errorf("decode blocks json: %w", err)
+		}
+		return decoded, nil
+	}
+	return s.renderBlockValue(raw, payload)
+}
+
+func (s *Service) renderBlockValue(value any, payload map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		return s.renderer.RenderString(v, payload)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := s.renderBlockValue(item, payload)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := s.renderBlockValue(item, payload)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			rendered, err := s.renderBlockValue(item, payload)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
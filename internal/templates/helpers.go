@@ -3,14 +3,19 @@ package templates
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	i18n "github.com/goliatone/go-i18n"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/links"
+	"golang.org/x/text/language"
 )
 
 func defaultHelperFuncs() map[string]any {
 	return map[string]any{
-		"secure_link": secureLink,
+		"secure_link":  secureLink,
+		"link_expired": linkExpired,
+		"bidi_isolate": bidiIsolate,
 	}
 }
 
@@ -53,6 +58,91 @@ func secureLink(args ...any) string {
 	return ""
 }
 
+// linkExpired reports whether the action link carried by the template data
+// (link_expires_at) has expired. It also accepts the expiry value directly.
+// Links without an expiry never expire.
+func linkExpired(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		value = v[links.ResolvedExpiresAtKey]
+	case domain.JSONMap:
+		value = v[links.ResolvedExpiresAtKey]
+	}
+	expiresAt, ok := timeFromTemplateValue(value)
+	if !ok {
+		return false
+	}
+	return !time.Now().Before(expiresAt)
+}
+
+// rtlScripts lists the ISO 15924 scripts Unicode lays out right to left.
+var rtlScripts = map[string]struct{}{
+	"Adlm": {}, "Arab": {}, "Hebr": {}, "Mand": {}, "Nkoo": {},
+	"Rohg": {}, "Samr": {}, "Syrc": {}, "Thaa": {}, "Yezi": {},
+}
+
+func directionFormatter(catalog *i18n.LocaleCatalog) func(string) string {
+	return func(locale string) string {
+		return formatDirection(catalog, locale)
+	}
+}
+
+// formatDirection returns "rtl" or "ltr" for a locale such as "ar-EG". The
+// "rtl" metadata flag of the matching catalog locale wins; otherwise the
+// direction follows the locale's script, either explicit ("az-Arab") or the
+// CLDR likely script for the language.
+func formatDirection(catalog *i18n.LocaleCatalog, locale string) string {
+	if meta, ok := catalog.Match(locale); ok {
+		if rtl, ok := meta.Metadata["rtl"].(bool); ok {
+			if rtl {
+				return "rtl"
+			}
+			return "ltr"
+		}
+	}
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil {
+		return "ltr"
+	}
+	script, _ := tag.Script()
+	if _, ok := rtlScripts[script.String()]; ok {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Unicode directional isolates (UAX #9).
+const (
+	bidiLRI = "\u2066"
+	bidiRLI = "\u2067"
+	bidiFSI = "\u2068"
+	bidiPDI = "\u2069"
+)
+
+// bidiIsolate wraps value in Unicode isolates so interpolated text cannot
+// reorder the surrounding sentence. The optional direction ("rtl" or "ltr")
+// forces the isolate; otherwise the direction follows the first strong
+// character.
+func bidiIsolate(value any, direction ...string) string {
+	text := ""
+	if value != nil {
+		text = fmt.Sprint(value)
+	}
+	if text == "" {
+		return ""
+	}
+	open := bidiFSI
+	if len(direction) > 0 {
+		switch strings.ToLower(strings.TrimSpace(direction[0])) {
+		case "rtl":
+			open = bidiRLI
+		case "ltr":
+			open = bidiLRI
+		}
+	}
+	return open + text + bidiPDI
+}
+
 func stringFromTemplateValue(value any) string {
 	if value == nil {
 		return ""
@@ -55,6 +55,13 @@ func (v *templateVariant) Body() string {
 	return sourceField(v.template.Source, "body")
 }
 
+func (v *templateVariant) Format() string {
+	if v == nil {
+		return ""
+	}
+	return v.template.Format
+}
+
 func (v *templateVariant) Metadata() domain.JSONMap {
 	if v == nil {
 		return nil
@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relativeUnit holds the CLDR relative-time patterns for one unit. The
+// singular/plural pairs cover the "one" and "other" categories, which is all
+// en and es need.
+type relativeUnit struct {
+	pastOne, pastOther     string
+	futureOne, futureOther string
+}
+
+type relativeWording struct {
+	now   string
+	units map[time.Duration]relativeUnit
+}
+
+const (
+	relativeDay   = 24 * time.Hour
+	relativeWeek  = 7 * relativeDay
+	relativeMonth = 30 * relativeDay
+	relativeYear  = 365 * relativeDay
+)
+
+// relativeSteps orders units from largest to smallest.
+var relativeSteps = []time.Duration{relativeYear, relativeMonth, relativeWeek, relativeDay, time.Hour, time.Minute, time.Second}
+
+var relativeLocales = map[string]relativeWording{
+	"en": {
+		now: "now",
+		units: map[time.Duration]relativeUnit{
+			time.Second:   {"{0} second ago", "{0} seconds ago", "in {0} second", "in {0} seconds"},
+			time.Minute:   {"{0} minute ago", "{0} minutes ago", "in {0} minute", "in {0} minutes"},
+			time.Hour:     {"{0} hour ago", "{0} hours ago", "in {0} hour", "in {0} hours"},
+			relativeDay:   {"{0} day ago", "{0} days ago", "in {0} day", "in {0} days"},
+			relativeWeek:  {"{0} week ago", "{0} weeks ago", "in {0} week", "in {0} weeks"},
+			relativeMonth: {"{0} month ago", "{0} months ago", "in {0} month", "in {0} months"},
+			relativeYear:  {"{0} year ago", "{0} years ago", "in {0} year", "in {0} years"},
+		},
+	},
+	"es": {
+		now: "ahora",
+		units: map[time.Duration]relativeUnit{
+			time.Second:   {"hace {0} segundo", "hace {0} segundos", "dentro de {0} segundo", "dentro de {0} segundos"},
+			time.Minute:   {"hace {0} minuto", "hace {0} minutos", "dentro de {0} minuto", "dentro de {0} minutos"},
+			time.Hour:     {"hace {0} hora", "hace {0} horas", "dentro de {0} hora", "dentro de {0} horas"},
+			relativeDay:   {"hace {0} día", "hace {0} días", "dentro de {0} día", "dentro de {0} días"},
+			relativeWeek:  {"hace {0} semana", "hace {0} semanas", "dentro de {0} semana", "dentro de {0} semanas"},
+			relativeMonth: {"hace {0} mes", "hace {0} meses", "dentro de {0} mes", "dentro de {0} meses"},
+			relativeYear:  {"hace {0} año", "hace {0} años", "dentro de {0} año", "dentro de {0} años"},
+		},
+	},
+}
+
+// relativeFormatter builds the format_relative helper. Locales resolve
+// through chain (the service fallback chain for the locale) and then the
+// base language, ending at en when no wording matches. go-i18n's CLDR bundles
+// have no relative-time patterns, so every locale other than en and es
+// renders English wording.
+func relativeFormatter(chain func(locale string) []string) func(locale string, value any) string {
+	return func(locale string, value any) string {
+		at, ok := timeFromTemplateValue(value)
+		if !ok {
+			return ""
+		}
+		return formatRelative(relativeWordingFor(chain(locale)), time.Until(at))
+	}
+}
+
+func relativeWordingFor(candidates []string) relativeWording {
+	for _, candidate := range candidates {
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(candidate), "_", "-"))
+		for key != "" {
+			if wording, ok := relativeLocales[key]; ok {
+				return wording
+			}
+			idx := strings.LastIndex(key, "-")
+			if idx < 0 {
+				break
+			}
+			key = key[:idx]
+		}
+	}
+	return relativeLocales["en"]
+}
+
+func formatRelative(wording relativeWording, delta time.Duration) string {
+	future := delta > 0
+	abs := delta
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs < time.Second {
+		return wording.now
+	}
+	idx := len(relativeSteps) - 1
+	for i, step := range relativeSteps {
+		if abs >= step {
+			idx = i
+			break
+		}
+	}
+	unit := relativeSteps[idx]
+	count := int64(math.Round(float64(abs) / float64(unit)))
+	// Rounding can reach the next unit (59.6 minutes is 60 minutes); report
+	// it in that unit instead ("1 hour").
+	for idx > 0 && time.Duration(count)*unit >= relativeSteps[idx-1] {
+		idx--
+		unit = relativeSteps[idx]
+		count = int64(math.Round(float64(abs) / float64(unit)))
+	}
+	patterns := wording.units[unit]
+	pattern := patterns.pastOther
+	switch {
+	case future && count == 1:
+		pattern = patterns.futureOne
+	case future:
+		pattern = patterns.futureOther
+	case count == 1:
+		pattern = patterns.pastOne
+	}
+	return strings.Replace(pattern, "{0}", strconv.FormatInt(count, 10), 1)
+}
+
+func timeFromTemplateValue(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, !v.IsZero()
+	case string:
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(v))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, true
+	}
+	return time.Time{}, false
+}
@@ -37,6 +37,7 @@ type RenderResult struct {
 	Subject      string
 	Body         string
 	Locale       string
+	Format       string
 	Revision     int
 	Metadata     domain.JSONMap
 	Source       domain.TemplateSource
@@ -50,6 +51,7 @@ type serviceOptions struct {
 	rendererOpts   []gotemplate.Option
 	missingHandler i18n.MissingTranslationHandler
 	localeKey      string
+	localeCatalog  *i18n.LocaleCatalog
 }
 
 // Option configures the template service.
@@ -103,6 +105,14 @@ func WithMissingTranslationHandler(handler i18n.MissingTranslationHandler) Optio
 	}
 }
 
+// WithLocaleCatalog supplies go-i18n locale metadata; format_direction reads
+// the "rtl" flag of the matching locale from it.
+func WithLocaleCatalog(catalog *i18n.LocaleCatalog) Option {
+	return func(so *serviceOptions) {
+		so.localeCatalog = catalog
+	}
+}
+
 // NewService builds the template service wiring the helper registry, renderer,
 // and localization translator together.
 func NewService(translator i18n.Translator, opts ...Option) (*Service, error) {
@@ -156,6 +166,10 @@ func NewService(translator i18n.Translator, opts ...Option) (*Service, error) {
 	}
 	service.helpers.Register(i18n.TemplateHelpers(translator, helperCfg))
 	service.helpers.Register(defaultHelperFuncs())
+	service.helpers.Register(map[string]any{
+		"format_relative":  relativeFormatter(service.localeChain),
+		"format_direction": directionFormatter(settings.localeCatalog),
+	})
 
 	for _, funcs := range settings.helperFuncs {
 		service.helpers.Register(funcs)
@@ -220,17 +234,31 @@ func (s *Service) Render(ctx context.Context, req RenderRequest) (RenderResult,
 		return RenderResult{}, fmt.Errorf("templates: render subject: %w", err)
 	}
 	body, err := s.renderer.RenderString(variant.Body(), payload)
-	s.renderMu.Unlock()
 	if err != nil {
+		s.renderMu.Unlock()
 		return RenderResult{}, fmt.Errorf("templates: render body: %w", err)
 	}
+	metadata := variant.Metadata()
+	if raw := variant.Blocks(); raw != nil {
+		blocks, err := s.renderBlocks(raw, payload)
+		if err != nil {
+			s.renderMu.Unlock()
+			return RenderResult{}, fmt.Errorf("templates: render blocks: %w", err)
+		}
+		if metadata == nil {
+			metadata = make(domain.JSONMap)
+		}
+		metadata[MetadataBlocksKey] = blocks
+	}
+	s.renderMu.Unlock()
 
 	return RenderResult{
 		Subject:      subject,
 		Body:         body,
 		Locale:       resolvedLocale,
+		Format:       variant.Format(),
 		Revision:     variant.Revision(),
-		Metadata:     variant.Metadata(),
+		Metadata:     metadata,
 		Source:       variant.Source(),
 		UsedFallback: !strings.EqualFold(resolvedLocale, strings.TrimSpace(req.Locale)),
 	}, nil
@@ -38,6 +38,12 @@ func uniqueStrings(values []string) []string {
 	return result
 }
 
+// ValidateSchema reports the required fields missing from data as a
+// SchemaError. Dotted fields address nested maps.
+func ValidateSchema(schema domain.TemplateSchema, data map[string]any) error {
+	return validateSchemaData(sanitizeSchema(schema), data)
+}
+
 func validateSchemaData(schema domain.TemplateSchema, data map[string]any) error {
 	if schema.IsZero() {
 		return nil
@@ -0,0 +1,469 @@
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+const (
+	ProductionEndpoint = "https://api.push.apple.com"
+	SandboxEndpoint    = "https://api.sandbox.push.apple.com"
+
+	// tokenLifetime keeps provider tokens inside Apple's 20-60 minute
+	// refresh window.
+	tokenLifetime = 50 * time.Minute
+)
+
+var (
+	// ErrUnregistered reports a device token APNs no longer accepts (410
+	// Unregistered). Hosts can match it with errors.Is to prune tokens.
+	ErrUnregistered = errors.New("apns: device token is no longer active")
+	// ErrBadDeviceToken reports a malformed token or one issued for the other
+	// environment (sandbox vs production).
+	ErrBadDeviceToken = errors.New("apns: bad device token")
+)
+
+// Adapter delivers push notifications through the APNs HTTP/2 provider API
+// using token-based (p8 key) authentication.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]providerToken
+}
+
+// Config holds APNs settings. TeamID, KeyID and PrivateKey are used when the
+// resolved secrets do not carry them.
+type Config struct {
+	TeamID     string
+	KeyID      string
+	PrivateKey string // contents of the AuthKey_<KeyID>.p8 file
+	Topic      string // app bundle id
+	Production bool
+	Endpoint   string // overrides the Production/sandbox endpoint
+	PushType   string // defaults to "alert"
+	Timeout    time.Duration
+	Transport  adapters.HTTPTransportConfig
+	DryRun     bool
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets APNs configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithClient injects a custom HTTP client. APNs only accepts HTTP/2, which
+// the default client negotiates over TLS.
+func WithClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the APNs adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "apns",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:          "apns",
+			Channels:      []string{"push"},
+			Formats:       []string{"text/plain"},
+			AutoDowngrade: true,
+		},
+		cfg: Config{
+			PushType: "alert",
+			Timeout:  10 * time.Second,
+		},
+		tokens: make(map[string]providerToken),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	deviceToken := firstNonEmpty(stringValue(msg.Metadata, "token"), strings.TrimSpace(msg.To))
+	if deviceToken == "" {
+		return fmt.Errorf("apns: device token required")
+	}
+	topic := firstNonEmpty(stringValue(msg.Metadata, "topic"), a.cfg.Topic)
+	if topic == "" {
+		return fmt.Errorf("apns: topic (bundle id) required")
+	}
+	payload, err := buildPayload(msg)
+	if err != nil {
+		return err
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[apns:during-dry-run] send skipped",
+			"to", deviceToken,
+			"topic", topic,
+			"subject", msg.Subject,
+		)
+		return nil
+	}
+
+	creds, err := a.credentials(msg.Metadata)
+	if err != nil {
+		return err
+	}
+	jwt, err := a.providerToken(creds, time.Now())
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := adapters.EncodeJSONPayload("apns", payload)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/3/device/%s", strings.TrimRight(a.endpoint(), "/"), url.PathEscape(deviceToken))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("apns: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+jwt)
+	req.Header.Set("apns-topic", topic)
+	req.Header.Set("apns-push-type", firstNonEmpty(stringValue(msg.Metadata, "push_type"), a.cfg.PushType, "alert"))
+	if priority := stringValue(msg.Metadata, "priority"); priority != "" {
+		req.Header.Set("apns-priority", priority)
+	}
+	if collapseID := stringValue(msg.Metadata, "collapse_id"); collapseID != "" {
+		req.Header.Set("apns-collapse-id", collapseID)
+	}
+	if expiration := stringValue(msg.Metadata, "expiration"); expiration != "" {
+		req.Header.Set("apns-expiration", expiration)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := errorReason(respBody)
+		if reason == "ExpiredProviderToken" || reason == "InvalidProviderToken" {
+			a.forgetToken(creds)
+		}
+		return classifyError(resp, respBody, reason)
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+func (a *Adapter) endpoint() string {
+	if endpoint := strings.TrimSpace(a.cfg.Endpoint); endpoint != "" {
+		return endpoint
+	}
+	if a.cfg.Production {
+		return ProductionEndpoint
+	}
+	return SandboxEndpoint
+}
+
+// buildPayload maps the message onto aps.alert and merges metadata data
+// keys next to aps, where the app reads them as custom payload.
+func buildPayload(msg adapters.Message) (map[string]any, error) {
+	alert := map[string]any{}
+	if title := strings.TrimSpace(msg.Subject); title != "" {
+		alert["title"] = title
+	}
+	if subtitle := stringValue(msg.Metadata, "subtitle"); subtitle != "" {
+		alert["subtitle"] = subtitle
+	}
+	if body := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body); strings.TrimSpace(body) != "" {
+		alert["body"] = body
+	}
+
+	aps := map[string]any{}
+	if len(alert) > 0 {
+		aps["alert"] = alert
+	}
+	if sound := stringValue(msg.Metadata, "sound"); sound != "" {
+		aps["sound"] = sound
+	}
+	if badge := stringValue(msg.Metadata, "badge"); badge != "" {
+		count, err := strconv.Atoi(badge)
+		if err != nil {
+			return nil, adapters.Permanent(fmt.Errorf("apns: badge must be an integer, got %q", badge))
+		}
+		aps["badge"] = count
+	}
+	if threadID := stringValue(msg.Metadata, "thread_id"); threadID != "" {
+		aps["thread-id"] = threadID
+	}
+	if category := stringValue(msg.Metadata, "category"); category != "" {
+		aps["category"] = category
+	}
+	if boolValue(msg.Metadata, "content_available") {
+		aps["content-available"] = 1
+	}
+	if boolValue(msg.Metadata, "mutable_content") {
+		aps["mutable-content"] = 1
+	}
+
+	payload := map[string]any{}
+	switch custom := msg.Metadata["data"].(type) {
+	case nil:
+	case map[string]any:
+		for key, value := range custom {
+			payload[key] = value
+		}
+	case map[string]string:
+		for key, value := range custom {
+			payload[key] = value
+		}
+	default:
+		return nil, fmt.Errorf("apns: data must be a map, got %T", custom)
+	}
+	if len(aps) == 0 && len(payload) == 0 {
+		return nil, fmt.Errorf("apns: alert content or data required")
+	}
+	payload["aps"] = aps
+	return payload, nil
+}
+
+// classifyError maps APNs reasons onto the retry contract. Dead and malformed
+// tokens are permanent so the dispatcher does not retry them.
+func classifyError(resp *http.Response, body []byte, reason string) error {
+	statusErr := adapters.HTTPStatusError("apns", resp.StatusCode, body)
+	retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+	switch reason {
+	case "Unregistered", "ExpiredToken":
+		return adapters.Permanent(fmt.Errorf("%w: %w", ErrUnregistered, statusErr))
+	case "BadDeviceToken", "DeviceTokenNotForTopic":
+		return adapters.Permanent(fmt.Errorf("%w: %w", ErrBadDeviceToken, statusErr))
+	case "ExpiredProviderToken":
+		// The cached JWT is discarded; the next attempt signs a fresh one.
+		return adapters.Retryable(statusErr, 0)
+	case "TooManyRequests", "InternalServerError", "ServiceUnavailable", "Shutdown":
+		return adapters.Retryable(statusErr, retryAfter)
+	}
+	return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, retryAfter)
+}
+
+func errorReason(body []byte) string {
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.Reason
+}
+
+type credentials struct {
+	TeamID     string
+	KeyID      string
+	PrivateKey string
+}
+
+func (c credentials) cacheKey() string { return c.TeamID + "/" + c.KeyID }
+
+func (a *Adapter) credentials(meta map[string]any) (credentials, error) {
+	creds := credentials{
+		TeamID:     firstNonEmpty(secretString(meta, "team_id"), a.cfg.TeamID),
+		KeyID:      firstNonEmpty(secretString(meta, "key_id"), a.cfg.KeyID),
+		PrivateKey: firstNonEmpty(secretString(meta, "private_key"), secretString(meta, "default"), a.cfg.PrivateKey),
+	}
+	if strings.TrimSpace(creds.PrivateKey) == "" {
+		return credentials{}, fmt.Errorf("apns: p8 private key required")
+	}
+	if strings.TrimSpace(creds.TeamID) == "" || strings.TrimSpace(creds.KeyID) == "" {
+		return credentials{}, fmt.Errorf("apns: team id and key id required")
+	}
+	return creds, nil
+}
+
+type providerToken struct {
+	value  string
+	issued time.Time
+}
+
+// providerToken returns a cached ES256 JWT for the team/key pair, signing a
+// new one when the cached token is older than tokenLifetime.
+func (a *Adapter) providerToken(creds credentials, now time.Time) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[creds.cacheKey()]
+	a.mu.Unlock()
+	if ok && now.Sub(cached.issued) < tokenLifetime {
+		return cached.value, nil
+	}
+
+	token, err := signToken(creds, now)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.tokens[creds.cacheKey()] = providerToken{value: token, issued: now}
+	a.mu.Unlock()
+	return token, nil
+}
+
+func (a *Adapter) forgetToken(creds credentials) {
+	a.mu.Lock()
+	delete(a.tokens, creds.cacheKey())
+	a.mu.Unlock()
+}
+
+func signToken(creds credentials, now time.Time) (string, error) {
+	key, err := parsePrivateKey(creds.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	headerJSON, err := json.Marshal(map[string]string{"alg": "ES256", "kid": creds.KeyID})
+	if err != nil {
+		return "", fmt.Errorf("apns: encode jwt header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(map[string]any{"iss": creds.TeamID, "iat": now.Unix()})
+	if err != nil {
+		return "", fmt.Errorf("apns: encode jwt claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("apns: sign jwt: %w", err)
+	}
+	// JWS encodes ES256 signatures as fixed-width r||s, not ASN.1.
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func parsePrivateKey(value string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, fmt.Errorf("apns: private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("apns: parse private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("apns: private key is not ECDSA")
+	}
+	return key, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func boolValue(meta map[string]any, key string) bool {
+	switch v := meta[key].(type) {
+	case bool:
+		return v
+	case string:
+		parsed, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return parsed
+	default:
+		return false
+	}
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func testKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestSendPostsAlertWithSignedProviderToken(t *testing.T) {
+	key, p8 := testKey(t)
+	var (
+		payload map[string]any
+		path    string
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		headers = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{TeamID: "TEAM123", KeyID: "KEY456", Topic: "com.example.app", Endpoint: server.URL}),
+		WithClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "push",
+		To:      "abc123",
+		Subject: "Order shipped",
+		Body:    "Arrives Tuesday",
+		Metadata: map[string]any{
+			"badge":   2,
+			"data":    map[string]any{"order_id": "42"},
+			"secrets": map[string][]byte{"private_key": []byte(p8)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if path != "/3/device/abc123" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if headers.Get("apns-topic") != "com.example.app" || headers.Get("apns-push-type") != "alert" {
+		t.Fatalf("unexpected apns headers %+v", headers)
+	}
+	aps, _ := payload["aps"].(map[string]any)
+	alert, _ := aps["alert"].(map[string]any)
+	if alert["title"] != "Order shipped" || alert["body"] != "Arrives Tuesday" || aps["badge"] != float64(2) {
+		t.Fatalf("unexpected aps payload %+v", aps)
+	}
+	if payload["order_id"] != "42" {
+		t.Fatalf("expected custom key passed through, got %+v", payload)
+	}
+
+	jwt := strings.TrimPrefix(headers.Get("Authorization"), "bearer ")
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected JWT, got %q", jwt)
+	}
+	headerJSON, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if !strings.Contains(string(headerJSON), `"kid":"KEY456"`) || !strings.Contains(string(claimsJSON), `"iss":"TEAM123"`) {
+		t.Fatalf("unexpected jwt header/claims %s %s", headerJSON, claimsJSON)
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+		t.Fatalf("jwt signature does not verify")
+	}
+}
+
+func TestSendReusesProviderToken(t *testing.T) {
+	_, p8 := testKey(t)
+	var auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{TeamID: "TEAM", KeyID: "KEY", PrivateKey: p8, Topic: "com.example.app", Endpoint: server.URL}),
+		WithClient(server.Client()),
+	)
+	for i := 0; i < 2; i++ {
+		if err := adapter.Send(context.Background(), adapters.Message{Channel: "push", To: "abc", Body: "hi"}); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	if len(auths) != 2 || auths[0] != auths[1] {
+		t.Fatalf("expected cached provider token reused, got %v", auths)
+	}
+}
+
+func TestSendDeadTokensArePermanent(t *testing.T) {
+	_, p8 := testKey(t)
+	cases := []struct {
+		status int
+		reason string
+		target error
+	}{
+		{http.StatusGone, "Unregistered", ErrUnregistered},
+		{http.StatusBadRequest, "BadDeviceToken", ErrBadDeviceToken},
+	}
+	for _, tc := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = io.WriteString(w, `{"reason":"`+tc.reason+`"}`)
+		}))
+		adapter := New(&logger.Nop{},
+			WithConfig(Config{TeamID: "TEAM", KeyID: "KEY", PrivateKey: p8, Topic: "com.example.app", Endpoint: server.URL}),
+			WithClient(server.Client()),
+		)
+		err := adapter.Send(context.Background(), adapters.Message{Channel: "push", To: "abc", Body: "hi"})
+		server.Close()
+		if err == nil || adapters.IsRetryable(err) {
+			t.Fatalf("%s: expected permanent error, got %v", tc.reason, err)
+		}
+		if !errors.Is(err, tc.target) {
+			t.Fatalf("%s: expected %v, got %v", tc.reason, tc.target, err)
+		}
+	}
+}
+
+func TestSendThrottledIsRetryable(t *testing.T) {
+	_, p8 := testKey(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"reason":"TooManyRequests"}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{TeamID: "TEAM", KeyID: "KEY", PrivateKey: p8, Topic: "com.example.app", Endpoint: server.URL}),
+		WithClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "push", To: "abc", Body: "hi"})
+	if err == nil || !adapters.IsRetryable(err) {
+		t.Fatalf("expected retryable error, got %v", err)
+	}
+}
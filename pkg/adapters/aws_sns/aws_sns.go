@@ -2,18 +2,15 @@ package aws_sns
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/internal/sigv4"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
 )
 
@@ -135,7 +132,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	}
 
 	creds := a.loadCredentials()
-	if creds.AccessKey == "" || creds.SecretKey == "" {
+	if !creds.Valid() {
 		return fmt.Errorf("aws_sns: aws credentials required")
 	}
 	region := strings.TrimSpace(a.cfg.Region)
@@ -143,12 +140,10 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		region = "us-east-1"
 	}
 
-	req, signedHeaders, err := a.signRequest(creds, region, params)
+	endpoint := fmt.Sprintf("https://sns.%s.amazonaws.com/", region)
+	req, err := sigv4.NewFormRequest(ctx, creds, "sns", region, endpoint, params, time.Now())
 	if err != nil {
-		return err
-	}
-	for k, v := range signedHeaders {
-		req.Header.Set(k, v)
+		return fmt.Errorf("aws_sns: %w", err)
 	}
 
 	resp, err := a.client.Do(req)
@@ -192,106 +187,14 @@ func stringValue(meta map[string]any, key string) string {
 	}
 }
 
-type credentials struct {
-	AccessKey    string
-	SecretKey    string
-	SessionToken string
-}
-
-func (a *Adapter) loadCredentials() credentials {
-	creds := credentials{
-		AccessKey:    strings.TrimSpace(a.cfg.AccessKey),
-		SecretKey:    strings.TrimSpace(a.cfg.SecretKey),
-		SessionToken: strings.TrimSpace(a.cfg.SessionToken),
-	}
-	if creds.AccessKey == "" {
-		creds.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
-	}
-	if creds.SecretKey == "" {
-		creds.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
-	}
-	if creds.SessionToken == "" {
-		creds.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
-	}
-	return creds
-}
-
-func (a *Adapter) signRequest(creds credentials, region string, params url.Values) (*http.Request, map[string]string, error) {
-	endpoint := fmt.Sprintf("https://sns.%s.amazonaws.com/", region)
-	bodyStr := params.Encode()
-	payloadHash := sha256Hex([]byte(bodyStr))
-
-	now := time.Now().UTC()
-	amzDate := now.Format("20060102T150405Z")
-	date := now.Format("20060102")
-
-	canonicalURI := "/"
-	canonicalQuery := ""
-	canonicalHeaders := fmt.Sprintf("content-type:application/x-www-form-urlencoded; charset=utf-8\nhost:sns.%s.amazonaws.com\nx-amz-date:%s\n", region, amzDate)
-	if creds.SessionToken != "" {
-		canonicalHeaders += fmt.Sprintf("x-amz-security-token:%s\n", creds.SessionToken)
-	}
-	signedHeaders := "content-type;host;x-amz-date"
-	if creds.SessionToken != "" {
-		signedHeaders += ";x-amz-security-token"
-	}
-
-	canonicalRequest := strings.Join([]string{
-		"POST",
-		canonicalURI,
-		canonicalQuery,
-		canonicalHeaders,
-		signedHeaders,
-		payloadHash,
-	}, "\n")
-
-	credentialScope := fmt.Sprintf("%s/%s/sns/aws4_request", date, region)
-	stringToSign := strings.Join([]string{
-		"AWS4-HMAC-SHA256",
-		amzDate,
-		credentialScope,
-		sha256Hex([]byte(canonicalRequest)),
-	}, "\n")
-
-	signingKey := deriveKey(creds.SecretKey, date, region, "sns")
-	signature := hex.EncodeToString(hmacSHA256(signingKey, []byte(stringToSign)))
-
-	authHeader := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
-		creds.AccessKey, credentialScope, signedHeaders, signature)
-
-	req, err := http.NewRequest("POST", endpoint, strings.NewReader(bodyStr))
-	if err != nil {
-		return nil, nil, err
-	}
-	headers := map[string]string{
-		"Content-Type":  "application/x-www-form-urlencoded; charset=utf-8",
-		"X-Amz-Date":    amzDate,
-		"Authorization": authHeader,
-	}
-	if creds.SessionToken != "" {
-		headers["X-Amz-Security-Token"] = creds.SessionToken
-	}
-	return req, headers, nil
-}
-
-func sha256Hex(data []byte) string {
-	h := sha256.Sum256(data)
-	return hex.EncodeToString(h[:])
-}
-
-func hmacSHA256(key []byte, data []byte) []byte {
-	h := hmac.New(sha256.New, key)
-	h.Write(data)
-	return h.Sum(nil)
+func (a *Adapter) loadCredentials() sigv4.Credentials {
+	return sigv4.Credentials{
+		AccessKey:    a.cfg.AccessKey,
+		SecretKey:    a.cfg.SecretKey,
+		SessionToken: a.cfg.SessionToken,
+	}.WithEnvDefaults()
 }
 
-func deriveKey(secret, date, region, service string) []byte {
-	kDate := hmacSHA256([]byte("AWS4"+secret), []byte(date))
-	kRegion := hmacSHA256(kDate, []byte(region))
-	kService := hmacSHA256(kRegion, []byte(service))
-	kSigning := hmacSHA256(kService, []byte("aws4_request"))
-	return kSigning
-}
 func stripHTML(html string) string {
 	var b strings.Builder
 	inTag := false
@@ -0,0 +1,51 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchMessenger is implemented by adapters whose provider accepts many
+// messages in a single request (SendGrid personalizations, FCM multicast).
+// SendBatch returns one error per message, in the same order as msgs; a nil
+// entry means that message was accepted.
+type BatchMessenger interface {
+	Messenger
+	SendBatch(ctx context.Context, msgs []Message) []error
+}
+
+// SendBatch delivers msgs through m, using its batch endpoint when it
+// implements BatchMessenger and falling back to one Send per message
+// otherwise. The result always has len(msgs) entries.
+func SendBatch(ctx context.Context, m Messenger, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	if len(msgs) == 0 {
+		return errs
+	}
+	if batcher, ok := m.(BatchMessenger); ok {
+		results := batcher.SendBatch(ctx, msgs)
+		if len(results) != len(msgs) {
+			err := fmt.Errorf("adapters: %s returned %d batch results for %d messages", m.Name(), len(results), len(msgs))
+			for i := range errs {
+				errs[i] = err
+			}
+			return errs
+		}
+		copy(errs, results)
+		return errs
+	}
+	for i, msg := range msgs {
+		errs[i] = m.Send(ctx, msg)
+	}
+	return errs
+}
+
+// BatchErrors returns a slice of len n where every entry is err. Adapters use
+// it when a batch request fails as a whole.
+func BatchErrors(n int, err error) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
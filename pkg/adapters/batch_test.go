@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type batchStub struct {
+	namedMessenger
+	batches [][]Message
+	results func([]Message) []error
+}
+
+func (b *batchStub) SendBatch(_ context.Context, msgs []Message) []error {
+	b.batches = append(b.batches, msgs)
+	return b.results(msgs)
+}
+
+func TestSendBatchUsesBatchEndpoint(t *testing.T) {
+	rejected := errors.New("rejected")
+	stub := &batchStub{
+		namedMessenger: namedMessenger{name: "bulk"},
+		results: func(msgs []Message) []error {
+			errs := make([]error, len(msgs))
+			for i, msg := range msgs {
+				if msg.To == "bad" {
+					errs[i] = rejected
+				}
+			}
+			return errs
+		},
+	}
+	errs := SendBatch(context.Background(), stub, []Message{{To: "a"}, {To: "bad"}, {To: "c"}})
+	if len(stub.batches) != 1 || len(stub.batches[0]) != 3 {
+		t.Fatalf("expected a single batch of 3, got %v", stub.batches)
+	}
+	if errs[0] != nil || !errors.Is(errs[1], rejected) || errs[2] != nil {
+		t.Fatalf("expected only the second message to fail, got %v", errs)
+	}
+}
+
+func TestSendBatchRejectsMismatchedResults(t *testing.T) {
+	stub := &batchStub{
+		namedMessenger: namedMessenger{name: "bulk"},
+		results:        func([]Message) []error { return nil },
+	}
+	errs := SendBatch(context.Background(), stub, []Message{{To: "a"}, {To: "b"}})
+	if len(errs) != 2 || errs[0] == nil || errs[1] == nil {
+		t.Fatalf("expected every message to fail on a short result, got %v", errs)
+	}
+}
+
+func TestSendBatchFallsBackToSend(t *testing.T) {
+	var sent []string
+	m := sendFunc(func(msg Message) error {
+		sent = append(sent, msg.To)
+		if msg.To == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	errs := SendBatch(context.Background(), m, []Message{{To: "a"}, {To: "b"}})
+	if len(sent) != 2 || errs[0] != nil || errs[1] == nil {
+		t.Fatalf("expected per-message sends, got sent=%v errs=%v", sent, errs)
+	}
+}
+
+type sendFunc func(Message) error
+
+func (sendFunc) Name() string                                { return "single" }
+func (sendFunc) Capabilities() Capability                    { return Capability{Name: "single"} }
+func (f sendFunc) Send(_ context.Context, msg Message) error { return f(msg) }
@@ -0,0 +1,85 @@
+package adapters
+
+import "slices"
+
+// AvailableChannels returns the channels the registered adapters can deliver,
+// in registration order. Each channel type is followed by a
+// "<channel>:<provider>" entry for every provider serving it, so the result
+// can be used directly as definition or preference channel options. A
+// provider named after the channel (Slack's "slack" channel) is not repeated
+// as "slack:slack". Capability channels are split with ParseChannel, so
+// "chat:slack" counts as the "chat" channel.
+func (r *Registry) AvailableChannels() []string {
+	var channels []string
+	providers := make(map[string][]string)
+	for _, m := range r.messengers() {
+		name := normalizeKey(m.Name())
+		for _, capability := range m.Capabilities().Channels {
+			channel, _ := ParseChannel(capability)
+			if channel == "" {
+				continue
+			}
+			if _, seen := providers[channel]; !seen {
+				channels = append(channels, channel)
+				providers[channel] = nil
+			}
+			if name != "" && name != channel && !slices.Contains(providers[channel], name) {
+				providers[channel] = append(providers[channel], name)
+			}
+		}
+	}
+	out := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		out = append(out, channel)
+		for _, provider := range providers[channel] {
+			out = append(out, channel+":"+provider)
+		}
+	}
+	return out
+}
+
+// Providers returns the names of the adapters that can deliver channel, in
+// registration order. A provider suffix on channel ("chat:slack") is
+// ignored: every provider of the channel type is returned.
+func (r *Registry) Providers(channel string) []string {
+	base, _ := ParseChannel(channel)
+	if base == "" {
+		return nil
+	}
+	var out []string
+	for _, m := range r.messengers() {
+		name := normalizeKey(m.Name())
+		if name == "" || slices.Contains(out, name) {
+			continue
+		}
+		for _, capability := range m.Capabilities().Channels {
+			if candidate, _ := ParseChannel(capability); candidate == base {
+				out = append(out, name)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// EnabledNames returns the names of the registered adapters in registration
+// order.
+func (r *Registry) EnabledNames() []string {
+	messengers := r.messengers()
+	out := make([]string, 0, len(messengers))
+	for _, m := range messengers {
+		if name := normalizeKey(m.Name()); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func (r *Registry) messengers() []Messenger {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.ordered)
+}
@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"slices"
+	"testing"
+)
+
+func newChatRegistry() *Registry {
+	return NewRegistry(
+		namedMessenger{name: "console", channels: []string{"email"}},
+		namedMessenger{name: "Slack", channels: []string{"chat", "chat:slack", "slack"}},
+		namedMessenger{name: "telegram", channels: []string{"chat:telegram"}},
+		namedMessenger{name: "twilio", channels: []string{"sms", "whatsapp"}},
+	)
+}
+
+func TestRegistryAvailableChannels(t *testing.T) {
+	got := newChatRegistry().AvailableChannels()
+	want := []string{
+		"email", "email:console",
+		"chat", "chat:slack", "chat:telegram",
+		"slack",
+		"sms", "sms:twilio",
+		"whatsapp", "whatsapp:twilio",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRegistryProvidersParsesChannel(t *testing.T) {
+	reg := newChatRegistry()
+	for _, channel := range []string{"chat", "chat:slack", "CHAT:telegram"} {
+		if got := reg.Providers(channel); !slices.Equal(got, []string{"slack", "telegram"}) {
+			t.Fatalf("Providers(%q): expected [slack telegram], got %v", channel, got)
+		}
+	}
+	if got := reg.Providers("push"); len(got) != 0 {
+		t.Fatalf("expected no push providers, got %v", got)
+	}
+}
+
+func TestRegistryEnabledNamesKeepsRegistrationOrder(t *testing.T) {
+	reg := newChatRegistry()
+	reg.Register(namedMessenger{name: "slack", channels: []string{"chat"}})
+	want := []string{"console", "slack", "telegram", "twilio"}
+	if got := reg.EnabledNames(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	var nilRegistry *Registry
+	if got := nilRegistry.EnabledNames(); len(got) != 0 {
+		t.Fatalf("expected no names for nil registry, got %v", got)
+	}
+}
@@ -0,0 +1,294 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+// maxContentLength is Discord's limit for the content field.
+const maxContentLength = 2000
+
+// Adapter posts messages to a Discord channel webhook.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+}
+
+// Config holds Discord webhook settings. WebhookURL is used when the
+// resolved secrets do not carry one.
+type Config struct {
+	WebhookURL string
+	Username   string
+	AvatarURL  string
+	Timeout    time.Duration
+	Transport  adapters.HTTPTransportConfig
+	DryRun     bool
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter provider name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets adapter configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithClient sets a custom HTTP client.
+func WithClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the Discord adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "discord",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:     "discord",
+			Channels: []string{"chat", "discord"},
+			Formats:  []string{"text/plain", "text/html"},
+		},
+		cfg: Config{
+			Timeout: 10 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	webhookURL := strings.TrimSpace(firstNonEmpty(
+		secretString(msg.Metadata, "webhook_url"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.WebhookURL,
+	))
+	if webhookURL == "" && !a.cfg.DryRun {
+		return fmt.Errorf("discord: webhook url required")
+	}
+
+	text := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body)
+	if htmlBody := stringValue(msg.Metadata, "html_body"); htmlBody != "" && strings.TrimSpace(text) == "" {
+		text = stripHTML(htmlBody)
+	}
+	content := strings.TrimSpace(text)
+	if subject := strings.TrimSpace(msg.Subject); subject != "" {
+		content = strings.TrimSpace("**" + subject + "**\n" + content)
+	}
+	embeds := embedsFrom(msg.Metadata["embeds"])
+	if content == "" && len(embeds) == 0 {
+		return fmt.Errorf("discord: message content or embeds required")
+	}
+	if len([]rune(content)) > maxContentLength {
+		return adapters.Permanent(fmt.Errorf("discord: content exceeds %d characters", maxContentLength))
+	}
+
+	payload := map[string]any{}
+	if content != "" {
+		payload["content"] = content
+	}
+	if len(embeds) > 0 {
+		payload["embeds"] = embeds
+	}
+	if username := firstNonEmpty(stringValue(msg.Metadata, "username"), a.cfg.Username); username != "" {
+		payload["username"] = username
+	}
+	if avatar := firstNonEmpty(stringValue(msg.Metadata, "avatar_url"), a.cfg.AvatarURL); avatar != "" {
+		payload["avatar_url"] = avatar
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[discord:during-dry-run] send skipped",
+			"content", content,
+			"embeds", len(embeds),
+		)
+		return nil
+	}
+
+	endpoint, err := webhookEndpoint(webhookURL, stringValue(msg.Metadata, "thread_id"))
+	if err != nil {
+		return err
+	}
+	bodyBytes, err := adapters.EncodeJSONPayload("discord", payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("discord: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("discord: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := adapters.HTTPStatusError("discord", resp.StatusCode, data)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return adapters.Retryable(statusErr, retryAfter(resp, data))
+		}
+		return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// retryAfter reads the rate-limit delay from the JSON body, where Discord
+// reports retry_after in (fractional) seconds, falling back to the header.
+func retryAfter(resp *http.Response, body []byte) time.Duration {
+	var payload struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.RetryAfter > 0 {
+		return time.Duration(payload.RetryAfter * float64(time.Second))
+	}
+	return adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+}
+
+func webhookEndpoint(raw, threadID string) (string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("discord: invalid webhook url: %w", err)
+	}
+	query := parsed.Query()
+	// wait=true makes Discord report delivery errors instead of accepting blindly.
+	query.Set("wait", "true")
+	if threadID != "" {
+		query.Set("thread_id", threadID)
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// embedsFrom passes metadata embeds through as-is. A single embed object is
+// wrapped in a list.
+func embedsFrom(raw any) []any {
+	switch v := raw.(type) {
+	case []any:
+		return v
+	case []map[string]any:
+		out := make([]any, 0, len(v))
+		for _, embed := range v {
+			out = append(out, embed)
+		}
+		return out
+	case map[string]any:
+		return []any{v}
+	default:
+		return nil
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func stripHTML(html string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch r {
+		case '<':
+			inTag = true
+		case '>':
+			inTag = false
+		default:
+			if !inTag {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
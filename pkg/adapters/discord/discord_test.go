@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendPostsContentAndEmbeds(t *testing.T) {
+	var (
+		payload map[string]any
+		query   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		_, _ = io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{Username: "Notifier"}), WithClient(server.Client()))
+	if adapter.Name() != "discord" {
+		t.Fatalf("expected provider name discord, got %q", adapter.Name())
+	}
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "chat",
+		Subject: "Release",
+		Body:    "v1.2.0 is out",
+		Metadata: map[string]any{
+			"embeds":    []any{map[string]any{"title": "Changelog", "url": "https://example.com"}},
+			"thread_id": "99",
+			"blocks":    []any{map[string]any{"type": "divider"}},
+			"secrets":   map[string][]byte{"webhook_url": []byte(server.URL + "/api/webhooks/1/token")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if payload["content"] != "**Release**\nv1.2.0 is out" {
+		t.Fatalf("unexpected content %q", payload["content"])
+	}
+	if embeds, _ := payload["embeds"].([]any); len(embeds) != 1 {
+		t.Fatalf("expected embeds passed through, got %+v", payload["embeds"])
+	}
+	if _, ok := payload["blocks"]; ok {
+		t.Fatalf("expected slack blocks to be ignored, got %+v", payload["blocks"])
+	}
+	if payload["username"] != "Notifier" {
+		t.Fatalf("expected configured username, got %+v", payload)
+	}
+	if query != "thread_id=99&wait=true" {
+		t.Fatalf("unexpected query %q", query)
+	}
+}
+
+func TestSendRateLimitedReturnsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"message":"You are being rate limited.","retry_after":1.5,"global":false}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{WebhookURL: server.URL}), WithClient(server.Client()))
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "chat", Body: "hello"})
+	if err == nil || !adapters.IsRetryable(err) {
+		t.Fatalf("expected retryable rate-limit error, got %v", err)
+	}
+	if after, ok := adapters.RetryAfter(err); !ok || after != 1500*time.Millisecond {
+		t.Fatalf("expected retry after 1.5s, got %v (%v)", after, ok)
+	}
+}
+
+func TestSendClientErrorIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"message":"Unknown Webhook","code":10015}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{WebhookURL: server.URL}), WithClient(server.Client()))
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "chat", Body: "hello"})
+	if err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected permanent error for unknown webhook, got %v", err)
+	}
+}
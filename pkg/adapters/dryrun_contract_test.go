@@ -6,12 +6,19 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/aws/aws-sdk-go-v2/service/ses"
+	awsses "github.com/aws/aws-sdk-go-v2/service/ses"
 	core "github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/apns"
 	"github.com/goliatone/go-notifications/pkg/adapters/aws_ses"
 	"github.com/goliatone/go-notifications/pkg/adapters/aws_sns"
+	"github.com/goliatone/go-notifications/pkg/adapters/discord"
+	"github.com/goliatone/go-notifications/pkg/adapters/fcm"
 	"github.com/goliatone/go-notifications/pkg/adapters/firebase"
+	"github.com/goliatone/go-notifications/pkg/adapters/pagerduty"
+	"github.com/goliatone/go-notifications/pkg/adapters/ses"
 	"github.com/goliatone/go-notifications/pkg/adapters/slack"
+	"github.com/goliatone/go-notifications/pkg/adapters/smtp"
+	"github.com/goliatone/go-notifications/pkg/adapters/teams"
 	"github.com/goliatone/go-notifications/pkg/adapters/telegram"
 	"github.com/goliatone/go-notifications/pkg/adapters/twilio"
 	"github.com/goliatone/go-notifications/pkg/adapters/webhook"
@@ -68,6 +75,36 @@ func TestDryRunContractSkipsCredentialGatingAndNetworkIO(t *testing.T) {
 				return a.Send(ctx, core.Message{Channel: "chat", Body: "hello"})
 			},
 		},
+		{
+			name: "discord",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := discord.New(&logger.Nop{},
+					discord.WithConfig(discord.Config{DryRun: true}),
+					discord.WithClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "chat", Body: "hello"})
+			},
+		},
+		{
+			name: "pagerduty",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := pagerduty.New(&logger.Nop{},
+					pagerduty.WithConfig(pagerduty.Config{DryRun: true}),
+					pagerduty.WithClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "alert", Subject: "disk full", Body: "db-1 at 98%"})
+			},
+		},
+		{
+			name: "teams",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := teams.New(&logger.Nop{},
+					teams.WithConfig(teams.Config{DryRun: true}),
+					teams.WithClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "chat", Subject: "hi", Body: "hello"})
+			},
+		},
 		{
 			name: "webhook",
 			run: func(ctx context.Context, rt *countingRoundTripper) error {
@@ -88,6 +125,26 @@ func TestDryRunContractSkipsCredentialGatingAndNetworkIO(t *testing.T) {
 				return a.Send(ctx, core.Message{Channel: "push", To: "device-token", Body: "hello"})
 			},
 		},
+		{
+			name: "fcm",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := fcm.New(&logger.Nop{},
+					fcm.WithConfig(fcm.Config{DryRun: true}),
+					fcm.WithClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "push", To: "device-token", Body: "hello"})
+			},
+		},
+		{
+			name: "apns",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := apns.New(&logger.Nop{},
+					apns.WithConfig(apns.Config{DryRun: true, Topic: "com.example.app"}),
+					apns.WithClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "push", To: "device-token", Body: "hello"})
+			},
+		},
 		{
 			name: "aws_sns",
 			run: func(ctx context.Context, rt *countingRoundTripper) error {
@@ -98,6 +155,25 @@ func TestDryRunContractSkipsCredentialGatingAndNetworkIO(t *testing.T) {
 				return a.Send(ctx, core.Message{Channel: "sms", To: "+15551234567", Body: "hello"})
 			},
 		},
+		{
+			name: "ses",
+			run: func(ctx context.Context, rt *countingRoundTripper) error {
+				a := ses.New(&logger.Nop{},
+					ses.WithConfig(ses.Config{DryRun: true, From: "noreply@example.com"}),
+					ses.WithHTTPClient(newCountingHTTPClient(rt)),
+				)
+				return a.Send(ctx, core.Message{Channel: "email", To: "user@example.com", Subject: "hi", Body: "hello"})
+			},
+		},
+		{
+			name: "smtp",
+			run: func(ctx context.Context, _ *countingRoundTripper) error {
+				a := smtp.New(&logger.Nop{},
+					smtp.WithConfig(smtp.Config{DryRun: true, From: "no-reply@example.com"}),
+				)
+				return a.Send(ctx, core.Message{Channel: "email", To: "to@example.com", Subject: "hi", Body: "hello"})
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -118,9 +194,9 @@ type fakeSESClient struct {
 	called bool
 }
 
-func (c *fakeSESClient) SendEmail(context.Context, *ses.SendEmailInput, ...func(*ses.Options)) (*ses.SendEmailOutput, error) {
+func (c *fakeSESClient) SendEmail(context.Context, *awsses.SendEmailInput, ...func(*awsses.Options)) (*awsses.SendEmailOutput, error) {
 	c.called = true
-	return &ses.SendEmailOutput{}, nil
+	return &awsses.SendEmailOutput{}, nil
 }
 
 func TestSESDryRunSkipsClientCall(t *testing.T) {
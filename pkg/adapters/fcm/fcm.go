@@ -0,0 +1,487 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+const (
+	messagingScope  = "https://www.googleapis.com/auth/firebase.messaging"
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	fcmErrorType    = "type.googleapis.com/google.firebase.fcm.v1.FcmError"
+)
+
+// ErrUnregistered reports a device token FCM no longer accepts. Hosts can
+// match it with errors.Is to prune stale tokens.
+var ErrUnregistered = errors.New("fcm: device token is not registered")
+
+// Adapter delivers push notifications through the FCM HTTP v1 API using
+// service-account credentials exchanged for short-lived OAuth2 tokens.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+// Config holds FCM HTTP v1 settings. ServiceAccountJSON is used when the
+// resolved secrets do not carry a service account.
+type Config struct {
+	ProjectID          string
+	ServiceAccountJSON string
+	Endpoint           string
+	Timeout            time.Duration
+	Transport          adapters.HTTPTransportConfig
+	DryRun             bool
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets FCM configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithClient injects a custom HTTP client.
+func WithClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the FCM adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "fcm",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:          "fcm",
+			Channels:      []string{"push"},
+			Formats:       []string{"text/plain"},
+			AutoDowngrade: true,
+		},
+		cfg: Config{
+			Endpoint: "https://fcm.googleapis.com",
+			Timeout:  10 * time.Second,
+		},
+		tokens: make(map[string]cachedToken),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if strings.TrimSpace(adapter.cfg.Endpoint) == "" {
+		adapter.cfg.Endpoint = "https://fcm.googleapis.com"
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	token := firstNonEmpty(stringValue(msg.Metadata, "token"), strings.TrimSpace(msg.To))
+	topic := strings.TrimPrefix(stringValue(msg.Metadata, "topic"), "/topics/")
+	if token == "" && topic == "" {
+		return fmt.Errorf("fcm: a device token or topic is required")
+	}
+
+	message := map[string]any{}
+	if token != "" {
+		message["token"] = token
+	} else {
+		message["topic"] = topic
+	}
+	notification := map[string]any{}
+	if title := strings.TrimSpace(msg.Subject); title != "" {
+		notification["title"] = title
+	}
+	if body := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body); strings.TrimSpace(body) != "" {
+		notification["body"] = body
+	}
+	if img := stringValue(msg.Metadata, "image"); img != "" {
+		notification["image"] = img
+	}
+	if len(notification) > 0 {
+		message["notification"] = notification
+	}
+	data, err := dataPayload(msg.Metadata["data"])
+	if err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		message["data"] = data
+	}
+	if len(notification) == 0 && len(data) == 0 {
+		return fmt.Errorf("fcm: notification content or data required")
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[fcm:during-dry-run] send skipped",
+			"to", firstNonEmpty(token, topic),
+			"subject", msg.Subject,
+		)
+		return nil
+	}
+
+	account, err := a.serviceAccount(msg.Metadata)
+	if err != nil {
+		return err
+	}
+	projectID := firstNonEmpty(strings.TrimSpace(a.cfg.ProjectID), account.ProjectID)
+	if projectID == "" {
+		return fmt.Errorf("fcm: project id required")
+	}
+	accessToken, err := a.accessToken(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := adapters.EncodeJSONPayload("fcm", map[string]any{"message": message})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimRight(a.cfg.Endpoint, "/"), url.PathEscape(projectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("fcm: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if resp.StatusCode == http.StatusUnauthorized {
+			a.forgetToken(account.ClientEmail)
+		}
+		return classifyError(resp, respBody)
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// classifyError maps FCM v1 error codes onto the retry contract. Dead or
+// malformed targets are permanent; quota and availability errors are
+// retried, honoring Retry-After.
+func classifyError(resp *http.Response, body []byte) error {
+	statusErr := adapters.HTTPStatusError("fcm", resp.StatusCode, body)
+	retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+	switch fcmErrorCode(body) {
+	case "UNREGISTERED":
+		return adapters.Permanent(fmt.Errorf("%w: %w", ErrUnregistered, statusErr))
+	case "INVALID_ARGUMENT", "SENDER_ID_MISMATCH", "THIRD_PARTY_AUTH_ERROR":
+		return adapters.Permanent(statusErr)
+	case "QUOTA_EXCEEDED", "UNAVAILABLE", "INTERNAL":
+		return adapters.Retryable(statusErr, retryAfter)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The cached access token was rejected; the next attempt mints a new one.
+		return adapters.Retryable(statusErr, 0)
+	}
+	return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, retryAfter)
+}
+
+func fcmErrorCode(body []byte) string {
+	var payload struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				Type      string `json:"@type"`
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	for _, detail := range payload.Error.Details {
+		if detail.Type == fcmErrorType && detail.ErrorCode != "" {
+			return detail.ErrorCode
+		}
+	}
+	return payload.Error.Status
+}
+
+// dataPayload converts metadata into the string map FCM requires. Non-string
+// values are JSON encoded.
+func dataPayload(raw any) (map[string]string, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case map[string]string:
+		return maps.Clone(v), nil
+	case map[string]any:
+		out := make(map[string]string, len(v))
+		for key, value := range v {
+			switch typed := value.(type) {
+			case string:
+				out[key] = typed
+			default:
+				encoded, err := json.Marshal(typed)
+				if err != nil {
+					return nil, fmt.Errorf("fcm: encode data %q: %w", key, err)
+				}
+				out[key] = string(encoded)
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("fcm: data must be a map, got %T", raw)
+	}
+}
+
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+func (a *Adapter) serviceAccount(meta map[string]any) (serviceAccount, error) {
+	raw := firstNonEmpty(
+		secretString(meta, "service_account"),
+		secretString(meta, "default"),
+		a.cfg.ServiceAccountJSON,
+	)
+	if strings.TrimSpace(raw) == "" {
+		return serviceAccount{}, fmt.Errorf("fcm: service account credentials required")
+	}
+	var account serviceAccount
+	if err := json.Unmarshal([]byte(raw), &account); err != nil {
+		return serviceAccount{}, fmt.Errorf("fcm: parse service account: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return serviceAccount{}, fmt.Errorf("fcm: service account missing client_email or private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = defaultTokenURL
+	}
+	return account, nil
+}
+
+type cachedToken struct {
+	value   string
+	expires time.Time
+}
+
+// accessToken returns a cached OAuth2 token for the account, minting a new
+// one through the JWT bearer grant when missing or about to expire.
+func (a *Adapter) accessToken(ctx context.Context, account serviceAccount) (string, error) {
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.tokens[account.ClientEmail]
+	a.mu.Unlock()
+	if ok && now.Add(time.Minute).Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	assertion, err := signAssertion(account, now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("fcm: build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm: token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), now)
+		return "", adapters.ClassifyHTTPStatus(adapters.HTTPStatusError("fcm", resp.StatusCode, body), resp.StatusCode, retryAfter)
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("fcm: decode token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", fmt.Errorf("fcm: token response missing access_token")
+	}
+	if payload.ExpiresIn <= 0 {
+		payload.ExpiresIn = 3600
+	}
+
+	a.mu.Lock()
+	a.tokens[account.ClientEmail] = cachedToken{
+		value:   payload.AccessToken,
+		expires: now.Add(time.Duration(payload.ExpiresIn) * time.Second),
+	}
+	a.mu.Unlock()
+	return payload.AccessToken, nil
+}
+
+func (a *Adapter) forgetToken(clientEmail string) {
+	a.mu.Lock()
+	delete(a.tokens, clientEmail)
+	a.mu.Unlock()
+}
+
+func signAssertion(account serviceAccount, now time.Time) (string, error) {
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if account.PrivateKeyID != "" {
+		header["kid"] = account.PrivateKeyID
+	}
+	claims := map[string]any{
+		"iss":   account.ClientEmail,
+		"scope": messagingScope,
+		"aud":   account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("fcm: encode jwt header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("fcm: encode jwt claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("fcm: sign jwt: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func parsePrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, fmt.Errorf("fcm: private key is not PEM encoded")
+	}
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		key, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("fcm: private key is not RSA")
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: parse private key: %w", err)
+	}
+	return key, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
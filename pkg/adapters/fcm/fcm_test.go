@@ -0,0 +1,189 @@
+package fcm
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+type fakeFCM struct {
+	mu         sync.Mutex
+	tokenCalls int
+	bodies     []map[string]any
+	status     int
+	response   string
+}
+
+func (f *fakeFCM) handler(t *testing.T) http.Handler {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.tokenCalls++
+		f.mu.Unlock()
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse token form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("unexpected grant type %q", r.Form.Get("grant_type"))
+		}
+		if parts := strings.Split(r.Form.Get("assertion"), "."); len(parts) != 3 {
+			t.Errorf("expected signed JWT assertion, got %q", r.Form.Get("assertion"))
+		}
+		_, _ = io.WriteString(w, `{"access_token":"token-1","expires_in":3600,"token_type":"Bearer"}`)
+	})
+	mux.HandleFunc("/v1/projects/demo/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode send body: %v", err)
+		}
+		f.mu.Lock()
+		f.bodies = append(f.bodies, body)
+		status, response := f.status, f.response
+		f.mu.Unlock()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, response)
+	})
+	return mux
+}
+
+func serviceAccountJSON(t *testing.T, tokenURL string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	account, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "demo",
+		"private_key_id": "kid-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "notifier@demo.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	if err != nil {
+		t.Fatalf("marshal service account: %v", err)
+	}
+	return string(account)
+}
+
+func TestSendMapsMessageAndCachesToken(t *testing.T) {
+	fake := &fakeFCM{response: `{"name":"projects/demo/messages/1"}`}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{Endpoint: server.URL}), WithClient(server.Client()))
+	msg := adapters.Message{
+		Channel: "push",
+		To:      "device-token",
+		Subject: "Order shipped",
+		Body:    "Your order is on the way",
+		Metadata: map[string]any{
+			"data":    map[string]any{"order_id": "42", "count": 3},
+			"secrets": map[string][]byte{"service_account": []byte(serviceAccountJSON(t, server.URL+"/token"))},
+		},
+	}
+	for range 2 {
+		if err := adapter.Send(context.Background(), msg); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+
+	if fake.tokenCalls != 1 {
+		t.Fatalf("expected the access token to be cached, got %d token calls", fake.tokenCalls)
+	}
+	message, _ := fake.bodies[0]["message"].(map[string]any)
+	if message["token"] != "device-token" {
+		t.Fatalf("expected device token target, got %+v", message)
+	}
+	notification, _ := message["notification"].(map[string]any)
+	if notification["title"] != "Order shipped" || notification["body"] != "Your order is on the way" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+	data, _ := message["data"].(map[string]any)
+	if data["order_id"] != "42" || data["count"] != "3" {
+		t.Fatalf("expected stringified data payload, got %+v", data)
+	}
+}
+
+func TestSendClassifiesFCMErrors(t *testing.T) {
+	cases := []struct {
+		name         string
+		status       int
+		response     string
+		retryable    bool
+		unregistered bool
+	}{
+		{
+			name:         "unregistered",
+			status:       http.StatusNotFound,
+			response:     `{"error":{"code":404,"status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`,
+			unregistered: true,
+		},
+		{
+			name:     "invalid argument",
+			status:   http.StatusBadRequest,
+			response: `{"error":{"code":400,"status":"INVALID_ARGUMENT"}}`,
+		},
+		{
+			name:      "unavailable",
+			status:    http.StatusServiceUnavailable,
+			response:  `{"error":{"code":503,"status":"UNAVAILABLE"}}`,
+			retryable: true,
+		},
+		{
+			name:      "quota",
+			status:    http.StatusTooManyRequests,
+			response:  `{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"QUOTA_EXCEEDED"}]}}`,
+			retryable: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeFCM{status: tc.status, response: tc.response}
+			server := httptest.NewServer(fake.handler(t))
+			defer server.Close()
+
+			adapter := New(&logger.Nop{},
+				WithConfig(Config{
+					Endpoint:           server.URL,
+					ServiceAccountJSON: serviceAccountJSON(t, server.URL+"/token"),
+				}),
+				WithClient(server.Client()),
+			)
+			err := adapter.Send(context.Background(), adapters.Message{Channel: "push", To: "device-token", Body: "hello"})
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if got := adapters.IsRetryable(err); got != tc.retryable {
+				t.Fatalf("expected retryable=%v, got %v (%v)", tc.retryable, got, err)
+			}
+			if got := errors.Is(err, ErrUnregistered); got != tc.unregistered {
+				t.Fatalf("expected unregistered=%v, got %v (%v)", tc.unregistered, got, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package firebase
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"maps"
@@ -91,6 +92,117 @@ func (a *Adapter) Name() string { return a.name }
 func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
 
 func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	payload, target, err := a.buildPayload(msg)
+	if err != nil {
+		return err
+	}
+	if a.cfg.DryRun {
+		a.logDryRun(msg, target)
+		return nil
+	}
+	if _, err := a.post(ctx, payload); err != nil {
+		return err
+	}
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// maxRegistrationIDs is the legacy API limit for a multicast request.
+const maxRegistrationIDs = 1000
+
+// SendBatch sends token-addressed messages with identical payloads as one
+// multicast request using registration_ids, and maps each entry of the
+// response's results array back to its message. Topic and condition
+// messages are sent individually.
+func (a *Adapter) SendBatch(ctx context.Context, msgs []adapters.Message) []error {
+	errs := make([]error, len(msgs))
+	type group struct {
+		payload map[string]any
+		indexes []int
+		tokens  []string
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	for i, msg := range msgs {
+		payload, target, err := a.buildPayload(msg)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if _, ok := payload["condition"]; ok || strings.HasPrefix(target, "/topics/") {
+			errs[i] = a.Send(ctx, msg)
+			continue
+		}
+		delete(payload, "to")
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			errs[i] = fmt.Errorf("firebase: encode payload: %w", err)
+			continue
+		}
+		key := string(raw)
+		g := byKey[key]
+		if g == nil || len(g.tokens) == maxRegistrationIDs {
+			g = &group{payload: payload}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.indexes = append(g.indexes, i)
+		g.tokens = append(g.tokens, target)
+	}
+
+	for _, g := range groups {
+		if a.cfg.DryRun {
+			for k, i := range g.indexes {
+				a.logDryRun(msgs[i], g.tokens[k])
+			}
+			continue
+		}
+		g.payload["registration_ids"] = g.tokens
+		respBody, err := a.post(ctx, g.payload)
+		if err != nil {
+			for _, i := range g.indexes {
+				errs[i] = err
+			}
+			continue
+		}
+		var resp struct {
+			Results []struct {
+				MessageID string `json:"message_id"`
+				Error     string `json:"error"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil || len(resp.Results) != len(g.indexes) {
+			err = fmt.Errorf("firebase: unexpected multicast response for %d tokens", len(g.indexes))
+			for _, i := range g.indexes {
+				errs[i] = err
+			}
+			continue
+		}
+		for k, i := range g.indexes {
+			if code := resp.Results[k].Error; code != "" {
+				errs[i] = resultError(code)
+				continue
+			}
+			a.base.LogSuccess(a.name, msgs[i])
+		}
+	}
+	return errs
+}
+
+// resultError classifies a per-token error code from the legacy API.
+func resultError(code string) error {
+	err := fmt.Errorf("firebase: %s", code)
+	switch code {
+	case "Unavailable", "InternalServerError", "DeviceMessageRateExceeded":
+		return adapters.Retryable(err, 0)
+	case "NotRegistered", "InvalidRegistration", "MissingRegistration", "MismatchSenderId", "MessageTooBig", "InvalidDataKey":
+		return adapters.Permanent(err)
+	default:
+		return err
+	}
+}
+
+func (a *Adapter) buildPayload(msg adapters.Message) (map[string]any, string, error) {
 	target := strings.TrimSpace(msg.To)
 	if token := stringValue(msg.Metadata, "token"); token != "" {
 		target = token
@@ -98,7 +210,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	topic := stringValue(msg.Metadata, "topic")
 	condition := stringValue(msg.Metadata, "condition")
 	if target == "" && topic == "" && condition == "" {
-		return fmt.Errorf("firebase: a target is required (token, topic, or condition)")
+		return nil, "", fmt.Errorf("firebase: a target is required (token, topic, or condition)")
 	}
 
 	text := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body)
@@ -137,38 +249,42 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	}
 
 	if topic != "" {
-		payload["to"] = "/topics/" + strings.TrimPrefix(topic, "/topics/")
+		target = "/topics/" + strings.TrimPrefix(topic, "/topics/")
+		payload["to"] = target
 	} else if condition != "" {
 		payload["condition"] = condition
 	} else {
 		payload["to"] = target
 	}
-	if a.cfg.DryRun {
-		a.base.LogSuccess(a.name, msg)
-		a.base.Logger().Info("[firebase:during-dry-run] send skipped",
-			"to", target,
-			"subject", msg.Subject,
-		)
-		return nil
-	}
+	return payload, target, nil
+}
+
+func (a *Adapter) logDryRun(msg adapters.Message, target string) {
+	a.base.LogSuccess(a.name, msg)
+	a.base.Logger().Info("[firebase:during-dry-run] send skipped",
+		"to", target,
+		"subject", msg.Subject,
+	)
+}
+
+func (a *Adapter) post(ctx context.Context, payload map[string]any) ([]byte, error) {
 	if strings.TrimSpace(a.cfg.ServerKey) == "" {
-		return fmt.Errorf("firebase: server key required")
+		return nil, fmt.Errorf("firebase: server key required")
 	}
-
 	bodyBytes, err := adapters.EncodeJSONPayload("firebase", payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.Endpoint, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return fmt.Errorf("firebase: build request: %w", err)
+		return nil, fmt.Errorf("firebase: build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "key="+strings.TrimSpace(a.cfg.ServerKey))
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("firebase: request failed: %w", err)
+		return nil, fmt.Errorf("firebase: request failed: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -176,11 +292,9 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return adapters.HTTPStatusError("firebase", resp.StatusCode, respBody)
+		return nil, adapters.HTTPStatusError("firebase", resp.StatusCode, respBody)
 	}
-
-	a.base.LogSuccess(a.name, msg)
-	return nil
+	return respBody, nil
 }
 
 func firstNonEmpty(values ...string) string {
@@ -0,0 +1,56 @@
+package firebase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendBatchMapsMulticastResults(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		if _, ok := body["registration_ids"]; !ok {
+			_, _ = io.WriteString(w, `{"message_id":1}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"success":1,"failure":2,"results":[{"message_id":"m1"},{"error":"NotRegistered"},{"error":"Unavailable"}]}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{ServerKey: "key", Endpoint: server.URL}),
+		WithClient(server.Client()),
+	)
+	errs := adapter.SendBatch(context.Background(), []adapters.Message{
+		{To: "tok-a", Subject: "Sale", Body: "50% off"},
+		{To: "tok-b", Subject: "Sale", Body: "50% off"},
+		{Subject: "Sale", Body: "50% off", Metadata: map[string]any{"topic": "deals"}},
+		{To: "tok-c", Subject: "Sale", Body: "50% off"},
+	})
+
+	if len(requests) != 2 {
+		t.Fatalf("expected a topic send plus one multicast, got %d requests", len(requests))
+	}
+	ids, _ := requests[1]["registration_ids"].([]any)
+	if len(ids) != 3 || ids[0] != "tok-a" || ids[2] != "tok-c" {
+		t.Fatalf("unexpected registration ids %v", requests[1]["registration_ids"])
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Fatalf("expected first token and topic to succeed, got %v", errs)
+	}
+	if errs[1] == nil || adapters.IsRetryable(errs[1]) {
+		t.Fatalf("expected NotRegistered to be permanent, got %v", errs[1])
+	}
+	if !adapters.IsRetryable(errs[3]) {
+		t.Fatalf("expected Unavailable to be retryable, got %v", errs[3])
+	}
+}
@@ -0,0 +1,99 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Content formats shared by templates and Capability.Formats.
+const (
+	FormatText     = "text/plain"
+	FormatHTML     = "text/html"
+	FormatMarkdown = "text/markdown"
+)
+
+// ErrFormatUnsupported is returned when a rendered body cannot be delivered
+// in any format the adapter accepts.
+var ErrFormatUnsupported = errors.New("adapters: format not supported")
+
+// NormalizeFormat maps template format shorthands ("text", "html",
+// "markdown") to the MIME types adapters declare. Unknown values are
+// lower-cased and returned as-is.
+func NormalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if idx := strings.Index(format, ";"); idx >= 0 {
+		format = strings.TrimSpace(format[:idx])
+	}
+	switch format {
+	case "text", "plain", "txt":
+		return FormatText
+	case "html":
+		return FormatHTML
+	case "markdown", "md":
+		return FormatMarkdown
+	default:
+		return format
+	}
+}
+
+// SupportsFormat reports whether the capability accepts format. Adapters
+// that declare no formats accept anything, as do empty formats.
+func (c Capability) SupportsFormat(format string) bool {
+	format = NormalizeFormat(format)
+	if format == "" || len(c.Formats) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(c.Formats, func(candidate string) bool {
+		return NormalizeFormat(candidate) == format
+	})
+}
+
+// NegotiateFormat returns the body to hand to an adapter for content rendered
+// in format, along with the format it is delivered in. Markdown is readable
+// as-is, so it is sent unchanged to text-only adapters. HTML is converted to
+// text only when the adapter sets AutoDowngrade; any other mismatch returns
+// ErrFormatUnsupported.
+func NegotiateFormat(caps Capability, format, body string) (string, string, error) {
+	format = NormalizeFormat(format)
+	if caps.SupportsFormat(format) {
+		return body, format, nil
+	}
+	if caps.SupportsFormat(FormatText) {
+		switch format {
+		case FormatMarkdown:
+			return body, FormatText, nil
+		case FormatHTML:
+			if caps.AutoDowngrade {
+				return HTMLToText(body), FormatText, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s accepts %s, template is %s",
+		ErrFormatUnsupported, caps.Name, strings.Join(caps.Formats, ", "), format)
+}
+
+var (
+	htmlDropBlocks = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlBreaks     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr)>`)
+	htmlTags       = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText strips markup from an HTML body, keeping paragraph and line
+// breaks and decoding entities.
+func HTMLToText(body string) string {
+	text := htmlDropBlocks.ReplaceAllString(body, "")
+	text = htmlBreaks.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
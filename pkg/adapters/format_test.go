@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	textOnly := Capability{Name: "sms", Formats: []string{"text/plain"}}
+	downgrading := Capability{Name: "push", Formats: []string{"text/plain"}, AutoDowngrade: true}
+	rich := Capability{Name: "email", Formats: []string{"text/plain", "text/html"}}
+
+	body, format, err := NegotiateFormat(rich, "html", "<p>Hi</p>")
+	if err != nil || body != "<p>Hi</p>" || format != FormatHTML {
+		t.Fatalf("expected html passthrough, got %q %q %v", body, format, err)
+	}
+
+	if _, _, err := NegotiateFormat(textOnly, "text/html", "<p>Hi</p>"); !errors.Is(err, ErrFormatUnsupported) {
+		t.Fatalf("expected ErrFormatUnsupported, got %v", err)
+	}
+
+	body, format, err = NegotiateFormat(downgrading, "text/html", "<p>Hi &amp; welcome</p><p>Bye</p>")
+	if err != nil || format != FormatText {
+		t.Fatalf("expected downgrade to text, got %q %v", format, err)
+	}
+	if body != "Hi & welcome\nBye" {
+		t.Fatalf("unexpected downgraded body %q", body)
+	}
+
+	body, format, err = NegotiateFormat(textOnly, "text/markdown", "**Hi**")
+	if err != nil || body != "**Hi**" || format != FormatText {
+		t.Fatalf("expected markdown sent as text, got %q %q %v", body, format, err)
+	}
+
+	if _, _, err := NegotiateFormat(Capability{Name: "any"}, "text/html", "<p>Hi</p>"); err != nil {
+		t.Fatalf("expected adapters without formats to accept anything, got %v", err)
+	}
+}
+
+func TestHTMLToTextDropsScriptsAndKeepsBreaks(t *testing.T) {
+	got := HTMLToText("<html><head><title>x</title></head><body><style>p{}</style>Line one<br/>Line two<script>alert(1)</script></body></html>")
+	if got != "Line one\nLine two" {
+		t.Fatalf("unexpected text %q", got)
+	}
+}
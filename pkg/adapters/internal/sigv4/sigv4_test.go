@@ -0,0 +1,40 @@
+package sigv4
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeriveKeyMatchesAWSExample(t *testing.T) {
+	key := deriveKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+	if got := hex.EncodeToString(key); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestNewFormRequestSetsSigningHeaders(t *testing.T) {
+	creds := Credentials{AccessKey: "AKID", SecretKey: "secret", SessionToken: "token"}
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	req, err := NewFormRequest(context.Background(), creds, "sns", "us-west-2", "https://sns.us-west-2.amazonaws.com/", url.Values{"Action": {"Publish"}}, now)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20240301T100000Z" {
+		t.Fatalf("unexpected x-amz-date %q", got)
+	}
+	if got := req.Header.Get("X-Amz-Security-Token"); got != "token" {
+		t.Fatalf("expected session token header, got %q", got)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20240301/us-west-2/sns/aws4_request, ") {
+		t.Fatalf("unexpected credential scope in %q", auth)
+	}
+	if !strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,") {
+		t.Fatalf("unexpected signed headers in %q", auth)
+	}
+}
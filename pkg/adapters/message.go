@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -32,6 +33,9 @@ type Capability struct {
 	Formats        []string
 	MaxAttachments int
 	Metadata       map[string]string
+	// AutoDowngrade lets the dispatcher strip HTML bodies to plain text for
+	// an adapter that does not list text/html, instead of skipping it.
+	AutoDowngrade bool
 }
 
 // Messenger is implemented by channel adapters (SMTP, Twilio, etc).
@@ -46,16 +50,19 @@ var ErrAdapterNotFound = errors.New("adapters: no adapter matches route")
 
 // Registry stores available messengers and matches channels to providers.
 type Registry struct {
-	mu        sync.RWMutex
-	adapters  map[string]Messenger
-	byChannel map[string][]Messenger
+	mu         sync.RWMutex
+	adapters   map[string]Messenger
+	byChannel  map[string][]Messenger
+	strategies map[string]SelectionStrategy
+	ordered    []Messenger // registration order; a re-registered name keeps its slot
 }
 
 // NewRegistry builds a registry with the supplied messengers.
 func NewRegistry(messengers ...Messenger) *Registry {
 	reg := &Registry{
-		adapters:  make(map[string]Messenger),
-		byChannel: make(map[string][]Messenger),
+		adapters:   make(map[string]Messenger),
+		byChannel:  make(map[string][]Messenger),
+		strategies: make(map[string]SelectionStrategy),
 	}
 	for _, m := range messengers {
 		reg.Register(m)
@@ -74,6 +81,13 @@ func (r *Registry) Register(m Messenger) {
 	if name != "" {
 		r.adapters[name] = m
 	}
+	if idx := slices.IndexFunc(r.ordered, func(existing Messenger) bool {
+		return name != "" && normalizeKey(existing.Name()) == name
+	}); idx >= 0 {
+		r.ordered[idx] = m
+	} else {
+		r.ordered = append(r.ordered, m)
+	}
 	for _, channel := range m.Capabilities().Channels {
 		key := normalizeKey(channel)
 		if key == "" {
@@ -121,6 +135,21 @@ func (r *Registry) List(channel string) []Messenger {
 	return out
 }
 
+// ProviderNames returns the provider names registered for a logical channel.
+func (r *Registry) ProviderNames(channel string) []string {
+	messengers := r.List(channel)
+	if len(messengers) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(messengers))
+	for _, m := range messengers {
+		if name := normalizeKey(m.Name()); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 // ParseChannel splits "<channel>[:provider]" into components.
 func ParseChannel(value string) (channel string, provider string) {
 	parts := strings.Split(strings.TrimSpace(value), ":")
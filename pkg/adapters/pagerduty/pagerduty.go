@@ -0,0 +1,305 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+// Event actions supported by the Events API v2.
+const (
+	ActionTrigger     = "trigger"
+	ActionAcknowledge = "acknowledge"
+	ActionResolve     = "resolve"
+)
+
+// maxSummaryLength is the Events API limit for payload.summary.
+const maxSummaryLength = 1024
+
+// Adapter triggers, acknowledges and resolves PagerDuty incidents through
+// the Events API v2.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+}
+
+// Config holds PagerDuty settings. RoutingKey is used when the resolved
+// secrets do not carry one.
+type Config struct {
+	RoutingKey string
+	Endpoint   string // defaults to https://events.pagerduty.com/v2/enqueue
+	Source     string // payload.source, defaults to go-notifications
+	Component  string
+	Group      string
+	Class      string
+	Timeout    time.Duration
+	Transport  adapters.HTTPTransportConfig
+	DryRun     bool
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter provider name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets adapter configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithClient sets a custom HTTP client.
+func WithClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the PagerDuty adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "pagerduty",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:          "pagerduty",
+			Channels:      []string{"alert", "pagerduty"},
+			Formats:       []string{"text/plain"},
+			AutoDowngrade: true,
+		},
+		cfg: Config{
+			Timeout: 10 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	routingKey := strings.TrimSpace(firstNonEmpty(
+		secretString(msg.Metadata, "routing_key"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.RoutingKey,
+	))
+	if routingKey == "" && !a.cfg.DryRun {
+		return fmt.Errorf("pagerduty: routing key required")
+	}
+
+	action := strings.ToLower(firstNonEmpty(stringValue(msg.Metadata, "event_action"), ActionTrigger))
+	dedupKey := firstNonEmpty(
+		stringValue(msg.Metadata, "dedup_key"),
+		defaultDedupKey(msg.Metadata),
+	)
+	event := map[string]any{
+		"routing_key":  routingKey,
+		"event_action": action,
+	}
+	if dedupKey != "" {
+		event["dedup_key"] = dedupKey
+	}
+
+	switch action {
+	case ActionTrigger:
+		payload, err := a.triggerPayload(msg)
+		if err != nil {
+			return err
+		}
+		event["payload"] = payload
+		if actionURL := stringValue(msg.Metadata, "action_url"); actionURL != "" {
+			event["links"] = []map[string]string{{"href": actionURL, "text": "Open"}}
+		}
+	case ActionAcknowledge, ActionResolve:
+		if dedupKey == "" {
+			return adapters.Permanent(fmt.Errorf("pagerduty: dedup_key required to %s", action))
+		}
+	default:
+		return adapters.Permanent(fmt.Errorf("pagerduty: unsupported event action %q", action))
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[pagerduty:during-dry-run] send skipped",
+			"action", action,
+			"dedup_key", dedupKey,
+		)
+		return nil
+	}
+
+	bodyBytes, err := adapters.EncodeJSONPayload("pagerduty", event)
+	if err != nil {
+		return err
+	}
+	endpoint := firstNonEmpty(strings.TrimSpace(a.cfg.Endpoint), "https://events.pagerduty.com/v2/enqueue")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("pagerduty: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pagerduty: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := adapters.HTTPStatusError("pagerduty", resp.StatusCode, data)
+		return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+func (a *Adapter) triggerPayload(msg adapters.Message) (map[string]any, error) {
+	body := strings.TrimSpace(firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body))
+	summary := strings.TrimSpace(firstNonEmpty(msg.Subject, body))
+	if summary == "" {
+		return nil, fmt.Errorf("pagerduty: summary required")
+	}
+	if runes := []rune(summary); len(runes) > maxSummaryLength {
+		summary = string(runes[:maxSummaryLength])
+	}
+
+	details := map[string]any{}
+	if body != "" && body != summary {
+		details["body"] = body
+	}
+	for _, key := range []string{"definition_code", "event_id"} {
+		if value := stringValue(msg.Metadata, key); value != "" {
+			details[key] = value
+		}
+	}
+	if custom, ok := msg.Metadata["custom_details"].(map[string]any); ok {
+		maps.Copy(details, custom)
+	}
+
+	payload := map[string]any{
+		"summary":   summary,
+		"source":    firstNonEmpty(stringValue(msg.Metadata, "source"), a.cfg.Source, "go-notifications"),
+		"severity":  Severity(stringValue(msg.Metadata, "severity")),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+	if component := firstNonEmpty(stringValue(msg.Metadata, "component"), a.cfg.Component); component != "" {
+		payload["component"] = component
+	}
+	if group := firstNonEmpty(stringValue(msg.Metadata, "group"), a.cfg.Group); group != "" {
+		payload["group"] = group
+	}
+	if class := firstNonEmpty(stringValue(msg.Metadata, "class"), a.cfg.Class); class != "" {
+		payload["class"] = class
+	}
+	if len(details) > 0 {
+		payload["custom_details"] = details
+	}
+	return payload, nil
+}
+
+// Severity maps a notification definition severity onto the four levels
+// the Events API accepts. Unknown or empty values become "error".
+func Severity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical", "fatal", "emergency", "urgent":
+		return "critical"
+	case "warning", "warn", "medium":
+		return "warning"
+	case "info", "information", "low", "notice":
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+// defaultDedupKey groups every recipient of one event into a single
+// incident when no explicit dedup_key is set.
+func defaultDedupKey(meta map[string]any) string {
+	eventID := stringValue(meta, "event_id")
+	if eventID == "" {
+		return ""
+	}
+	return firstNonEmpty(stringValue(meta, "definition_code"), "event") + ":" + eventID
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
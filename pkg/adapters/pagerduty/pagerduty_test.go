@@ -0,0 +1,169 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendTriggersIncident(t *testing.T) {
+	var event map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, `{"status":"success","dedup_key":"db-disk"}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{Endpoint: server.URL, Source: "db-1"}), WithClient(server.Client()))
+	if adapter.Name() != "pagerduty" {
+		t.Fatalf("expected provider name pagerduty, got %q", adapter.Name())
+	}
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "alert",
+		Subject: "Disk almost full",
+		Body:    "db-1 is at 98%",
+		Metadata: map[string]any{
+			"secrets":         map[string][]byte{"routing_key": []byte("R0UT1NG")},
+			"severity":        "critical",
+			"dedup_key":       "db-disk",
+			"definition_code": "disk_alert",
+			"action_url":      "https://status.example.com/db-1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	if event["routing_key"] != "R0UT1NG" || event["event_action"] != "trigger" || event["dedup_key"] != "db-disk" {
+		t.Fatalf("unexpected envelope: %+v", event)
+	}
+	payload, _ := event["payload"].(map[string]any)
+	if payload["summary"] != "Disk almost full" || payload["severity"] != "critical" || payload["source"] != "db-1" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	details, _ := payload["custom_details"].(map[string]any)
+	if details["body"] != "db-1 is at 98%" || details["definition_code"] != "disk_alert" {
+		t.Fatalf("unexpected custom details: %+v", details)
+	}
+	links, _ := event["links"].([]any)
+	if len(links) != 1 {
+		t.Fatalf("expected action_url link, got %+v", event["links"])
+	}
+}
+
+func TestSendResolveRequiresDedupKey(t *testing.T) {
+	adapter := New(&logger.Nop{}, WithConfig(Config{RoutingKey: "key", DryRun: true}))
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel:  "alert",
+		Subject:  "Disk almost full",
+		Metadata: map[string]any{"event_action": "resolve"},
+	})
+	if err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+}
+
+func TestSendResolveOmitsPayload(t *testing.T) {
+	var event map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{Endpoint: server.URL, RoutingKey: "key"}), WithClient(server.Client()))
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel:  "alert",
+		Metadata: map[string]any{"event_action": "resolve", "dedup_key": "db-disk"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if event["event_action"] != "resolve" || event["dedup_key"] != "db-disk" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if _, ok := event["payload"]; ok {
+		t.Fatalf("resolve should not carry a payload: %+v", event)
+	}
+}
+
+func TestSendDerivesDedupKeyFromEvent(t *testing.T) {
+	var event map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{Endpoint: server.URL, RoutingKey: "key"}), WithClient(server.Client()))
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel:  "alert",
+		Body:     strings.Repeat("x", 2000),
+		Metadata: map[string]any{"event_id": "evt-1", "definition_code": "disk_alert"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if event["dedup_key"] != "disk_alert:evt-1" {
+		t.Fatalf("expected derived dedup key, got %v", event["dedup_key"])
+	}
+	payload, _ := event["payload"].(map[string]any)
+	if summary, _ := payload["summary"].(string); len(summary) != maxSummaryLength {
+		t.Fatalf("expected summary truncated to %d, got %d", maxSummaryLength, len(summary))
+	}
+	if payload["severity"] != "error" {
+		t.Fatalf("expected default severity error, got %v", payload["severity"])
+	}
+}
+
+func TestSendClassifiesErrors(t *testing.T) {
+	cases := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+	}
+	for _, tc := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = io.WriteString(w, `{"status":"invalid event"}`)
+		}))
+		adapter := New(&logger.Nop{}, WithConfig(Config{Endpoint: server.URL, RoutingKey: "key"}), WithClient(server.Client()))
+		err := adapter.Send(context.Background(), adapters.Message{Channel: "alert", Subject: "down"})
+		server.Close()
+		if err == nil {
+			t.Fatalf("status %d: expected error", tc.status)
+		}
+		if adapters.IsRetryable(err) != tc.retryable {
+			t.Fatalf("status %d: expected retryable=%v, got %v", tc.status, tc.retryable, err)
+		}
+	}
+}
+
+func TestSeverityMapping(t *testing.T) {
+	cases := map[string]string{
+		"critical": "critical",
+		"Warning":  "warning",
+		"low":      "info",
+		"error":    "error",
+		"":         "error",
+		"bogus":    "error",
+	}
+	for in, want := range cases {
+		if got := Severity(in); got != want {
+			t.Fatalf("Severity(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
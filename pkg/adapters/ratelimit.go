@@ -0,0 +1,126 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter wraps a Messenger and throttles Send with a token bucket so a
+// broadcast stays under the provider's per-second cap. Name and
+// Capabilities are those of the wrapped adapter.
+type RateLimiter struct {
+	inner  Messenger
+	bucket *tokenBucket
+}
+
+// WithRateLimit wraps inner so Send waits for a token, refilled at rps per
+// second up to burst. A non-positive rps disables limiting; burst is at
+// least 1. When inner implements BatchMessenger the wrapper does too, and
+// each SendBatch call takes one token since it is a single request.
+func WithRateLimit(inner Messenger, rps float64, burst int) Messenger {
+	if inner == nil {
+		return nil
+	}
+	limiter := &RateLimiter{inner: inner, bucket: newTokenBucket(rps, burst, time.Now)}
+	if _, ok := inner.(BatchMessenger); ok {
+		return &batchRateLimiter{RateLimiter: limiter}
+	}
+	return limiter
+}
+
+func (r *RateLimiter) Name() string { return r.inner.Name() }
+
+func (r *RateLimiter) Capabilities() Capability { return r.inner.Capabilities() }
+
+// Send waits for a token, returning the context error if ctx ends first.
+func (r *RateLimiter) Send(ctx context.Context, msg Message) error {
+	if err := r.bucket.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.Send(ctx, msg)
+}
+
+// Unwrap returns the wrapped adapter.
+func (r *RateLimiter) Unwrap() Messenger { return r.inner }
+
+type batchRateLimiter struct {
+	*RateLimiter
+}
+
+func (r *batchRateLimiter) SendBatch(ctx context.Context, msgs []Message) []error {
+	if err := r.bucket.wait(ctx); err != nil {
+		return BatchErrors(len(msgs), err)
+	}
+	return r.inner.(BatchMessenger).SendBatch(ctx, msgs)
+}
+
+// tokenBucket hands out reservations: a caller takes a token immediately,
+// possibly driving the balance negative, and sleeps until the bucket has
+// refilled to cover it. Cancelled waits return their token.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rps float64, burst int, now func() time.Time) *tokenBucket {
+	burst = max(burst, 1)
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+		now:    now,
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.release()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait for it.
+func (b *tokenBucket) reserve() time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) release() {
+	if b.rate <= 0 {
+		return
+	}
+	b.mu.Lock()
+	b.tokens = min(b.burst, b.tokens+1)
+	b.mu.Unlock()
+}
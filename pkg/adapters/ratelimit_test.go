@@ -0,0 +1,70 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReservesAgainstRefill(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(2, 2, func() time.Time { return now })
+
+	if d := bucket.reserve(); d != 0 {
+		t.Fatalf("expected burst token immediately, got %v", d)
+	}
+	if d := bucket.reserve(); d != 0 {
+		t.Fatalf("expected second burst token immediately, got %v", d)
+	}
+	if d := bucket.reserve(); d != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait at 2 rps, got %v", d)
+	}
+	if d := bucket.reserve(); d != time.Second {
+		t.Fatalf("expected queued reservations to stack, got %v", d)
+	}
+
+	now = now.Add(10 * time.Second)
+	if d := bucket.reserve(); d != 0 {
+		t.Fatalf("expected refill capped at burst, got %v", d)
+	}
+}
+
+func TestWithRateLimitRespectsContextAndKeepsIdentity(t *testing.T) {
+	inner := namedMessenger{name: "twilio", channels: []string{"sms"}}
+	limited := WithRateLimit(inner, 1, 1)
+	if limited.Name() != "twilio" || limited.Capabilities().Name != "twilio" {
+		t.Fatalf("expected wrapped name and capabilities, got %s", limited.Name())
+	}
+	if _, ok := limited.(BatchMessenger); ok {
+		t.Fatalf("did not expect batch support for a plain messenger")
+	}
+
+	ctx := context.Background()
+	if err := limited.Send(ctx, Message{}); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limited.Send(ctx, Message{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error while waiting, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected wait to stop at the deadline, took %v", elapsed)
+	}
+}
+
+func TestWithRateLimitPreservesBatchSupport(t *testing.T) {
+	inner := &batchStub{
+		namedMessenger: namedMessenger{name: "bulk"},
+		results:        func(msgs []Message) []error { return make([]error, len(msgs)) },
+	}
+	limited, ok := WithRateLimit(inner, 0, 0).(BatchMessenger)
+	if !ok {
+		t.Fatalf("expected batch support to be preserved")
+	}
+	if errs := limited.SendBatch(context.Background(), []Message{{}, {}}); len(errs) != 2 || len(inner.batches) != 1 {
+		t.Fatalf("expected one batch forwarded, got errs=%v batches=%d", errs, len(inner.batches))
+	}
+}
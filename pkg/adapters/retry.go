@@ -0,0 +1,151 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryableError marks a delivery failure as transient. RetryAfter, when
+// positive, is the delay the provider asked for before the next attempt.
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	if e == nil || e.Err == nil {
+		return "retryable delivery failure"
+	}
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// PermanentError marks a delivery failure that will not succeed on retry,
+// such as a rejected payload or an unregistered device.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	if e == nil || e.Err == nil {
+		return "permanent delivery failure"
+	}
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// ErrCredentialsRejected matches, through errors.Is, failures where the
+// provider refused the credentials it was given. During a secret rotation
+// the dispatcher resolves the secret again and retries once with the newer
+// version.
+var ErrCredentialsRejected = errors.New("adapters: credentials rejected")
+
+type credentialsError struct {
+	err error
+}
+
+func (e *credentialsError) Error() string { return e.err.Error() }
+
+func (e *credentialsError) Unwrap() error { return e.err }
+
+func (e *credentialsError) Is(target error) bool { return target == ErrCredentialsRejected }
+
+// CredentialsRejected marks err as a permanent failure caused by rejected
+// credentials, keeping its message unchanged.
+func CredentialsRejected(err error) error {
+	if err == nil {
+		return nil
+	}
+	return Permanent(&credentialsError{err: err})
+}
+
+// Retryable wraps err as a transient failure with an optional retry delay.
+func Retryable(err error, retryAfter time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err, RetryAfter: retryAfter}
+}
+
+// Permanent wraps err so the dispatcher stops retrying it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsRetryable reports whether a failed send is worth another attempt.
+// Errors are retryable unless they are marked permanent.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var permanent *PermanentError
+	return !errors.As(err, &permanent)
+}
+
+// RetryAfter returns the provider requested delay carried by err, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryable *RetryableError
+	if errors.As(err, &retryable) && retryable.RetryAfter > 0 {
+		return retryable.RetryAfter, true
+	}
+	return 0, false
+}
+
+// ClassifyHTTPStatus wraps err according to the response status: 408, 425,
+// 429 and 5xx are retryable, any other 4xx is permanent. A 401 is also
+// marked with ErrCredentialsRejected.
+func ClassifyHTTPStatus(err error, statusCode int, retryAfter time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooEarly ||
+		statusCode == http.StatusTooManyRequests || statusCode >= 500:
+		return Retryable(err, retryAfter)
+	case statusCode == http.StatusUnauthorized:
+		return CredentialsRejected(err)
+	case statusCode >= http.StatusBadRequest:
+		return Permanent(err)
+	default:
+		return err
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header value given either in seconds
+// or as an HTTP date relative to now.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClassifyHTTPStatus(t *testing.T) {
+	base := errors.New("boom")
+	cases := map[int]bool{
+		http.StatusBadRequest:          false,
+		http.StatusUnauthorized:        false,
+		http.StatusNotFound:            false,
+		http.StatusRequestTimeout:      true,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+	}
+	for status, retryable := range cases {
+		err := ClassifyHTTPStatus(base, status, 0)
+		if got := IsRetryable(err); got != retryable {
+			t.Fatalf("status %d: expected retryable=%v, got %v", status, retryable, got)
+		}
+		if !errors.Is(err, base) {
+			t.Fatalf("status %d: expected wrapped error to unwrap", status)
+		}
+	}
+
+	if err := ClassifyHTTPStatus(base, http.StatusUnauthorized, 0); !errors.Is(err, ErrCredentialsRejected) || err.Error() != base.Error() {
+		t.Fatalf("expected 401 to be marked as rejected credentials, got %v", err)
+	}
+	if err := ClassifyHTTPStatus(base, http.StatusForbidden, 0); errors.Is(err, ErrCredentialsRejected) {
+		t.Fatalf("expected 403 not to be marked as rejected credentials")
+	}
+
+	err := ClassifyHTTPStatus(base, http.StatusTooManyRequests, 3*time.Second)
+	if after, ok := RetryAfter(err); !ok || after != 3*time.Second {
+		t.Fatalf("expected retry-after 3s, got %v %v", after, ok)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if got := ParseRetryAfter("7", now); got != 7*time.Second {
+		t.Fatalf("expected 7s, got %v", got)
+	}
+	if got := ParseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); got != 90*time.Second {
+		t.Fatalf("expected 90s from HTTP date, got %v", got)
+	}
+	if got := ParseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("expected 0 for invalid value, got %v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package adapters
+
+import "slices"
+
+// SecretKeyer is implemented by adapters that read secrets under keys other
+// than "default", such as SES access keys or SMTP credentials. The dispatcher
+// resolves every listed key next to "default" and hands the adapter all keys
+// found in the most specific scope that has any of them.
+type SecretKeyer interface {
+	SecretKeys() []string
+}
+
+// SecretKeys returns the secret keys m reads: "default" first, followed by
+// the keys declared through SecretKeyer. Wrappers that expose
+// Unwrap() Messenger, such as RateLimiter, are looked through.
+func SecretKeys(m Messenger) []string {
+	keys := []string{"default"}
+	for m != nil {
+		if keyer, ok := m.(SecretKeyer); ok {
+			for _, key := range keyer.SecretKeys() {
+				if key != "" && !slices.Contains(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+			return keys
+		}
+		wrapper, ok := m.(interface{ Unwrap() Messenger })
+		if !ok {
+			break
+		}
+		m = wrapper.Unwrap()
+	}
+	return keys
+}
@@ -0,0 +1,171 @@
+package adapters
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+// Built-in provider selection strategies.
+const (
+	StrategyFirst      = "first"
+	StrategyRoundRobin = "round-robin"
+	StrategyWeighted   = "weighted"
+)
+
+// SelectionStrategy orders the candidates registered for a channel. The
+// dispatcher tries the first entry and falls back to the rest in order.
+type SelectionStrategy interface {
+	Order(channel string, candidates []Messenger) []Messenger
+}
+
+// SelectionStrategyFunc adapts a function to SelectionStrategy.
+type SelectionStrategyFunc func(channel string, candidates []Messenger) []Messenger
+
+func (f SelectionStrategyFunc) Order(channel string, candidates []Messenger) []Messenger {
+	return f(channel, candidates)
+}
+
+// NewSelectionStrategy builds a built-in strategy by name. Weights are only
+// used by the weighted strategy and are keyed by provider name.
+func NewSelectionStrategy(name string, weights map[string]int) (SelectionStrategy, error) {
+	switch normalizeKey(name) {
+	case "", StrategyFirst:
+		return FirstStrategy{}, nil
+	case StrategyRoundRobin, "round_robin", "roundrobin":
+		return NewRoundRobinStrategy(), nil
+	case StrategyWeighted:
+		return NewWeightedStrategy(weights), nil
+	default:
+		return nil, fmt.Errorf("adapters: unknown selection strategy %q", name)
+	}
+}
+
+// FirstStrategy keeps registration order, so the first registered provider
+// takes all traffic and the others only act as fallbacks.
+type FirstStrategy struct{}
+
+func (FirstStrategy) Order(_ string, candidates []Messenger) []Messenger {
+	return candidates
+}
+
+// RoundRobinStrategy rotates the starting provider on every selection.
+type RoundRobinStrategy struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// NewRoundRobinStrategy returns a strategy with an independent counter per
+// channel.
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{next: make(map[string]int)}
+}
+
+func (s *RoundRobinStrategy) Order(channel string, candidates []Messenger) []Messenger {
+	if len(candidates) < 2 {
+		return candidates
+	}
+	s.mu.Lock()
+	start := s.next[channel] % len(candidates)
+	s.next[channel] = start + 1
+	s.mu.Unlock()
+	return append(slices.Clone(candidates[start:]), candidates[:start]...)
+}
+
+// WeightedStrategy picks the starting provider at random in proportion to
+// its weight; the others follow in registration order. Providers without a
+// weight count as 1, and a weight of 0 keeps a provider as fallback only.
+type WeightedStrategy struct {
+	weights map[string]int
+	intN    func(int) int
+}
+
+// NewWeightedStrategy builds a weighted strategy keyed by provider name.
+func NewWeightedStrategy(weights map[string]int) *WeightedStrategy {
+	normalized := make(map[string]int, len(weights))
+	for name, weight := range weights {
+		normalized[normalizeKey(name)] = weight
+	}
+	return &WeightedStrategy{weights: normalized, intN: rand.IntN}
+}
+
+func (s *WeightedStrategy) Order(_ string, candidates []Messenger) []Messenger {
+	if len(candidates) < 2 {
+		return candidates
+	}
+	total := 0
+	for _, m := range candidates {
+		total += s.weight(m)
+	}
+	if total <= 0 {
+		return candidates
+	}
+	pick := s.intN(total)
+	for i, m := range candidates {
+		pick -= s.weight(m)
+		if pick < 0 {
+			out := make([]Messenger, 0, len(candidates))
+			out = append(out, m)
+			out = append(out, candidates[:i]...)
+			return append(out, candidates[i+1:]...)
+		}
+	}
+	return candidates
+}
+
+func (s *WeightedStrategy) weight(m Messenger) int {
+	weight, ok := s.weights[normalizeKey(m.Name())]
+	if !ok {
+		return 1
+	}
+	return max(weight, 0)
+}
+
+// SetStrategy configures how Select orders providers for a channel. A nil
+// strategy restores registration order.
+func (r *Registry) SetStrategy(channel string, strategy SelectionStrategy) {
+	if r == nil {
+		return
+	}
+	key := normalizeKey(channel)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if strategy == nil {
+		delete(r.strategies, key)
+		return
+	}
+	if r.strategies == nil {
+		r.strategies = make(map[string]SelectionStrategy)
+	}
+	r.strategies[key] = strategy
+}
+
+// Select returns the candidates for a channel in the order they should be
+// tried. An explicit provider ("sms:twilio") is moved to the front without
+// consulting the strategy; otherwise the channel's strategy decides.
+func (r *Registry) Select(channel string) []Messenger {
+	candidates := r.List(channel)
+	if len(candidates) == 0 {
+		return candidates
+	}
+	base, provider := ParseChannel(channel)
+	if provider != "" {
+		idx := slices.IndexFunc(candidates, func(m Messenger) bool {
+			return normalizeKey(m.Name()) == provider
+		})
+		if idx > 0 {
+			// List returns a copy, so reordering in place is safe.
+			chosen := candidates[idx]
+			candidates = append([]Messenger{chosen}, slices.Delete(candidates, idx, idx+1)...)
+		}
+		return candidates
+	}
+	r.mu.RLock()
+	strategy := r.strategies[normalizeKey(base)]
+	r.mu.RUnlock()
+	if strategy == nil {
+		return candidates
+	}
+	return strategy.Order(base, candidates)
+}
@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+)
+
+type namedMessenger struct {
+	name     string
+	channels []string
+}
+
+func (m namedMessenger) Name() string { return m.name }
+
+func (m namedMessenger) Capabilities() Capability {
+	return Capability{Name: m.name, Channels: m.channels}
+}
+
+func (namedMessenger) Send(context.Context, Message) error { return nil }
+
+func names(messengers []Messenger) []string {
+	out := make([]string, 0, len(messengers))
+	for _, m := range messengers {
+		out = append(out, m.Name())
+	}
+	return out
+}
+
+func newSMSRegistry() *Registry {
+	return NewRegistry(
+		namedMessenger{name: "twilio", channels: []string{"sms"}},
+		namedMessenger{name: "aws_sns", channels: []string{"sms"}},
+		namedMessenger{name: "vonage", channels: []string{"sms"}},
+	)
+}
+
+func TestRegistrySelectDefaultsToRegistrationOrder(t *testing.T) {
+	reg := newSMSRegistry()
+	for i := 0; i < 2; i++ {
+		if got := names(reg.Select("sms")); got[0] != "twilio" || got[2] != "vonage" {
+			t.Fatalf("expected registration order, got %v", got)
+		}
+	}
+}
+
+func TestRegistrySelectRoundRobin(t *testing.T) {
+	reg := newSMSRegistry()
+	reg.SetStrategy("SMS", NewRoundRobinStrategy())
+
+	want := [][]string{
+		{"twilio", "aws_sns", "vonage"},
+		{"aws_sns", "vonage", "twilio"},
+		{"vonage", "twilio", "aws_sns"},
+		{"twilio", "aws_sns", "vonage"},
+	}
+	for i, expected := range want {
+		got := names(reg.Select("sms"))
+		for j := range expected {
+			if got[j] != expected[j] {
+				t.Fatalf("selection %d: expected %v, got %v", i, expected, got)
+			}
+		}
+	}
+	if got := names(reg.List("sms")); got[0] != "twilio" {
+		t.Fatalf("expected List to keep registration order, got %v", got)
+	}
+}
+
+func TestRegistrySelectWeighted(t *testing.T) {
+	reg := newSMSRegistry()
+	strategy := NewWeightedStrategy(map[string]int{"twilio": 3, "aws_sns": 1, "vonage": 0})
+	picks := []int{0, 2, 3}
+	strategy.intN = func(n int) int {
+		if n != 4 {
+			t.Fatalf("expected total weight 4, got %d", n)
+		}
+		pick := picks[0]
+		picks = picks[1:]
+		return pick
+	}
+	reg.SetStrategy("sms", strategy)
+
+	for _, expected := range []string{"twilio", "twilio", "aws_sns"} {
+		got := names(reg.Select("sms"))
+		if got[0] != expected || len(got) != 3 {
+			t.Fatalf("expected %s first with fallbacks, got %v", expected, got)
+		}
+	}
+}
+
+func TestRegistrySelectExplicitProviderGoesFirst(t *testing.T) {
+	reg := newSMSRegistry()
+	reg.SetStrategy("sms", NewRoundRobinStrategy())
+	if got := names(reg.Select("sms:vonage")); got[0] != "vonage" || len(got) != 3 {
+		t.Fatalf("expected vonage first, got %v", got)
+	}
+}
+
+func TestNewSelectionStrategyRejectsUnknownName(t *testing.T) {
+	if _, err := NewSelectionStrategy("random", nil); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+	if _, err := NewSelectionStrategy("round-robin", nil); err != nil {
+		t.Fatalf("round-robin: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package sendgrid
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -114,6 +115,77 @@ func (a *Adapter) Name() string { return a.name }
 func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
 
 func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	env, personalization, err := a.prepare(msg)
+	if err != nil {
+		return err
+	}
+	if err := a.post(ctx, env, msg.Subject, []map[string]any{personalization}); err != nil {
+		return err
+	}
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// maxPersonalizations is the SendGrid limit per mail/send request.
+const maxPersonalizations = 1000
+
+// SendBatch folds messages that share credentials, sender, headers and
+// content into one request with a personalization per recipient. SendGrid
+// accepts or rejects a request as a whole, so every message in a request
+// gets the same error.
+func (a *Adapter) SendBatch(ctx context.Context, msgs []adapters.Message) []error {
+	errs := make([]error, len(msgs))
+	type group struct {
+		env     envelope
+		subject string
+		indexes []int
+		people  []map[string]any
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	for i, msg := range msgs {
+		env, personalization, err := a.prepare(msg)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		personalization["subject"] = msg.Subject
+		key := env.key()
+		g := byKey[key]
+		if g == nil || len(g.indexes) == maxPersonalizations {
+			g = &group{env: env, subject: msg.Subject}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.indexes = append(g.indexes, i)
+		g.people = append(g.people, personalization)
+	}
+	for _, g := range groups {
+		err := a.post(ctx, g.env, g.subject, g.people)
+		for _, i := range g.indexes {
+			errs[i] = err
+			if err == nil {
+				a.base.LogSuccess(a.name, msgs[i])
+			}
+		}
+	}
+	return errs
+}
+
+// envelope holds the request fields SendGrid shares across personalizations.
+type envelope struct {
+	apiKey  string
+	from    string
+	content []map[string]string
+	headers map[string]string
+}
+
+func (e envelope) key() string {
+	raw, _ := json.Marshal([]any{e.apiKey, e.from, e.content, e.headers})
+	return string(raw)
+}
+
+func (a *Adapter) prepare(msg adapters.Message) (envelope, map[string]any, error) {
 	apiKey := strings.TrimSpace(firstNonEmpty(
 		stringValue(msg.Metadata, "api_key"),
 		secretString(msg.Metadata, "api_key"),
@@ -121,10 +193,10 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		a.cfg.APIKey,
 	))
 	if apiKey == "" {
-		return fmt.Errorf("sendgrid: api key required")
+		return envelope{}, nil, fmt.Errorf("sendgrid: api key required")
 	}
 	if strings.TrimSpace(msg.To) == "" {
-		return fmt.Errorf("sendgrid: destination required")
+		return envelope{}, nil, fmt.Errorf("sendgrid: destination required")
 	}
 	from := firstNonEmpty(
 		stringValue(msg.Metadata, "from"),
@@ -132,7 +204,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		a.cfg.From,
 	)
 	if strings.TrimSpace(from) == "" {
-		return fmt.Errorf("sendgrid: from required")
+		return envelope{}, nil, fmt.Errorf("sendgrid: from required")
 	}
 
 	textBody := firstNonEmpty(stringValue(msg.Metadata, "text_body"), stringValue(msg.Metadata, "body"), msg.Body)
@@ -153,18 +225,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		content = append(content, map[string]string{"type": "text/html", "value": htmlBody})
 	}
 	if len(content) == 0 {
-		return fmt.Errorf("sendgrid: content empty")
-	}
-
-	requestBody := map[string]any{
-		"personalizations": []any{personalization},
-		"from":             map[string]string{"email": from},
-		"subject":          msg.Subject,
-		"content":          content,
-	}
-
-	if hdrs := msg.Headers; len(hdrs) > 0 {
-		requestBody["headers"] = hdrs
+		return envelope{}, nil, fmt.Errorf("sendgrid: content empty")
 	}
 
 	if cc := stringSlice(msg.Metadata, "cc"); len(cc) > 0 {
@@ -182,6 +243,20 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		personalization["bcc"] = bccList
 	}
 
+	return envelope{apiKey: apiKey, from: from, content: content, headers: msg.Headers}, personalization, nil
+}
+
+func (a *Adapter) post(ctx context.Context, env envelope, subject string, personalizations []map[string]any) error {
+	requestBody := map[string]any{
+		"personalizations": personalizations,
+		"from":             map[string]string{"email": env.from},
+		"subject":          subject,
+		"content":          env.content,
+	}
+	if len(env.headers) > 0 {
+		requestBody["headers"] = env.headers
+	}
+
 	bodyBytes, err := adapters.EncodeJSONPayload("sendgrid", requestBody)
 	if err != nil {
 		return err
@@ -190,7 +265,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	if err != nil {
 		return fmt.Errorf("sendgrid: build request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Authorization", "Bearer "+env.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := a.client.Do(req)
@@ -205,8 +280,6 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return adapters.HTTPStatusError("sendgrid", resp.StatusCode, respBody)
 	}
-
-	a.base.LogSuccess(a.name, msg)
 	return nil
 }
 
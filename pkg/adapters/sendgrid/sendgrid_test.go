@@ -0,0 +1,72 @@
+package sendgrid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendBatchFoldsSharedContentIntoPersonalizations(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithAPIKey("SG.test"),
+		WithFrom("noreply@example.com"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+	errs := adapter.SendBatch(context.Background(), []adapters.Message{
+		{To: "a@example.com", Subject: "Digest", Body: "Weekly news"},
+		{To: "", Subject: "Digest", Body: "Weekly news"},
+		{To: "b@example.com", Subject: "Digest for B", Body: "Weekly news"},
+		{To: "c@example.com", Subject: "Digest", Body: "Something else"},
+	})
+
+	if len(errs) != 4 || errs[0] != nil || errs[1] == nil || errs[2] != nil || errs[3] != nil {
+		t.Fatalf("expected only the message without destination to fail, got %v", errs)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected one request per distinct content, got %d", len(requests))
+	}
+	people, _ := requests[0]["personalizations"].([]any)
+	if len(people) != 2 {
+		t.Fatalf("expected two personalizations in the shared request, got %v", requests[0]["personalizations"])
+	}
+	second, _ := people[1].(map[string]any)
+	if second["subject"] != "Digest for B" {
+		t.Fatalf("expected per-recipient subject, got %v", second["subject"])
+	}
+}
+
+func TestSendBatchSharesRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithAPIKey("SG.bad"),
+		WithFrom("noreply@example.com"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+	errs := adapter.SendBatch(context.Background(), []adapters.Message{
+		{To: "a@example.com", Subject: "Hi", Body: "Hello"},
+		{To: "b@example.com", Subject: "Hi", Body: "Hello"},
+	})
+	if errs[0] == nil || errs[1] == nil {
+		t.Fatalf("expected the request error on every message, got %v", errs)
+	}
+}
@@ -0,0 +1,283 @@
+package ses
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/internal/sigv4"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+// Adapter delivers email through the Amazon SES query API, signing requests
+// with SigV4 directly instead of going through the AWS SDK.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+}
+
+// Config holds SES settings. Keys left empty fall back to the resolved
+// secrets and then to the standard AWS environment variables.
+type Config struct {
+	Region           string
+	AccessKey        string
+	SecretKey        string
+	SessionToken     string
+	From             string
+	ReplyTo          string
+	ConfigurationSet string
+	Endpoint         string // defaults to https://email.<region>.amazonaws.com/
+	DryRun           bool
+	Timeout          time.Duration
+	Transport        adapters.HTTPTransportConfig
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets SES configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithHTTPClient injects a custom HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the SES adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "ses",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:     "ses",
+			Channels: []string{"email"},
+			Formats:  []string{"text/plain", "text/html"},
+		},
+		cfg: Config{
+			Region:  "us-east-1",
+			Timeout: 10 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+// SecretKeys lists the secret keys Send reads besides "default".
+func (a *Adapter) SecretKeys() []string {
+	return []string{"access_key_id", "secret_access_key", "session_token", "from"}
+}
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	to := strings.TrimSpace(msg.To)
+	if to == "" {
+		return fmt.Errorf("ses: destination required")
+	}
+	from := firstNonEmpty(stringValue(msg.Metadata, "from"), secretString(msg.Metadata, "from"), a.cfg.From)
+	if strings.TrimSpace(from) == "" {
+		return fmt.Errorf("ses: from required")
+	}
+	textBody := firstNonEmpty(stringValue(msg.Metadata, "text_body"), stringValue(msg.Metadata, "body"), msg.Body)
+	htmlBody := stringValue(msg.Metadata, "html_body")
+	if strings.TrimSpace(textBody) == "" && htmlBody == "" {
+		return fmt.Errorf("ses: content empty")
+	}
+
+	params := url.Values{}
+	params.Set("Action", "SendEmail")
+	params.Set("Version", "2010-12-01")
+	params.Set("Source", from)
+	params.Set("Destination.ToAddresses.member.1", to)
+	setMembers(params, "Destination.CcAddresses.member", stringSlice(msg.Metadata, "cc"))
+	setMembers(params, "Destination.BccAddresses.member", stringSlice(msg.Metadata, "bcc"))
+	if replyTo := firstNonEmpty(stringValue(msg.Metadata, "reply_to"), a.cfg.ReplyTo); replyTo != "" {
+		params.Set("ReplyToAddresses.member.1", replyTo)
+	}
+	params.Set("Message.Subject.Data", msg.Subject)
+	params.Set("Message.Subject.Charset", "UTF-8")
+	if strings.TrimSpace(textBody) != "" {
+		params.Set("Message.Body.Text.Data", textBody)
+		params.Set("Message.Body.Text.Charset", "UTF-8")
+	}
+	if htmlBody != "" {
+		params.Set("Message.Body.Html.Data", htmlBody)
+		params.Set("Message.Body.Html.Charset", "UTF-8")
+	}
+	if cs := strings.TrimSpace(a.cfg.ConfigurationSet); cs != "" {
+		params.Set("ConfigurationSetName", cs)
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[ses:during-dry-run] send skipped",
+			"to", to,
+			"subject", msg.Subject,
+		)
+		return nil
+	}
+
+	creds := sigv4.Credentials{
+		AccessKey:    firstNonEmpty(secretString(msg.Metadata, "access_key_id"), a.cfg.AccessKey),
+		SecretKey:    firstNonEmpty(secretString(msg.Metadata, "secret_access_key"), a.cfg.SecretKey),
+		SessionToken: firstNonEmpty(secretString(msg.Metadata, "session_token"), a.cfg.SessionToken),
+	}.WithEnvDefaults()
+	if !creds.Valid() {
+		return fmt.Errorf("ses: aws credentials required")
+	}
+	region := firstNonEmpty(strings.TrimSpace(a.cfg.Region), "us-east-1")
+	endpoint := firstNonEmpty(strings.TrimSpace(a.cfg.Endpoint), fmt.Sprintf("https://email.%s.amazonaws.com/", region))
+
+	req, err := sigv4.NewFormRequest(ctx, creds, "ses", region, endpoint, params, time.Now())
+	if err != nil {
+		return fmt.Errorf("ses: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return classifyError(resp, respBody)
+	}
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// classifyError treats SES throttling as retryable even though it arrives
+// as a 400; other client errors such as MessageRejected are permanent.
+func classifyError(resp *http.Response, body []byte) error {
+	statusErr := adapters.HTTPStatusError("ses", resp.StatusCode, body)
+	retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	var payload struct {
+		Error struct {
+			Code string `xml:"Code"`
+		} `xml:"Error"`
+	}
+	if err := xml.Unmarshal(body, &payload); err == nil {
+		switch payload.Error.Code {
+		case "Throttling", "ThrottlingException", "ServiceUnavailable":
+			return adapters.Retryable(statusErr, retryAfter)
+		}
+	}
+	return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, retryAfter)
+}
+
+func setMembers(params url.Values, prefix string, values []string) {
+	for i, value := range values {
+		params.Set(prefix+"."+strconv.Itoa(i+1), value)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func stringSlice(meta map[string]any, key string) []string {
+	if meta == nil {
+		return nil
+	}
+	switch v := meta[key].(type) {
+	case []string:
+		return append([]string(nil), v...)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, entry := range v {
+			if str := strings.TrimSpace(fmt.Sprint(entry)); str != "" {
+				out = append(out, str)
+			}
+		}
+		return out
+	case string:
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			return []string{trimmed}
+		}
+	}
+	return nil
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
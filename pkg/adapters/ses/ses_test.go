@@ -0,0 +1,112 @@
+package ses
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendSignsSendEmailWithSecretCredentials(t *testing.T) {
+	var (
+		form url.Values
+		auth string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		_, _ = io.WriteString(w, `<SendEmailResponse><SendEmailResult><MessageId>abc</MessageId></SendEmailResult></SendEmailResponse>`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{Region: "eu-west-1", From: "noreply@example.com", ConfigurationSet: "tracking", Endpoint: server.URL + "/"}),
+		WithHTTPClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "email",
+		To:      "user@example.com",
+		Subject: "Welcome",
+		Body:    "Hello",
+		Metadata: map[string]any{
+			"html_body": "<p>Hello</p>",
+			"cc":        []string{"a@example.com", "b@example.com"},
+			"secrets": map[string][]byte{
+				"access_key_id":     []byte("AKIDSECRET"),
+				"secret_access_key": []byte("shh"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDSECRET/") || !strings.Contains(auth, "/eu-west-1/ses/aws4_request") {
+		t.Fatalf("unexpected authorization header %q", auth)
+	}
+	expected := map[string]string{
+		"Action":                           "SendEmail",
+		"Source":                           "noreply@example.com",
+		"Destination.ToAddresses.member.1": "user@example.com",
+		"Destination.CcAddresses.member.2": "b@example.com",
+		"Message.Subject.Data":             "Welcome",
+		"Message.Body.Text.Data":           "Hello",
+		"Message.Body.Html.Data":           "<p>Hello</p>",
+		"ConfigurationSetName":             "tracking",
+	}
+	for key, want := range expected {
+		if got := form.Get(key); got != want {
+			t.Fatalf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestSendClassifiesThrottlingAsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Maximum sending rate exceeded.</Message></Error></ErrorResponse>`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{From: "noreply@example.com", AccessKey: "AKID", SecretKey: "secret", Endpoint: server.URL}),
+		WithHTTPClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "email", To: "user@example.com", Body: "hi"})
+	if err == nil || !adapters.IsRetryable(err) {
+		t.Fatalf("expected retryable throttling error, got %v", err)
+	}
+}
+
+func TestSendRejectedMessageIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<ErrorResponse><Error><Type>Sender</Type><Code>MessageRejected</Code><Message>Email address is not verified.</Message></Error></ErrorResponse>`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{From: "noreply@example.com", AccessKey: "AKID", SecretKey: "secret", Endpoint: server.URL}),
+		WithHTTPClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "email", To: "user@example.com", Body: "hi"})
+	if err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected permanent rejection, got %v", err)
+	}
+}
+
+func TestSendRequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	adapter := New(&logger.Nop{}, WithConfig(Config{From: "noreply@example.com"}))
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "email", To: "user@example.com", Body: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "credentials required") {
+		t.Fatalf("expected credentials error, got %v", err)
+	}
+}
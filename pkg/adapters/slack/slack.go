@@ -111,7 +111,11 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		// Slack uses mrkdwn; strip tags to keep content readable
 		text = stripHTML(htmlBody)
 	}
-	if text == "" {
+	blocks, err := blocksFrom(msg.Metadata["blocks"])
+	if err != nil {
+		return adapters.Permanent(err)
+	}
+	if text == "" && len(blocks) == 0 {
 		return fmt.Errorf("slack: message body required")
 	}
 
@@ -120,6 +124,10 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		"text":    text,
 		"mrkdwn":  true,
 	}
+	if len(blocks) > 0 {
+		// text stays as the notification fallback for clients without blocks
+		payload["blocks"] = blocks
+	}
 	if thread := stringValue(msg.Metadata, "thread_ts"); thread != "" {
 		payload["thread_ts"] = thread
 	}
@@ -194,6 +202,42 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	return nil
 }
 
+// blocksFrom accepts Block Kit blocks as decoded JSON ([]any or
+// []map[string]any) or as a JSON array string/bytes.
+func blocksFrom(raw any) ([]any, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, block := range v {
+			out[i] = block
+		}
+		return out, nil
+	case string:
+		return decodeBlocks([]byte(v))
+	case []byte:
+		return decodeBlocks(v)
+	case json.RawMessage:
+		return decodeBlocks(v)
+	default:
+		return nil, fmt.Errorf("slack: unsupported blocks type %T", raw)
+	}
+}
+
+func decodeBlocks(data []byte) ([]any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var blocks []any
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("slack: decode blocks: %w", err)
+	}
+	return blocks, nil
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
 		if strings.TrimSpace(v) != "" {
@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendIncludesBlocksWithTextFallback(t *testing.T) {
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		payloads = append(payloads, payload)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{Token: "xoxb-test", Channel: "#ops", BaseURL: server.URL}),
+		WithClient(server.Client()),
+	)
+	send := func(meta map[string]any) {
+		t.Helper()
+		if err := adapter.Send(context.Background(), adapters.Message{Channel: "chat", Body: "Deploy finished", Metadata: meta}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	send(map[string]any{"blocks": []any{map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": "*Deploy* finished"}}}})
+	send(map[string]any{"blocks": `[{"type":"divider"}]`})
+	send(nil)
+
+	if blocks, _ := payloads[0]["blocks"].([]any); len(blocks) != 1 || payloads[0]["text"] != "Deploy finished" {
+		t.Fatalf("expected blocks with text fallback, got %+v", payloads[0])
+	}
+	if blocks, _ := payloads[1]["blocks"].([]any); len(blocks) != 1 {
+		t.Fatalf("expected blocks decoded from JSON string, got %+v", payloads[1])
+	}
+	if _, ok := payloads[2]["blocks"]; ok || payloads[2]["text"] != "Deploy finished" {
+		t.Fatalf("expected plain text without blocks, got %+v", payloads[2])
+	}
+}
+
+func TestSendRejectsMalformedBlocks(t *testing.T) {
+	adapter := New(&logger.Nop{}, WithConfig(Config{Token: "xoxb-test", Channel: "#ops", DryRun: true}))
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel:  "chat",
+		Body:     "hello",
+		Metadata: map[string]any{"blocks": `{not json`},
+	})
+	if err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected a permanent error for malformed blocks, got %v", err)
+	}
+}
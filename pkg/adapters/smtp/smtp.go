@@ -47,6 +47,7 @@ type Config struct {
 	LocalName    string
 	AuthDisabled bool
 	PlainOnly    bool // Force text/plain even when HTML is available.
+	DryRun       bool // Build the message and log it without dialing the server.
 }
 
 // WithName overrides the provider name (defaults to smtp).
@@ -136,15 +137,13 @@ func (a *Adapter) Name() string { return a.name }
 
 func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
 
-func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
-	if strings.TrimSpace(a.cfg.Host) == "" {
-		return fmt.Errorf("smtp: host is required")
-	}
-	if a.cfg.Port == 0 {
-		a.cfg.Port = 587
-	}
+// SecretKeys lists the secret keys Send reads besides "default".
+func (a *Adapter) SecretKeys() []string {
+	return []string{"username", "password", "from"}
+}
 
-	from := firstNonEmpty(msg.Metadata, "from", a.cfg.From)
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	from := coalesce(firstNonEmpty(msg.Metadata, "from"), secretString(msg.Metadata, "from"), a.cfg.From)
 	if from == "" {
 		return fmt.Errorf("smtp: from address is required")
 	}
@@ -168,7 +167,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		return fmt.Errorf("smtp: invalid subject: %w", err)
 	}
 
-	replyToRaw := firstNonEmpty(msg.Metadata, "reply_to", a.cfg.ReplyTo)
+	replyToRaw := coalesce(firstNonEmpty(msg.Metadata, "reply_to"), a.cfg.ReplyTo)
 	var replyTo *mail.Address
 	if strings.TrimSpace(replyToRaw) != "" {
 		if err := ensureNoCRLF(replyToRaw); err != nil {
@@ -189,7 +188,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		return fmt.Errorf("smtp: invalid bcc: %w", err)
 	}
 
-	textBody := firstNonEmpty(msg.Metadata, "text_body", "text", "body", msg.Body)
+	textBody := coalesce(firstNonEmpty(msg.Metadata, "text_body", "text", "body"), msg.Body)
 	htmlBody := firstNonEmpty(msg.Metadata, "html_body", "html")
 	if htmlBody != "" && strings.TrimSpace(textBody) == "" {
 		textBody = htmlToText(htmlBody)
@@ -219,7 +218,32 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		return err
 	}
 
-	addr := fmt.Sprintf("%s:%d", a.cfg.Host, a.cfg.Port)
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[smtp:during-dry-run] send skipped",
+			"to", toAddr.Address,
+			"channel", msg.Channel,
+			"subject", subject,
+			"bytes", len(messageBytes),
+		)
+		return nil
+	}
+
+	if strings.TrimSpace(a.cfg.Host) == "" {
+		return fmt.Errorf("smtp: host is required")
+	}
+	port := a.cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	username := coalesce(secretString(msg.Metadata, "username"), a.cfg.Username)
+	password := coalesce(
+		secretString(msg.Metadata, "password"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.Password,
+	)
+
+	addr := fmt.Sprintf("%s:%d", a.cfg.Host, port)
 	dialer := &net.Dialer{Timeout: a.cfg.Timeout}
 	tlsCfg := &tls.Config{ServerName: a.cfg.Host}
 	if a.cfg.TLSPolicy == adapters.TLSPolicyInsecureSkipVerify {
@@ -249,8 +273,8 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		}
 	}
 
-	if !a.cfg.AuthDisabled && a.cfg.Username != "" {
-		auth := gosmtp.PlainAuth("", a.cfg.Username, a.cfg.Password, a.cfg.Host)
+	if !a.cfg.AuthDisabled && username != "" {
+		auth := gosmtp.PlainAuth("", username, password, a.cfg.Host)
 		if err := client.Auth(auth); err != nil {
 			return fmt.Errorf("smtp: auth failed: %w", err)
 		}
@@ -605,6 +629,17 @@ func encodeBase64Lines(data []byte) string {
 	return sb.String()
 }
 
+// coalesce returns the first value that is not blank. Unlike firstNonEmpty,
+// plain strings are treated as values rather than metadata keys.
+func coalesce(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 func firstNonEmpty(meta map[string]any, keys ...any) string {
 	for _, key := range keys {
 		switch v := key.(type) {
@@ -649,6 +684,32 @@ func stringValue(meta map[string]any, key string) string {
 	}
 }
 
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
+
 func stringSlice(meta map[string]any, key string) []string {
 	if meta == nil {
 		return nil
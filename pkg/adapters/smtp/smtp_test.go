@@ -1,11 +1,13 @@
 package smtp
 
 import (
+	"context"
 	"net/mail"
 	"strings"
 	"testing"
 
 	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
 )
 
 func mustParseAddress(t *testing.T, value string) *mail.Address {
@@ -122,3 +124,23 @@ func TestParseAddressListRejectsCRLF(t *testing.T) {
 		t.Fatalf("expected CRLF address rejection")
 	}
 }
+
+func TestSendDryRunUsesConfiguredAndSecretSender(t *testing.T) {
+	ctx := context.Background()
+	msg := adapters.Message{Channel: "email", To: "to@example.com", Subject: "Hi", Body: "hello"}
+
+	a := New(&logger.Nop{}, WithConfig(Config{DryRun: true}), WithFrom("no-reply@example.com"))
+	if err := a.Send(ctx, msg); err != nil {
+		t.Fatalf("expected dry-run with configured from to succeed, got %v", err)
+	}
+
+	bare := New(&logger.Nop{}, WithConfig(Config{DryRun: true}))
+	if err := bare.Send(ctx, msg); err == nil {
+		t.Fatalf("expected missing from to fail even in dry-run")
+	}
+
+	msg.Metadata = map[string]any{"secrets": map[string][]byte{"from": []byte("ops@example.com")}}
+	if err := bare.Send(ctx, msg); err != nil {
+		t.Fatalf("expected from resolved from secrets, got %v", err)
+	}
+}
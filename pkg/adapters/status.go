@@ -0,0 +1,29 @@
+package adapters
+
+import "context"
+
+// Delivery statuses reported asynchronously by providers after Send returned.
+const (
+	DeliveryStatusDelivered   = "delivered"
+	DeliveryStatusFailed      = "failed"
+	DeliveryStatusUndelivered = "undelivered"
+)
+
+// DeliveryStatusUpdate is a provider delivery report for a message that was
+// already handed off, such as a Twilio StatusCallback.
+type DeliveryStatusUpdate struct {
+	// MessageID is the notification message ID sent as Message.ID.
+	MessageID         string
+	Provider          string
+	ProviderMessageID string
+	// Status is one of the DeliveryStatus constants; other provider states
+	// (queued, sent) are recorded without changing the message status.
+	Status string
+	Error  string
+}
+
+// DeliveryStatusUpdater applies asynchronous delivery reports to stored
+// messages. Web handlers receiving provider callbacks call it.
+type DeliveryStatusUpdater interface {
+	UpdateDeliveryStatus(ctx context.Context, update DeliveryStatusUpdate) error
+}
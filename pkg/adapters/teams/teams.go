@@ -0,0 +1,381 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+// Card formats supported by Teams incoming webhooks.
+const (
+	FormatAdaptiveCard = "adaptive"
+	FormatMessageCard  = "messagecard"
+)
+
+// Adapter posts cards to a Microsoft Teams incoming webhook.
+type Adapter struct {
+	name   string
+	base   adapters.BaseAdapter
+	caps   adapters.Capability
+	cfg    Config
+	client *http.Client
+}
+
+// Config holds Teams webhook settings. WebhookURL is used when the resolved
+// secrets do not carry one.
+type Config struct {
+	WebhookURL string
+	Format     string // adaptive (default) or messagecard
+	ThemeColor string // MessageCard accent, e.g. "0076D7"
+	Timeout    time.Duration
+	Transport  adapters.HTTPTransportConfig
+	DryRun     bool
+}
+
+type Option func(*Adapter)
+
+// WithName overrides the adapter provider name.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		if strings.TrimSpace(name) != "" {
+			a.name = name
+		}
+	}
+}
+
+// WithConfig sets adapter configuration.
+func WithConfig(cfg Config) Option {
+	return func(a *Adapter) {
+		a.cfg = cfg
+	}
+}
+
+// WithClient sets a custom HTTP client.
+func WithClient(c *http.Client) Option {
+	return func(a *Adapter) {
+		if c != nil {
+			a.client = c
+		}
+	}
+}
+
+// New constructs the Teams adapter.
+func New(l logger.Logger, opts ...Option) *Adapter {
+	adapter := &Adapter{
+		name: "teams",
+		base: adapters.NewBaseAdapter(l),
+		caps: adapters.Capability{
+			Name:     "teams",
+			Channels: []string{"chat", "teams"},
+			Formats:  []string{"text/plain", "text/html"},
+		},
+		cfg: Config{
+			Format:  FormatAdaptiveCard,
+			Timeout: 10 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	if adapter.client == nil {
+		adapter.client = adapters.NewHTTPClient(adapter.cfg.Timeout, adapter.cfg.Transport)
+	}
+	return adapter
+}
+
+func (a *Adapter) Name() string { return a.name }
+
+func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
+
+func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
+	webhookURL := strings.TrimSpace(firstNonEmpty(
+		secretString(msg.Metadata, "webhook_url"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.WebhookURL,
+	))
+	if webhookURL == "" && !a.cfg.DryRun {
+		return fmt.Errorf("teams: webhook url required")
+	}
+
+	title := strings.TrimSpace(msg.Subject)
+	text := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body)
+	if htmlBody := stringValue(msg.Metadata, "html_body"); htmlBody != "" && strings.TrimSpace(text) == "" {
+		text = stripHTML(htmlBody)
+	}
+	if strings.TrimSpace(text) == "" && title == "" {
+		return fmt.Errorf("teams: message body required")
+	}
+	card := cardInput{
+		Title:     title,
+		Text:      text,
+		Facts:     factsFrom(msg.Metadata["facts"]),
+		ActionURL: stringValue(msg.Metadata, "action_url"),
+	}
+
+	var payload map[string]any
+	format := strings.ToLower(strings.TrimSpace(firstNonEmpty(stringValue(msg.Metadata, "card_format"), a.cfg.Format)))
+	switch format {
+	case FormatMessageCard:
+		payload = messageCard(card, a.cfg.ThemeColor)
+	case "", FormatAdaptiveCard:
+		payload = adaptiveCard(card)
+	default:
+		return fmt.Errorf("teams: unsupported card format %q", format)
+	}
+
+	if a.cfg.DryRun {
+		a.base.LogSuccess(a.name, msg)
+		a.base.Logger().Info("[teams:during-dry-run] send skipped",
+			"title", title,
+			"facts", len(card.Facts),
+		)
+		return nil
+	}
+
+	bodyBytes, err := adapters.EncodeJSONPayload("teams", payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("teams: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("teams: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return adapters.ClassifyHTTPStatus(adapters.HTTPStatusError("teams", resp.StatusCode, data), resp.StatusCode, retryAfter)
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// Fact is a label/value pair rendered in the card's fact set.
+type Fact struct {
+	Title string
+	Value string
+}
+
+type cardInput struct {
+	Title     string
+	Text      string
+	Facts     []Fact
+	ActionURL string
+}
+
+func adaptiveCard(in cardInput) map[string]any {
+	body := []map[string]any{}
+	if in.Title != "" {
+		body = append(body, map[string]any{
+			"type":   "TextBlock",
+			"text":   in.Title,
+			"weight": "Bolder",
+			"size":   "Medium",
+			"wrap":   true,
+		})
+	}
+	if in.Text != "" {
+		body = append(body, map[string]any{
+			"type": "TextBlock",
+			"text": in.Text,
+			"wrap": true,
+		})
+	}
+	if len(in.Facts) > 0 {
+		facts := make([]map[string]string, 0, len(in.Facts))
+		for _, fact := range in.Facts {
+			facts = append(facts, map[string]string{"title": fact.Title, "value": fact.Value})
+		}
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+	content := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if in.ActionURL != "" {
+		content["actions"] = []map[string]any{
+			{"type": "Action.OpenUrl", "title": "Open", "url": in.ActionURL},
+		}
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     content,
+			},
+		},
+	}
+}
+
+func messageCard(in cardInput, themeColor string) map[string]any {
+	summary := firstNonEmpty(in.Title, in.Text)
+	card := map[string]any{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  summary,
+		"text":     in.Text,
+	}
+	if in.Title != "" {
+		card["title"] = in.Title
+	}
+	if themeColor != "" {
+		card["themeColor"] = themeColor
+	}
+	if len(in.Facts) > 0 {
+		facts := make([]map[string]string, 0, len(in.Facts))
+		for _, fact := range in.Facts {
+			facts = append(facts, map[string]string{"name": fact.Title, "value": fact.Value})
+		}
+		card["sections"] = []map[string]any{{"facts": facts}}
+	}
+	if in.ActionURL != "" {
+		card["potentialAction"] = []map[string]any{
+			{
+				"@type":   "OpenUri",
+				"name":    "Open",
+				"targets": []map[string]string{{"os": "default", "uri": in.ActionURL}},
+			},
+		}
+	}
+	return card
+}
+
+// factsFrom accepts either a map (rendered in key order) or a list of
+// {"title","value"} entries, which keeps the caller's order.
+func factsFrom(raw any) []Fact {
+	switch v := raw.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out := make([]Fact, 0, len(keys))
+		for _, key := range keys {
+			out = append(out, Fact{Title: key, Value: fmt.Sprint(v[key])})
+		}
+		return out
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out := make([]Fact, 0, len(keys))
+		for _, key := range keys {
+			out = append(out, Fact{Title: key, Value: v[key]})
+		}
+		return out
+	case []Fact:
+		return append([]Fact(nil), v...)
+	case []any:
+		out := make([]Fact, 0, len(v))
+		for _, entry := range v {
+			if fact, ok := entry.(map[string]any); ok {
+				title := stringValue(fact, "title")
+				if title == "" {
+					title = stringValue(fact, "name")
+				}
+				if title == "" {
+					continue
+				}
+				out = append(out, Fact{Title: title, Value: stringValue(fact, "value")})
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringValue(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta[key]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func stripHTML(html string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch r {
+		case '<':
+			inTag = true
+		case '>':
+			inTag = false
+		default:
+			if !inTag {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
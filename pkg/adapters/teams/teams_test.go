@@ -0,0 +1,105 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendPostsAdaptiveCardToSecretWebhook(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithClient(server.Client()))
+	if adapter.Name() != "teams" {
+		t.Fatalf("expected provider name teams, got %q", adapter.Name())
+	}
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "chat",
+		Subject: "Build failed",
+		Body:    "main is red",
+		Metadata: map[string]any{
+			"facts":      map[string]any{"Commit": "abc123", "Author": "dev"},
+			"action_url": "https://ci.example.com/builds/1",
+			"secrets":    map[string][]byte{"webhook_url": []byte(server.URL)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	attachments, _ := payload["attachments"].([]any)
+	if len(attachments) != 1 {
+		t.Fatalf("expected one card attachment, got %+v", payload)
+	}
+	content, _ := attachments[0].(map[string]any)["content"].(map[string]any)
+	body, _ := content["body"].([]any)
+	if len(body) != 3 {
+		t.Fatalf("expected title, text, and facts blocks, got %+v", body)
+	}
+	if title, _ := body[0].(map[string]any)["text"].(string); title != "Build failed" {
+		t.Fatalf("expected subject as card title, got %q", title)
+	}
+	facts, _ := body[2].(map[string]any)["facts"].([]any)
+	first, _ := facts[0].(map[string]any)
+	if len(facts) != 2 || first["title"] != "Author" || first["value"] != "dev" {
+		t.Fatalf("expected facts sorted by title, got %+v", facts)
+	}
+	if actions, _ := content["actions"].([]any); len(actions) != 1 {
+		t.Fatalf("expected open-url action, got %+v", content["actions"])
+	}
+}
+
+func TestSendMessageCardAndErrors(t *testing.T) {
+	var payload map[string]any
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{WebhookURL: server.URL, Format: FormatMessageCard}),
+		WithClient(server.Client()),
+	)
+	msg := adapters.Message{
+		Channel:  "chat",
+		Subject:  "Deploy",
+		Body:     "done",
+		Metadata: map[string]any{"facts": []any{map[string]any{"title": "Env", "value": "prod"}}},
+	}
+	if err := adapter.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if payload["@type"] != "MessageCard" || payload["title"] != "Deploy" {
+		t.Fatalf("expected MessageCard payload, got %+v", payload)
+	}
+
+	status = http.StatusBadRequest
+	if err := adapter.Send(context.Background(), msg); err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected permanent error for 400, got %v", err)
+	}
+	status = http.StatusTooManyRequests
+	if err := adapter.Send(context.Background(), msg); err == nil || !adapters.IsRetryable(err) {
+		t.Fatalf("expected retryable error for 429, got %v", err)
+	}
+}
+
+func TestSendRequiresWebhookURL(t *testing.T) {
+	adapter := New(&logger.Nop{})
+	if err := adapter.Send(context.Background(), adapters.Message{Channel: "chat", Body: "hi"}); err == nil {
+		t.Fatalf("expected missing webhook url error")
+	}
+}
@@ -0,0 +1,103 @@
+package twilio
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+)
+
+// ErrInvalidCallback is returned when a status callback is missing the
+// message SID or status.
+var ErrInvalidCallback = errors.New("twilio: invalid status callback")
+
+// ParseStatusCallback reads the MessageSid and MessageStatus fields Twilio
+// posts to a StatusCallback URL.
+func ParseStatusCallback(r *http.Request) (string, string, error) {
+	if r == nil {
+		return "", "", ErrInvalidCallback
+	}
+	if err := r.ParseForm(); err != nil {
+		return "", "", errors.Join(ErrInvalidCallback, err)
+	}
+	sid := strings.TrimSpace(r.PostForm.Get("MessageSid"))
+	status := strings.ToLower(strings.TrimSpace(firstNonEmpty(r.PostForm.Get("MessageStatus"), r.PostForm.Get("SmsStatus"))))
+	if sid == "" || status == "" {
+		return "", "", ErrInvalidCallback
+	}
+	return sid, status, nil
+}
+
+// StatusUpdate converts a parsed callback into a delivery status update. The
+// message ID comes from the message_id query parameter Send appends to the
+// callback URL; Twilio's failed and undelivered states keep the ErrorCode.
+func StatusUpdate(r *http.Request) (adapters.DeliveryStatusUpdate, error) {
+	sid, status, err := ParseStatusCallback(r)
+	if err != nil {
+		return adapters.DeliveryStatusUpdate{}, err
+	}
+	update := adapters.DeliveryStatusUpdate{
+		MessageID:         strings.TrimSpace(r.URL.Query().Get("message_id")),
+		Provider:          "twilio",
+		ProviderMessageID: sid,
+		Status:            status,
+	}
+	if code := strings.TrimSpace(r.PostForm.Get("ErrorCode")); code != "" {
+		update.Error = "twilio error " + code
+	}
+	return update, nil
+}
+
+// ValidateSignature checks the X-Twilio-Signature header against the public
+// callback URL Twilio called and the posted form, using the account auth
+// token. Handlers should reject callbacks that fail validation.
+func ValidateSignature(r *http.Request, authToken, callbackURL string) bool {
+	if r == nil || authToken == "" {
+		return false
+	}
+	signature := r.Header.Get("X-Twilio-Signature")
+	if signature == "" {
+		return false
+	}
+	if err := r.ParseForm(); err != nil {
+		return false
+	}
+	var payload strings.Builder
+	payload.WriteString(callbackURL)
+	keys := make([]string, 0, len(r.PostForm))
+	for key := range r.PostForm {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		for _, value := range r.PostForm[key] {
+			payload.WriteString(key)
+			payload.WriteString(value)
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(payload.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+func statusCallbackURL(callback, messageID string) string {
+	if messageID == "" {
+		return callback
+	}
+	parsed, err := url.Parse(callback)
+	if err != nil {
+		return callback
+	}
+	query := parsed.Query()
+	query.Set("message_id", messageID)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,103 @@
+package twilio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendSetsStatusCallbackWithMessageID(t *testing.T) {
+	var gotForm url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotForm = r.Form
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{
+		AccountSID:     "AC123",
+		AuthToken:      "token",
+		From:           "+15551234567",
+		APIBaseURL:     server.URL,
+		StatusCallback: "https://app.example.com/hooks/twilio?tenant=acme",
+	}))
+	err := adapter.Send(context.Background(), adapters.Message{
+		ID:      "msg-1",
+		Channel: "sms",
+		To:      "+15557654321",
+		Body:    "hello",
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	callback, err := url.Parse(gotForm.Get("StatusCallback"))
+	if err != nil {
+		t.Fatalf("parse callback: %v", err)
+	}
+	if callback.Query().Get("message_id") != "msg-1" || callback.Query().Get("tenant") != "acme" {
+		t.Fatalf("expected message id appended to callback, got %s", callback)
+	}
+}
+
+func TestStatusUpdateFromCallback(t *testing.T) {
+	form := url.Values{
+		"MessageSid":    {"SM123"},
+		"MessageStatus": {"undelivered"},
+		"ErrorCode":     {"30003"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/hooks/twilio?message_id=msg-1", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	update, err := StatusUpdate(req)
+	if err != nil {
+		t.Fatalf("status update: %v", err)
+	}
+	if update.MessageID != "msg-1" || update.ProviderMessageID != "SM123" || update.Status != adapters.DeliveryStatusUndelivered {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	if update.Error != "twilio error 30003" {
+		t.Fatalf("expected error code carried, got %q", update.Error)
+	}
+
+	empty := httptest.NewRequest(http.MethodPost, "/hooks/twilio", strings.NewReader(""))
+	empty.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, _, err := ParseStatusCallback(empty); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("expected ErrInvalidCallback, got %v", err)
+	}
+}
+
+func TestValidateSignature(t *testing.T) {
+	const callbackURL = "https://app.example.com/hooks/twilio?message_id=msg-1"
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+	mac := hmac.New(sha1.New, []byte("token"))
+	mac.Write([]byte(callbackURL + "MessageSidSM123MessageStatusdelivered"))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	newRequest := func(body url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/twilio?message_id=msg-1", strings.NewReader(body.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", signature)
+		return req
+	}
+	if !ValidateSignature(newRequest(form), "token", callbackURL) {
+		t.Fatalf("expected signature to validate")
+	}
+	tampered := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"failed"}}
+	if ValidateSignature(newRequest(tampered), "token", callbackURL) {
+		t.Fatalf("expected tampered form to fail validation")
+	}
+	if ValidateSignature(newRequest(form), "other", callbackURL) {
+		t.Fatalf("expected wrong auth token to fail validation")
+	}
+}
@@ -35,6 +35,9 @@ type Config struct {
 	Transport           adapters.HTTPTransportConfig
 	PlainOnly           bool // Force text/plain when HTML is provided.
 	DryRun              bool // When true, validates and logs but does not send.
+	// StatusCallback is the URL Twilio posts delivery status updates to. The
+	// message ID is appended as the message_id query parameter.
+	StatusCallback string
 }
 
 func WithName(name string) Option {
@@ -140,6 +143,9 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		}
 	}
 	form.Set("Body", body)
+	if callback := firstNonEmpty(stringValue(msg.Metadata, "status_callback"), a.cfg.StatusCallback); callback != "" {
+		form.Set("StatusCallback", statusCallbackURL(callback, msg.ID))
+	}
 
 	media := stringSlice(msg.Metadata, "media_urls")
 	if attURLs := adapters.AttachmentURLs(msg.Attachments); len(attURLs) > 0 {
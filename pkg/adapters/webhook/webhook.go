@@ -3,8 +3,12 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -34,8 +38,13 @@ type Config struct {
 	DryRun          bool
 	ForwardMetadata bool // include msg.Metadata in payload
 	ForwardHeaders  bool // include msg.Headers in payload
+	SigningSecret   string
+	SignatureHeader string // defaults to X-Signature
 }
 
+// DefaultSignatureHeader carries the hex HMAC-SHA256 of the request body.
+const DefaultSignatureHeader = "X-Signature"
+
 type Option func(*Adapter)
 
 // WithName overrides the adapter name.
@@ -106,7 +115,8 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		)
 		return nil
 	}
-	if strings.TrimSpace(a.cfg.URL) == "" {
+	target := firstNonEmpty(stringValue(msg.Metadata, "url"), a.cfg.URL)
+	if strings.TrimSpace(target) == "" {
 		return fmt.Errorf("webhook: url is required")
 	}
 	contentType := "application/json"
@@ -119,7 +129,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		"html":    html,
 	}
 	if a.cfg.ForwardMetadata {
-		payload["metadata"] = msg.Metadata
+		payload["metadata"] = forwardedMetadata(msg.Metadata)
 	}
 	if a.cfg.ForwardHeaders {
 		payload["headers"] = msg.Headers
@@ -129,7 +139,7 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(a.cfg.Method), a.cfg.URL, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(a.cfg.Method), target, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return fmt.Errorf("webhook: build request: %w", err)
 	}
@@ -143,10 +153,20 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	if a.cfg.BasicAuthUser != "" {
 		req.SetBasicAuth(a.cfg.BasicAuthUser, a.cfg.BasicAuthPass)
 	}
+	secret := firstNonEmpty(
+		secretString(msg.Metadata, "signing_secret"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.SigningSecret,
+	)
+	if secret != "" {
+		header := firstNonEmpty(a.cfg.SignatureHeader, DefaultSignatureHeader)
+		req.Header.Set(header, Sign(secret, bodyBytes))
+	}
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("webhook: request failed: %w", err)
+		// Timeouts and connection failures are transient.
+		return adapters.Retryable(fmt.Errorf("webhook: request failed: %w", err), 0)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -154,13 +174,43 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return adapters.HTTPStatusError("webhook", resp.StatusCode, respBody)
+		retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return adapters.ClassifyHTTPStatus(adapters.HTTPStatusError("webhook", resp.StatusCode, respBody), resp.StatusCode, retryAfter)
 	}
 
 	a.base.LogSuccess(a.name, msg)
 	return nil
 }
 
+// Sign returns the hex encoded HMAC-SHA256 of body. Receivers recompute it
+// over the raw request body and compare with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body under secret.
+func Verify(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// forwardedMetadata drops resolved secrets so they never leave the process.
+func forwardedMetadata(meta map[string]any) map[string]any {
+	if meta == nil {
+		return nil
+	}
+	out := maps.Clone(meta)
+	delete(out, "secrets")
+	return out
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
 		if strings.TrimSpace(v) != "" {
@@ -185,3 +235,29 @@ func stringValue(meta map[string]any, key string) string {
 		return strings.TrimSpace(fmt.Sprint(v))
 	}
 }
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
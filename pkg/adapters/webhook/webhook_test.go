@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendSignsBodyAndUsesMetadataURL(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotSignature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(DefaultSignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{},
+		WithConfig(Config{Method: http.MethodPost, ForwardMetadata: true, URL: "http://unused.invalid"}),
+		WithClient(server.Client()),
+	)
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "webhook",
+		Subject: "Deploy finished",
+		Body:    "ok",
+		Metadata: map[string]any{
+			"url":     server.URL,
+			"build":   "1234",
+			"secrets": map[string][]byte{"signing_secret": []byte("s3cr3t")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !Verify("s3cr3t", gotBody, gotSignature) {
+		t.Fatalf("signature %q does not match body %s", gotSignature, gotBody)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	meta, _ := payload["metadata"].(map[string]any)
+	if meta["build"] != "1234" {
+		t.Fatalf("expected metadata forwarded, got %+v", payload)
+	}
+	if _, leaked := meta["secrets"]; leaked {
+		t.Fatalf("secrets must not be forwarded: %+v", meta)
+	}
+}
+
+func TestSendClassifiesStatus(t *testing.T) {
+	cases := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusGone, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusBadGateway, true},
+	}
+	for _, tc := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(tc.status)
+		}))
+		adapter := New(&logger.Nop{}, WithConfig(Config{Method: http.MethodPost, URL: server.URL}), WithClient(server.Client()))
+		err := adapter.Send(context.Background(), adapters.Message{Channel: "webhook", Body: "hello"})
+		server.Close()
+		if err == nil {
+			t.Fatalf("status %d: expected error", tc.status)
+		}
+		if got := adapters.IsRetryable(err); got != tc.retryable {
+			t.Fatalf("status %d: expected retryable=%v, got %v", tc.status, tc.retryable, got)
+		}
+		if after, ok := adapters.RetryAfter(err); tc.retryable && (!ok || after != 2*time.Second) {
+			t.Fatalf("status %d: expected retry-after 2s, got %v", tc.status, after)
+		}
+	}
+}
+
+func TestSendTimeoutIsRetryable(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := server.Client()
+	client.Timeout = 20 * time.Millisecond
+	adapter := New(&logger.Nop{}, WithConfig(Config{Method: http.MethodPost, URL: server.URL}), WithClient(client))
+	err := adapter.Send(context.Background(), adapters.Message{Channel: "webhook", Body: "hello"})
+	if err == nil || !adapters.IsRetryable(err) {
+		t.Fatalf("expected retryable timeout error, got %v", err)
+	}
+}
@@ -3,8 +3,10 @@ package whatsapp
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -86,7 +88,13 @@ func (a *Adapter) Name() string { return a.name }
 func (a *Adapter) Capabilities() adapters.Capability { return a.caps }
 
 func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
-	if strings.TrimSpace(a.cfg.Token) == "" || strings.TrimSpace(a.cfg.PhoneNumberID) == "" {
+	token := strings.TrimSpace(firstNonEmpty(
+		secretString(msg.Metadata, "token"),
+		secretString(msg.Metadata, "default"),
+		a.cfg.Token,
+	))
+	phoneNumberID := strings.TrimSpace(firstNonEmpty(stringValue(msg.Metadata, "phone_number_id"), a.cfg.PhoneNumberID))
+	if token == "" || phoneNumberID == "" {
 		return fmt.Errorf("whatsapp: token and phone number id required")
 	}
 	to := strings.TrimSpace(msg.To)
@@ -94,6 +102,55 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		return fmt.Errorf("whatsapp: destination required")
 	}
 
+	payload, err := a.buildPayload(msg, to)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := adapters.EncodeJSONPayload("whatsapp", payload)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/%s/messages", strings.TrimRight(a.cfg.APIBase, "/"), phoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("whatsapp: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("whatsapp: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return classifyError(resp, respBody)
+	}
+
+	a.base.LogSuccess(a.name, msg)
+	return nil
+}
+
+// buildPayload prefers a structured template message when the metadata
+// carries one, then a document when an attachment URL is present, and
+// falls back to a freeform text message.
+func (a *Adapter) buildPayload(msg adapters.Message, to string) (map[string]any, error) {
+	payload := map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                to,
+	}
+	if tpl, ok, err := templateFrom(msg.Metadata); err != nil {
+		return nil, err
+	} else if ok {
+		payload["type"] = "template"
+		payload["template"] = tpl
+		return payload, nil
+	}
+
 	textBody := firstNonEmpty(stringValue(msg.Metadata, "body"), msg.Body)
 	htmlBody := firstNonEmpty(stringValue(msg.Metadata, "html_body"))
 	if htmlBody != "" && !a.cfg.PlainOnly {
@@ -102,10 +159,9 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 	attachments := adapters.NormalizeAttachments(msg.Attachments)
 	attachment := firstURLAttachment(attachments)
 	if attachment == nil && textBody == "" {
-		return fmt.Errorf("whatsapp: body required")
+		return nil, fmt.Errorf("whatsapp: body required")
 	}
 
-	var payload map[string]any
 	if attachment != nil {
 		doc := map[string]any{
 			"link": attachment.URL,
@@ -116,54 +172,107 @@ func (a *Adapter) Send(ctx context.Context, msg adapters.Message) error {
 		if textBody != "" {
 			doc["caption"] = textBody
 		}
-		payload = map[string]any{
-			"messaging_product": "whatsapp",
-			"to":                to,
-			"type":              "document",
-			"document":          doc,
-		}
-	} else {
-		payload = map[string]any{
-			"messaging_product": "whatsapp",
-			"to":                to,
-			"type":              "text",
-			"text": map[string]any{
-				"body": textBody,
-			},
+		payload["type"] = "document"
+		payload["document"] = doc
+		return payload, nil
+	}
+
+	text := map[string]any{
+		"body": textBody,
+	}
+	if preview := boolValue(msg.Metadata, "preview_url"); preview {
+		text["preview_url"] = true
+	}
+	payload["type"] = "text"
+	payload["text"] = text
+	return payload, nil
+}
+
+// templateFrom reads Metadata["template"] as {name, language, components},
+// where language is a code string or {"code": ...}. Flat template_name,
+// template_language and template_components keys are accepted too.
+func templateFrom(meta map[string]any) (map[string]any, bool, error) {
+	var raw map[string]any
+	switch v := meta["template"].(type) {
+	case map[string]any:
+		raw = maps.Clone(v)
+	case string:
+		if strings.TrimSpace(v) != "" {
+			raw = map[string]any{"name": v}
 		}
-		if preview := boolValue(msg.Metadata, "preview_url"); preview {
-			if text, ok := payload["text"].(map[string]any); ok {
-				text["preview_url"] = true
-			}
+	}
+	if raw == nil && stringValue(meta, "template_name") != "" {
+		raw = map[string]any{
+			"name":       stringValue(meta, "template_name"),
+			"language":   meta["template_language"],
+			"components": meta["template_components"],
 		}
 	}
-
-	bodyBytes, err := adapters.EncodeJSONPayload("whatsapp", payload)
-	if err != nil {
-		return err
+	if raw == nil {
+		return nil, false, nil
 	}
-	endpoint := fmt.Sprintf("%s/%s/messages", strings.TrimRight(a.cfg.APIBase, "/"), strings.TrimSpace(a.cfg.PhoneNumberID))
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return fmt.Errorf("whatsapp: build request: %w", err)
+	if raw["language"] == nil && meta["template_language"] != nil {
+		raw["language"] = meta["template_language"]
+	}
+	if raw["components"] == nil && meta["template_components"] != nil {
+		raw["components"] = meta["template_components"]
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(a.cfg.Token))
 
-	resp, err := a.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("whatsapp: request failed: %w", err)
+	name := stringValue(raw, "name")
+	if name == "" {
+		return nil, false, adapters.Permanent(fmt.Errorf("whatsapp: template name required"))
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return adapters.HTTPStatusError("whatsapp", resp.StatusCode, respBody)
+	language := ""
+	switch v := raw["language"].(type) {
+	case string:
+		language = strings.TrimSpace(v)
+	case map[string]any:
+		language = stringValue(v, "code")
+	}
+	if language == "" {
+		return nil, false, adapters.Permanent(fmt.Errorf("whatsapp: template %s language required", name))
+	}
+	tpl := map[string]any{
+		"name":     name,
+		"language": map[string]any{"code": language},
+	}
+	switch components := raw["components"].(type) {
+	case nil:
+	case []any, []map[string]any:
+		tpl["components"] = components
+	default:
+		return nil, false, adapters.Permanent(fmt.Errorf("whatsapp: template components must be a list, got %T", components))
 	}
+	return tpl, true, nil
+}
 
-	a.base.LogSuccess(a.name, msg)
-	return nil
+// classifyError maps Cloud API error codes: throttling and temporary
+// outages are retryable, while auth, parameter, template and recipient
+// errors (such as the 24-hour window closing) are permanent.
+func classifyError(resp *http.Response, body []byte) error {
+	statusErr := adapters.HTTPStatusError("whatsapp", resp.StatusCode, body)
+	retryAfter := adapters.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	var payload struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error.Code == 0 {
+		return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, retryAfter)
+	}
+	code := payload.Error.Code
+	switch {
+	case code == 1, code == 2, code == 4, code == 80007,
+		code == 130429, code == 131000, code == 131016, code == 131048, code == 131056, code == 133004:
+		return adapters.Retryable(statusErr, retryAfter)
+	case code == 10, code == 100, code == 190, code == 200, code == 368,
+		code >= 131008 && code <= 131009, code == 131021, code == 131026, code == 131030, code == 131031,
+		code == 131045, code == 131047, code == 131051, code == 131052, code == 131053,
+		code >= 132000 && code <= 132069:
+		return adapters.Permanent(statusErr)
+	default:
+		return adapters.ClassifyHTTPStatus(statusErr, resp.StatusCode, retryAfter)
+	}
 }
 
 func firstNonEmpty(values ...string) string {
@@ -237,3 +346,29 @@ func firstURLAttachment(attachments []adapters.Attachment) *adapters.Attachment
 	}
 	return nil
 }
+
+func secretString(meta map[string]any, key string) string {
+	if meta == nil {
+		return ""
+	}
+	raw, ok := meta["secrets"]
+	if !ok {
+		return ""
+	}
+	switch v := raw.(type) {
+	case map[string][]byte:
+		if val, ok := v[key]; ok {
+			return strings.TrimSpace(string(val))
+		}
+	case map[string]any:
+		if val, ok := v[key]; ok {
+			switch data := val.(type) {
+			case string:
+				return strings.TrimSpace(data)
+			case []byte:
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+)
+
+func TestSendTemplateMessageWithSecretToken(t *testing.T) {
+	var (
+		payload map[string]any
+		auth    string
+		path    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_, _ = io.WriteString(w, `{"messages":[{"id":"wamid.1"}]}`)
+	}))
+	defer server.Close()
+
+	adapter := New(&logger.Nop{}, WithConfig(Config{PhoneNumberID: "123", APIBase: server.URL}), WithClient(server.Client()))
+	err := adapter.Send(context.Background(), adapters.Message{
+		Channel: "whatsapp",
+		To:      "15551234567",
+		Metadata: map[string]any{
+			"template": map[string]any{
+				"name":     "order_shipped",
+				"language": "en_US",
+				"components": []any{
+					map[string]any{"type": "body", "parameters": []any{map[string]any{"type": "text", "text": "A-42"}}},
+				},
+			},
+			"secrets": map[string][]byte{"token": []byte("meta-token")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if auth != "Bearer meta-token" || path != "/123/messages" {
+		t.Fatalf("unexpected auth %q or path %q", auth, path)
+	}
+	if payload["type"] != "template" {
+		t.Fatalf("expected template message, got %+v", payload)
+	}
+	tpl, _ := payload["template"].(map[string]any)
+	language, _ := tpl["language"].(map[string]any)
+	if tpl["name"] != "order_shipped" || language["code"] != "en_US" {
+		t.Fatalf("unexpected template %+v", tpl)
+	}
+	if components, _ := tpl["components"].([]any); len(components) != 1 {
+		t.Fatalf("expected components passed through, got %+v", tpl["components"])
+	}
+}
+
+func TestSendTemplateRequiresLanguage(t *testing.T) {
+	adapter := New(&logger.Nop{}, WithConfig(Config{Token: "t", PhoneNumberID: "123"}))
+	err := adapter.Send(context.Background(), adapters.Message{
+		To:       "15551234567",
+		Metadata: map[string]any{"template_name": "order_shipped"},
+	})
+	if err == nil || adapters.IsRetryable(err) {
+		t.Fatalf("expected permanent error for missing language, got %v", err)
+	}
+}
+
+func TestSendClassifiesCloudAPIErrors(t *testing.T) {
+	cases := map[string]bool{
+		`{"error":{"code":130429,"message":"Rate limit hit"}}`:                 true,
+		`{"error":{"code":131047,"message":"Re-engagement message"}}`:          false,
+		`{"error":{"code":132001,"message":"Template name does not exist"}}`:   false,
+		`{"error":{"code":133004,"message":"Server temporarily unavailable"}}`: true,
+	}
+	for body, retryable := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, body)
+		}))
+		adapter := New(&logger.Nop{}, WithConfig(Config{Token: "t", PhoneNumberID: "123", APIBase: server.URL}), WithClient(server.Client()))
+		err := adapter.Send(context.Background(), adapters.Message{To: "15551234567", Body: "hi"})
+		server.Close()
+		if err == nil {
+			t.Fatalf("%s: expected error", body)
+		}
+		if adapters.IsRetryable(err) != retryable {
+			t.Fatalf("%s: expected retryable=%v, got %v", body, retryable, err)
+		}
+	}
+}
@@ -13,23 +13,25 @@ import (
 
 // Re-export request types so consumers need not import internal packages.
 type (
-	CreateDefinition = internalcommands.CreateDefinition
-	TemplateUpsert   = internalcommands.TemplateUpsert
-	InboxMarkRead    = internalcommands.InboxMarkRead
-	InboxDismiss     = internalcommands.InboxDismiss
-	InboxSnooze      = internalcommands.InboxSnooze
+	CreateDefinition  = internalcommands.CreateDefinition
+	UpsertPreferences = internalcommands.UpsertPreferences
+	TemplateUpsert    = internalcommands.TemplateUpsert
+	InboxMarkRead     = internalcommands.InboxMarkRead
+	InboxDismiss      = internalcommands.InboxDismiss
+	InboxSnooze       = internalcommands.InboxSnooze
 )
 
 // Registry exposes go-command compatible handlers backed by the module services.
 type Registry struct {
-	Catalog          *internalcommands.Catalog
-	CreateDefinition command.Commander[CreateDefinition]
-	SaveTemplate     command.Commander[TemplateUpsert]
-	UpsertPreference command.Commander[preferences.PreferenceInput]
-	InboxMarkRead    command.Commander[InboxMarkRead]
-	InboxDismiss     command.Commander[InboxDismiss]
-	InboxSnooze      command.Commander[InboxSnooze]
-	EnqueueEvent     command.Commander[events.IntakeRequest]
+	Catalog           *internalcommands.Catalog
+	CreateDefinition  command.Commander[CreateDefinition]
+	SaveTemplate      command.Commander[TemplateUpsert]
+	UpsertPreference  command.Commander[preferences.PreferenceInput]
+	UpsertPreferences command.Commander[UpsertPreferences]
+	InboxMarkRead     command.Commander[InboxMarkRead]
+	InboxDismiss      command.Commander[InboxDismiss]
+	InboxSnooze       command.Commander[InboxSnooze]
+	EnqueueEvent      command.Commander[events.IntakeRequest]
 }
 
 // Dependencies mirror the internal command dependencies but keep them public.
@@ -56,14 +58,15 @@ func New(deps Dependencies) (*Registry, error) {
 		return nil, err
 	}
 	return &Registry{
-		Catalog:          catalog,
-		CreateDefinition: catalog.CreateDefinition,
-		SaveTemplate:     catalog.SaveTemplate,
-		UpsertPreference: catalog.UpsertPreference,
-		InboxMarkRead:    catalog.InboxMarkRead,
-		InboxDismiss:     catalog.InboxDismiss,
-		InboxSnooze:      catalog.InboxSnooze,
-		EnqueueEvent:     catalog.EnqueueEvent,
+		Catalog:           catalog,
+		CreateDefinition:  catalog.CreateDefinition,
+		SaveTemplate:      catalog.SaveTemplate,
+		UpsertPreference:  catalog.UpsertPreference,
+		UpsertPreferences: catalog.UpsertPreferences,
+		InboxMarkRead:     catalog.InboxMarkRead,
+		InboxDismiss:      catalog.InboxDismiss,
+		InboxSnooze:       catalog.InboxSnooze,
+		EnqueueEvent:      catalog.EnqueueEvent,
 	}, nil
 }
 
@@ -76,6 +79,7 @@ func (r *Registry) Commanders() []any {
 		r.CreateDefinition,
 		r.SaveTemplate,
 		r.UpsertPreference,
+		r.UpsertPreferences,
 		r.InboxMarkRead,
 		r.InboxDismiss,
 		r.InboxSnooze,
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"time"
 )
 
@@ -14,6 +15,7 @@ type Config struct {
 	Localization LocalizationConfig `mapstructure:"localization" json:"localization"`
 	Dispatcher   DispatcherConfig   `mapstructure:"dispatcher" json:"dispatcher"`
 	Inbox        InboxConfig        `mapstructure:"inbox" json:"inbox"`
+	Preferences  PreferencesConfig  `mapstructure:"preferences" json:"preferences"`
 	Templates    TemplateConfig     `mapstructure:"templates" json:"templates"`
 	Realtime     RealtimeConfig     `mapstructure:"realtime" json:"realtime"`
 	Options      OptionsConfig      `mapstructure:"options" json:"options"`
@@ -30,12 +32,37 @@ type DispatcherConfig struct {
 	MaxAttempts int  `mapstructure:"max_attempts" json:"max_attempts,omitempty"`
 	MaxWorkers  int  `mapstructure:"max_workers" json:"max_workers,omitempty"`
 	// EnvFallbackAllowlist gates using global config/env credentials for specific subjects (e.g., admin/test users).
+	// Entries match a recipient or tenant ID exactly, or as path.Match globs such as "*@example.com" or "tenant-dev-*".
 	EnvFallbackAllowlist []string `mapstructure:"env_fallback_allowlist" json:"env_fallback_allowlist,omitempty"`
+	// MaxRetryDelay caps the wait between attempts, including provider
+	// Retry-After hints; zero uses the dispatcher default (30s).
+	MaxRetryDelay time.Duration `mapstructure:"max_retry_delay" json:"max_retry_delay,omitempty"`
+	// Selection picks how providers sharing a channel are ordered, keyed by channel (e.g. "sms").
+	Selection map[string]ProviderSelectionConfig `mapstructure:"selection" json:"selection,omitempty"`
+}
+
+// ProviderSelectionConfig names a selection strategy (first, round-robin,
+// weighted) and, for weighted, the per-provider weights.
+type ProviderSelectionConfig struct {
+	Strategy string         `mapstructure:"strategy" json:"strategy,omitempty"`
+	Weights  map[string]int `mapstructure:"weights" json:"weights,omitempty"`
 }
 
 // InboxConfig enables the in-app notification center.
 type InboxConfig struct {
 	Enabled bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	// MaxPinned caps pinned items per user; zero disables the limit.
+	MaxPinned int `mapstructure:"max_pinned" json:"max_pinned,omitempty"`
+	// SnoozeReleaseInterval controls how often the module resurfaces expired
+	// snoozes; zero disables the background releaser.
+	SnoozeReleaseInterval time.Duration `mapstructure:"snooze_release_interval" json:"snooze_release_interval,omitempty"`
+}
+
+// PreferencesConfig tunes preference evaluation.
+type PreferencesConfig struct {
+	// CriticalSeverities lists definition severities that bypass opt-outs and
+	// quiet hours; do_not_disturb still applies.
+	CriticalSeverities []string `mapstructure:"critical_severities" json:"critical_severities,omitempty"`
 }
 
 // TemplateConfig scopes cache + rendering behaviors.
@@ -62,10 +89,14 @@ func Defaults() Config {
 			MaxAttempts:          3,
 			MaxWorkers:           4,
 			EnvFallbackAllowlist: []string{},
+			MaxRetryDelay:        30 * time.Second,
 		},
 		Inbox: InboxConfig{
 			Enabled: true,
 		},
+		Preferences: PreferencesConfig{
+			CriticalSeverities: []string{"critical"},
+		},
 		Templates: TemplateConfig{
 			CacheTTL: time.Minute,
 		},
@@ -89,6 +120,20 @@ func (c *Config) Validate() error {
 	if c.Dispatcher.MaxWorkers <= 0 {
 		return fmt.Errorf("dispatcher.max_workers must be > 0")
 	}
+	if c.Dispatcher.MaxRetryDelay < 0 {
+		return fmt.Errorf("dispatcher.max_retry_delay must be >= 0")
+	}
+	for _, pattern := range c.Dispatcher.EnvFallbackAllowlist {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("dispatcher.env_fallback_allowlist: invalid pattern %q", pattern)
+		}
+	}
+	if c.Inbox.MaxPinned < 0 {
+		return fmt.Errorf("inbox.max_pinned must be >= 0")
+	}
+	if c.Inbox.SnoozeReleaseInterval < 0 {
+		return fmt.Errorf("inbox.snooze_release_interval must be >= 0")
+	}
 	if c.Templates.CacheTTL < 0 {
 		return fmt.Errorf("templates.cache_ttl must be >= 0")
 	}
@@ -73,3 +73,14 @@ func TestLoadPreservesExplicitDisabledFlags(t *testing.T) {
 		t.Fatalf("expected realtime disabled to be preserved")
 	}
 }
+
+func TestLoadRejectsMalformedFallbackPattern(t *testing.T) {
+	input := map[string]any{
+		"dispatcher": map[string]any{
+			"env_fallback_allowlist": []string{"*@example.com", "tenant-[dev"},
+		},
+	}
+	if _, err := Load(input); err == nil {
+		t.Fatalf("expected malformed allowlist pattern to fail validation")
+	}
+}
@@ -227,6 +227,8 @@ type NotificationMessage struct {
 	Receiver    string    `bun:",nullzero,notnull"`
 	Status      string    `bun:",nullzero"`
 	Metadata    JSONMap   `bun:"type:jsonb,nullzero"`
+	// LinkExpiresAt is when the resolved action link stops working.
+	LinkExpiresAt time.Time `bun:",nullzero" json:"link_expires_at"`
 }
 
 // DeliveryAttempt tracks adapter executions.
@@ -272,18 +274,25 @@ type InboxItem struct {
 	bun.BaseModel `bun:"table:notification_inbox_items"`
 	RecordMeta
 
-	UserID       string    `bun:",nullzero,notnull" json:"user_id"`
-	MessageID    uuid.UUID `bun:",nullzero" json:"message_id"`
-	Title        string    `bun:",nullzero" json:"title"`
-	Body         string    `bun:",nullzero" json:"body"`
-	Locale       string    `bun:",nullzero" json:"locale"`
-	Unread       bool      `bun:",nullzero" json:"unread"`
-	Pinned       bool      `bun:",nullzero" json:"pinned"`
-	ActionURL    string    `bun:",nullzero" json:"action_url"`
-	Metadata     JSONMap   `bun:"type:jsonb,nullzero" json:"metadata,omitempty"`
-	ReadAt       time.Time `bun:",nullzero" json:"read_at"`
-	DismissedAt  time.Time `bun:",nullzero" json:"dismissed_at"`
-	SnoozedUntil time.Time `bun:",nullzero" json:"snoozed_until"`
+	UserID       string     `bun:",nullzero,notnull" json:"user_id"`
+	MessageID    uuid.UUID  `bun:",nullzero" json:"message_id"`
+	Title        string     `bun:",nullzero" json:"title"`
+	Body         string     `bun:",nullzero" json:"body"`
+	Locale       string     `bun:",nullzero" json:"locale"`
+	Unread       bool       `bun:",nullzero" json:"unread"`
+	Pinned       bool       `bun:",nullzero" json:"pinned"`
+	ActionURL    string     `bun:",nullzero" json:"action_url"`
+	Metadata     JSONMap    `bun:"type:jsonb,nullzero" json:"metadata,omitempty"`
+	Tags         StringList `bun:"type:jsonb,nullzero" json:"tags,omitempty"`
+	ReadAt       time.Time  `bun:",nullzero" json:"read_at"`      // first read; preserved across toggles
+	LastReadAt   time.Time  `bun:",nullzero" json:"last_read_at"` // most recent read
+	DismissedAt  time.Time  `bun:",nullzero" json:"dismissed_at"`
+	ArchivedAt   time.Time  `bun:",nullzero" json:"archived_at"`
+	SnoozedUntil time.Time  `bun:",nullzero" json:"snoozed_until"`
+	// LinkExpiresAt is when ActionURL stops working; zero when it does not expire.
+	LinkExpiresAt time.Time `bun:",nullzero" json:"link_expires_at"`
+	// LinkExpired is computed by List when ListFilters.FlagExpiredLinks is set.
+	LinkExpired bool `bun:"-" json:"link_expired,omitempty"`
 }
 
 // Domain constants for statuses.
@@ -293,11 +302,13 @@ const (
 	EventStatusProcessed = "processed"
 	EventStatusFailed    = "failed"
 
-	MessageStatusPending   = "pending"
-	MessageStatusDelivered = "delivered"
-	MessageStatusFailed    = "failed"
+	MessageStatusPending     = "pending"
+	MessageStatusDelivered   = "delivered"
+	MessageStatusFailed      = "failed"
+	MessageStatusUndelivered = "undelivered"
 
 	AttemptStatusPending   = "pending"
 	AttemptStatusSucceeded = "succeeded"
 	AttemptStatusFailed    = "failed"
+	AttemptStatusSkipped   = "skipped"
 )
@@ -16,10 +16,14 @@ import (
 
 // Re-export commonly used types so callers don't depend on the internal package.
 type (
-	CreateInput = inbox.CreateInput
-	ListFilters = inbox.ListFilters
+	CreateInput   = inbox.CreateInput
+	ListFilters   = inbox.ListFilters
+	PinLimitError = inbox.PinLimitError
 )
 
+// ErrPinLimitExceeded matches any PinLimitError via errors.Is.
+var ErrPinLimitExceeded = inbox.ErrPinLimitExceeded
+
 // Service exposes inbox management helpers to consumers.
 type Service struct {
 	internal *inbox.Service
@@ -31,6 +35,7 @@ type Dependencies struct {
 	Broadcaster broadcaster.Broadcaster
 	Logger      logger.Logger
 	Activity    activity.Hooks
+	MaxPinned   int
 }
 
 var errServiceNotInitialised = errors.New("inbox: service not initialised")
@@ -42,6 +47,7 @@ func New(deps Dependencies) (*Service, error) {
 		Broadcaster: deps.Broadcaster,
 		Logger:      deps.Logger,
 		Activity:    deps.Activity,
+		MaxPinned:   deps.MaxPinned,
 	})
 	if err != nil {
 		return nil, err
@@ -101,6 +107,102 @@ func (s *Service) Dismiss(ctx context.Context, userID, id string) error {
 	return s.internal.Dismiss(ctx, userID, itemID)
 }
 
+// Archive hides an inbox item from the active list without marking it read.
+func (s *Service) Archive(ctx context.Context, userID, id string) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	itemID, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.internal.Archive(ctx, userID, itemID)
+}
+
+// Unarchive restores an archived inbox item.
+func (s *Service) Unarchive(ctx context.Context, userID, id string) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	itemID, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.internal.Unarchive(ctx, userID, itemID)
+}
+
+// SetPinned pins or unpins an inbox item.
+func (s *Service) SetPinned(ctx context.Context, userID, id string, pinned bool) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	itemID, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.internal.SetPinned(ctx, userID, itemID, pinned)
+}
+
+// PinLimit reports the configured per-user pin limit (0 = unlimited).
+func (s *Service) PinLimit() int {
+	if s == nil || s.internal == nil {
+		return 0
+	}
+	return s.internal.PinLimit()
+}
+
+// BulkDismiss dismisses multiple inbox items in one call.
+func (s *Service) BulkDismiss(ctx context.Context, userID string, ids []string) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	uuids, err := parseUUIDs(ids)
+	if err != nil {
+		return err
+	}
+	return s.internal.BulkDismiss(ctx, userID, uuids)
+}
+
+// BulkSnooze defers multiple inbox items until the given unix timestamp.
+func (s *Service) BulkSnooze(ctx context.Context, userID string, ids []string, until int64) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	uuids, err := parseUUIDs(ids)
+	if err != nil {
+		return err
+	}
+	return s.internal.BulkSnooze(ctx, userID, uuids, unixToTime(until))
+}
+
+// BulkSetPinned pins or unpins multiple inbox items.
+func (s *Service) BulkSetPinned(ctx context.Context, userID string, ids []string, pinned bool) error {
+	if s == nil || s.internal == nil {
+		return errServiceNotInitialised
+	}
+	uuids, err := parseUUIDs(ids)
+	if err != nil {
+		return err
+	}
+	return s.internal.BulkSetPinned(ctx, userID, uuids, pinned)
+}
+
+// ReleaseDue resurfaces items whose snooze expired at or before now.
+func (s *Service) ReleaseDue(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.internal == nil {
+		return 0, errServiceNotInitialised
+	}
+	return s.internal.ReleaseDue(ctx, now)
+}
+
+// RunReleaser calls ReleaseDue periodically until ctx is cancelled.
+func (s *Service) RunReleaser(ctx context.Context, interval time.Duration) {
+	if s == nil || s.internal == nil {
+		return
+	}
+	s.internal.RunReleaser(ctx, interval)
+}
+
 // BadgeCount returns unread counts.
 func (s *Service) BadgeCount(ctx context.Context, userID string) (int, error) {
 	if s == nil || s.internal == nil {
@@ -68,6 +68,21 @@ type NotificationPreferenceRepository interface {
 	GetBySubject(ctx context.Context, subjectType, subjectID string, definitionCode string, channel string) (*domain.NotificationPreference, error)
 }
 
+// PreferenceWriter is the subset of preference operations available inside a
+// transaction.
+type PreferenceWriter interface {
+	Create(ctx context.Context, record *domain.NotificationPreference) error
+	Update(ctx context.Context, record *domain.NotificationPreference) error
+	GetBySubject(ctx context.Context, subjectType, subjectID string, definitionCode string, channel string) (*domain.NotificationPreference, error)
+}
+
+// PreferenceTxRepository is implemented by preference repositories that can
+// apply several writes atomically. Returning an error from fn discards every
+// write made through the supplied writer.
+type PreferenceTxRepository interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context, repo PreferenceWriter) error) error
+}
+
 type SubscriptionGroupRepository interface {
 	Repository[domain.SubscriptionGroup]
 	GetByCode(ctx context.Context, code string) (*domain.SubscriptionGroup, error)
@@ -76,8 +91,20 @@ type SubscriptionGroupRepository interface {
 type InboxRepository interface {
 	Repository[domain.InboxItem]
 	ListByUser(ctx context.Context, userID string, opts ListOptions) (ListResult[domain.InboxItem], error)
+	// ListByUserTags returns the user's items carrying any of the provided tags.
+	ListByUserTags(ctx context.Context, userID string, tags []string, opts ListOptions) (ListResult[domain.InboxItem], error)
 	MarkRead(ctx context.Context, id uuid.UUID, read bool) error
 	Snooze(ctx context.Context, id uuid.UUID, until time.Time) error
+	// ListSnoozedBefore returns snoozed items due at or before before,
+	// skipping dismissed and archived items.
+	ListSnoozedBefore(ctx context.Context, before time.Time, limit int) ([]domain.InboxItem, error)
+	// ReleaseSnooze clears the snooze of a live (not dismissed or archived)
+	// item that is still due at or before before, and marks it unread. It
+	// reports false when nothing was released, e.g. because the item was
+	// snoozed again to a later time.
+	ReleaseSnooze(ctx context.Context, id uuid.UUID, before time.Time) (bool, error)
 	Dismiss(ctx context.Context, id uuid.UUID) error
+	SetArchived(ctx context.Context, id uuid.UUID, archived bool) error
 	CountUnread(ctx context.Context, userID string) (int, error)
+	CountPinned(ctx context.Context, userID string) (int, error)
 }
@@ -0,0 +1,139 @@
+package links
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// ClickTokenQueryKey is the query parameter ClickHandler reads the tracking
+// token from before falling back to the last path segment.
+const ClickTokenQueryKey = "token"
+
+// ClickErrorFunc renders a failed click. err matches ErrLinkNotFound,
+// ErrLinkExpired (as an *ExpiredError) or ErrLinkConsumed (as a
+// *ConsumedError) when the link cannot be followed.
+type ClickErrorFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// ClickOption configures ClickHandler.
+type ClickOption func(*clickHandler)
+
+// WithClickErrorHandler replaces the default plain-text error responses, for
+// example to render an expired link page.
+func WithClickErrorHandler(fn ClickErrorFunc) ClickOption {
+	return func(h *clickHandler) {
+		if fn != nil {
+			h.onError = fn
+		}
+	}
+}
+
+type clickHandler struct {
+	store   LinkStore
+	onError ClickErrorFunc
+}
+
+// ClickHandler records a click through store and redirects to the stored
+// link URL. Mount it on a route such as /l/{token} or /l?token=...
+// Expired links are rejected with an *ExpiredError; when store implements
+// LinkLookup the click is not recorded. Consumed links, and single-use links
+// on their second click, are rejected with a *ConsumedError.
+func ClickHandler(store LinkStore, opts ...ClickOption) http.Handler {
+	handler := &clickHandler{
+		store:   store,
+		onError: defaultClickError,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler
+}
+
+func (h *clickHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "link tracking unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	token := clickToken(r)
+	if token == "" {
+		http.Error(w, "missing link token", http.StatusBadRequest)
+		return
+	}
+	if lookup, ok := h.store.(LinkLookup); ok {
+		record, err := lookup.Lookup(r.Context(), token)
+		if err != nil {
+			h.onError(w, r, err)
+			return
+		}
+		if record.Expired(time.Now()) {
+			h.onError(w, r, &ExpiredError{Token: token, Record: record})
+			return
+		}
+	}
+	record, err := h.store.RecordClick(r.Context(), token, clickMetadata(r))
+	if err != nil {
+		h.onError(w, r, err)
+		return
+	}
+	if record.URL == "" {
+		h.onError(w, r, ErrLinkNotFound)
+		return
+	}
+	if record.Expired(time.Now()) {
+		h.onError(w, r, &ExpiredError{Token: token, Record: record})
+		return
+	}
+	if !record.ConsumedAt.IsZero() {
+		h.onError(w, r, &ConsumedError{Token: token, Record: record})
+		return
+	}
+	if record.SingleUse {
+		consumed, err := h.store.Consume(r.Context(), token)
+		if err != nil {
+			h.onError(w, r, err)
+			return
+		}
+		if !consumed {
+			h.onError(w, r, &ConsumedError{Token: token, Record: record})
+			return
+		}
+	}
+	http.Redirect(w, r, record.URL, http.StatusFound)
+}
+
+func defaultClickError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrLinkNotFound):
+		http.NotFound(w, r)
+	case errors.Is(err, ErrLinkConsumed), errors.Is(err, ErrLinkExpired):
+		http.Error(w, "link expired", http.StatusGone)
+	default:
+		http.Error(w, "record click failed", http.StatusInternalServerError)
+	}
+}
+
+func clickToken(r *http.Request) string {
+	if token := strings.TrimSpace(r.URL.Query().Get(ClickTokenQueryKey)); token != "" {
+		return token
+	}
+	segment := path.Base(strings.TrimRight(r.URL.Path, "/"))
+	if segment == "." || segment == "/" {
+		return ""
+	}
+	return strings.TrimSpace(segment)
+}
+
+func clickMetadata(r *http.Request) map[string]any {
+	meta := map[string]any{
+		"clicked_at": time.Now().UTC(),
+	}
+	if agent := r.UserAgent(); agent != "" {
+		meta["user_agent"] = agent
+	}
+	if referer := r.Referer(); referer != "" {
+		meta["referer"] = referer
+	}
+	return meta
+}
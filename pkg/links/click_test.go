@@ -0,0 +1,180 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type clickStore struct {
+	records  map[string]LinkRecord
+	consumed map[string]bool
+	meta     map[string]any
+	err      error
+}
+
+func (s *clickStore) Save(ctx context.Context, records []LinkRecord) error { return nil }
+
+func (s *clickStore) RecordClick(ctx context.Context, token string, meta map[string]any) (LinkRecord, error) {
+	s.meta = meta
+	if s.err != nil {
+		return LinkRecord{}, s.err
+	}
+	record, ok := s.records[token]
+	if !ok {
+		return LinkRecord{}, ErrLinkNotFound
+	}
+	return record, nil
+}
+
+func (s *clickStore) Consume(ctx context.Context, token string) (bool, error) {
+	if _, ok := s.records[token]; !ok {
+		return false, ErrLinkNotFound
+	}
+	if s.consumed[token] {
+		return false, nil
+	}
+	if s.consumed == nil {
+		s.consumed = map[string]bool{}
+	}
+	s.consumed[token] = true
+	return true, nil
+}
+
+func TestClickHandlerRedirects(t *testing.T) {
+	store := &clickStore{records: map[string]LinkRecord{"abc": {URL: "https://example.com/reset"}}}
+	handler := ClickHandler(store)
+
+	for _, target := range []string{"/l/abc", "/l?token=abc"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("User-Agent", "mail-client")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected 302, got %d", target, rec.Code)
+		}
+		if got := rec.Header().Get("Location"); got != "https://example.com/reset" {
+			t.Fatalf("%s: unexpected location %q", target, got)
+		}
+		if store.meta["user_agent"] != "mail-client" {
+			t.Fatalf("%s: expected user agent in click metadata, got %+v", target, store.meta)
+		}
+	}
+}
+
+func TestClickHandlerErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		store  LinkStore
+		target string
+		want   int
+	}{
+		{name: "unknown token", store: &clickStore{}, target: "/l/missing", want: http.StatusNotFound},
+		{name: "missing token", store: &clickStore{}, target: "/", want: http.StatusBadRequest},
+		{name: "store failure", store: &clickStore{err: errors.New("boom")}, target: "/l/abc", want: http.StatusInternalServerError},
+		{name: "nop store", store: &NopStore{}, target: "/l/abc", want: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ClickHandler(tc.store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestClickHandlerRejectsConsumedLinks(t *testing.T) {
+	store := &clickStore{records: map[string]LinkRecord{
+		"once": {URL: "https://example.com/reset", SingleUse: true},
+		"used": {URL: "https://example.com/old", ConsumedAt: time.Now()},
+	}}
+	var rendered error
+	handler := ClickHandler(store, WithClickErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		rendered = err
+		w.WriteHeader(http.StatusGone)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/once", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected first click to redirect, got %d", rec.Code)
+	}
+
+	for _, token := range []string{"once", "used"} {
+		rendered = nil
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/"+token, nil))
+		if rec.Code != http.StatusGone {
+			t.Fatalf("%s: expected 410, got %d", token, rec.Code)
+		}
+		var consumed *ConsumedError
+		if !errors.Is(rendered, ErrLinkConsumed) || !errors.As(rendered, &consumed) || consumed.Token != token {
+			t.Fatalf("%s: expected ConsumedError, got %v", token, rendered)
+		}
+	}
+}
+
+type lookupClickStore struct {
+	clickStore
+	clicks int
+}
+
+func (s *lookupClickStore) Lookup(ctx context.Context, token string) (LinkRecord, error) {
+	record, ok := s.records[token]
+	if !ok {
+		return LinkRecord{}, ErrLinkNotFound
+	}
+	return record, nil
+}
+
+func (s *lookupClickStore) RecordClick(ctx context.Context, token string, meta map[string]any) (LinkRecord, error) {
+	s.clicks++
+	return s.clickStore.RecordClick(ctx, token, meta)
+}
+
+func TestClickHandlerRejectsExpiredLinks(t *testing.T) {
+	records := map[string]LinkRecord{
+		"old":  {URL: "https://example.com/reset/old", ExpiresAt: time.Now().Add(-time.Minute)},
+		"live": {URL: "https://example.com/reset/live", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	plain := &clickStore{records: records}
+	lookup := &lookupClickStore{clickStore: clickStore{records: records}}
+
+	for name, store := range map[string]LinkStore{"plain": plain, "lookup": lookup} {
+		var rendered error
+		handler := ClickHandler(store, WithClickErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			rendered = err
+			w.WriteHeader(http.StatusGone)
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/old", nil))
+		if rec.Code != http.StatusGone {
+			t.Fatalf("%s: expected 410 for expired link, got %d", name, rec.Code)
+		}
+		var expired *ExpiredError
+		if !errors.Is(rendered, ErrLinkExpired) || !errors.As(rendered, &expired) || expired.Token != "old" {
+			t.Fatalf("%s: expected ExpiredError, got %v", name, rendered)
+		}
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/live", nil))
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected live link to redirect, got %d", name, rec.Code)
+		}
+	}
+	if lookup.clicks != 1 {
+		t.Fatalf("expected only the live click recorded with a LinkLookup store, got %d", lookup.clicks)
+	}
+
+	rec := httptest.NewRecorder()
+	ClickHandler(plain).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/l/old", nil))
+	if rec.Code != http.StatusGone {
+		t.Fatalf("expected default handler to answer 410, got %d", rec.Code)
+	}
+}
@@ -12,6 +12,16 @@ func (n *NopStore) Save(ctx context.Context, records []LinkRecord) error {
 	return nil
 }
 
+// RecordClick reports every token as unknown since nothing is stored.
+func (n *NopStore) RecordClick(ctx context.Context, token string, meta map[string]any) (LinkRecord, error) {
+	return LinkRecord{}, ErrLinkNotFound
+}
+
+// Consume reports every token as unknown since nothing is stored.
+func (n *NopStore) Consume(ctx context.Context, token string) (bool, error) {
+	return false, ErrLinkNotFound
+}
+
 // NopObserver implements LinkObserver without side effects.
 type NopObserver struct{}
 
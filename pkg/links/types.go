@@ -2,6 +2,7 @@ package links
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 )
@@ -15,8 +16,52 @@ const (
 	ResolvedURLKey = "url"
 	// ResolvedURLMetaPrefix namespaces extra resolved URL fields.
 	ResolvedURLMetaPrefix = "meta."
+	// ResolvedExpiresAtKey carries the action link expiry (RFC 3339) in
+	// template payloads.
+	ResolvedExpiresAtKey = "link_expires_at"
+	// ResolvedTrackingTokenKey carries the action link tracking token in
+	// template payloads and message metadata.
+	ResolvedTrackingTokenKey = "tracking_token"
 )
 
+// ErrLinkNotFound is returned when a tracking token matches no stored link.
+var ErrLinkNotFound = errors.New("links: link not found")
+
+// ErrLinkConsumed matches, through errors.Is, clicks on a link that was
+// already used up.
+var ErrLinkConsumed = errors.New("links: link already consumed")
+
+// ConsumedError reports a click on a consumed link. Record lets the web layer
+// render an expired link page with context about the original link.
+type ConsumedError struct {
+	Token  string
+	Record LinkRecord
+}
+
+func (e *ConsumedError) Error() string { return ErrLinkConsumed.Error() }
+
+func (e *ConsumedError) Is(target error) bool { return target == ErrLinkConsumed }
+
+// ErrLinkExpired matches, through errors.Is, clicks on a link past its
+// ExpiresAt.
+var ErrLinkExpired = errors.New("links: link expired")
+
+// ExpiredError reports a click on an expired link. Record lets the web layer
+// render an expired link page with context about the original link.
+type ExpiredError struct {
+	Token  string
+	Record LinkRecord
+}
+
+func (e *ExpiredError) Error() string { return ErrLinkExpired.Error() }
+
+func (e *ExpiredError) Is(target error) bool { return target == ErrLinkExpired }
+
+// Expired reports whether the record has an expiry that is not after now.
+func (r LinkRecord) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
 // ResolvedURLKeySet lists the canonical resolved URL keys.
 var ResolvedURLKeySet = map[string]struct{}{
 	ResolvedURLActionKey:   {},
@@ -37,6 +82,14 @@ type LinkBuilder interface {
 	Build(ctx context.Context, req LinkRequest) (ResolvedLinks, error)
 }
 
+// BatchLinkBuilder is an optional LinkBuilder extension used for broadcasts.
+// BuildMany must return one ResolvedLinks per request, in request order, so
+// the cost of signing can be amortized across recipients.
+type BatchLinkBuilder interface {
+	LinkBuilder
+	BuildMany(ctx context.Context, reqs []LinkRequest) ([]ResolvedLinks, error)
+}
+
 // LinkRequest captures the context passed to a LinkBuilder.
 type LinkRequest struct {
 	EventID      string
@@ -57,6 +110,7 @@ type ResolvedLinks struct {
 	ActionURL   string
 	ManifestURL string
 	URL         string
+	ExpiresAt   time.Time      // zero when the links do not expire
 	Metadata    map[string]any // builder-provided metadata (tokens, expiry, ids)
 	Records     []LinkRecord   // optional, for storage/analytics
 }
@@ -64,18 +118,34 @@ type ResolvedLinks struct {
 // LinkRecord represents a stored or auditable link resolution record.
 type LinkRecord struct {
 	ID         string
+	Token      string // opaque tracking token used to record clicks
 	URL        string
 	Channel    string
 	Recipient  string
 	MessageID  string
 	Definition string
 	ExpiresAt  time.Time
+	SingleUse  bool      // consumed by the first click
+	ConsumedAt time.Time // zero until the link is consumed
 	Metadata   map[string]any
 }
 
 // LinkStore persists resolved link records.
 type LinkStore interface {
 	Save(ctx context.Context, records []LinkRecord) error
+	// RecordClick counts a click on the link identified by token and returns
+	// its record. Unknown tokens return ErrLinkNotFound.
+	RecordClick(ctx context.Context, token string, meta map[string]any) (LinkRecord, error)
+	// Consume marks the link identified by token as used. It reports false
+	// when the link was already consumed; unknown tokens return ErrLinkNotFound.
+	Consume(ctx context.Context, token string) (bool, error)
+}
+
+// LinkLookup is implemented by stores that can read a link without counting
+// a click. ClickHandler uses it to reject expired links before recording the
+// click.
+type LinkLookup interface {
+	Lookup(ctx context.Context, token string) (LinkRecord, error)
 }
 
 // LinkObserver receives resolved link events.
@@ -85,8 +155,14 @@ type LinkObserver interface {
 
 // LinkResolution bundles the request and resolved outputs.
 type LinkResolution struct {
-	Request  LinkRequest
-	Resolved ResolvedLinks
+	Request       LinkRequest
+	Resolved      ResolvedLinks
+	TrackingToken string // token of the primary (action) link record
+	// Channel, Recipient and MessageID attribute the resolution to a single
+	// delivery so observers can correlate multi-channel events.
+	Channel   string
+	Recipient string
+	MessageID string
 }
 
 // FailureMode controls how link resolution errors are handled.
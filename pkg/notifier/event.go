@@ -0,0 +1,201 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-notifications/internal/dispatcher"
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/interfaces/store"
+	"github.com/goliatone/go-notifications/pkg/templates"
+)
+
+// ErrMissingFields is matched by MissingFieldsError.
+var ErrMissingFields = errors.New("notifier: event context is missing required fields")
+
+// MissingFieldsError lists the required template fields absent from an
+// event context, keyed by channel type.
+type MissingFieldsError struct {
+	DefinitionCode string
+	Missing        map[string][]string
+}
+
+func (e *MissingFieldsError) Error() string {
+	channels := make([]string, 0, len(e.Missing))
+	for channel := range e.Missing {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	parts := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		parts = append(parts, fmt.Sprintf("%s: %s", channel, strings.Join(e.Missing[channel], ", ")))
+	}
+	return fmt.Sprintf("notifier: event %s missing required fields (%s)", e.DefinitionCode, strings.Join(parts, "; "))
+}
+
+func (e *MissingFieldsError) Is(target error) bool {
+	return target == ErrMissingFields
+}
+
+// EventBuilder assembles an Event fluently:
+//
+//	evt, err := notifier.NewEvent("welcome").To("user-1").With("Name", "Ada").Locale("en").Build()
+type EventBuilder struct {
+	evt  Event
+	errs []error
+}
+
+// NewEvent starts an event for the given definition code.
+func NewEvent(definitionCode string) *EventBuilder {
+	return &EventBuilder{evt: Event{DefinitionCode: strings.TrimSpace(definitionCode)}}
+}
+
+// To appends recipients, skipping blanks.
+func (b *EventBuilder) To(recipients ...string) *EventBuilder {
+	for _, recipient := range recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			b.evt.Recipients = append(b.evt.Recipients, recipient)
+		}
+	}
+	return b
+}
+
+// With sets a context value.
+func (b *EventBuilder) With(key string, value any) *EventBuilder {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		b.errs = append(b.errs, errors.New("notifier: context key is required"))
+		return b
+	}
+	if b.evt.Context == nil {
+		b.evt.Context = make(map[string]any)
+	}
+	b.evt.Context[key] = value
+	return b
+}
+
+// WithContext merges values into the context.
+func (b *EventBuilder) WithContext(values map[string]any) *EventBuilder {
+	for key, value := range values {
+		b.With(key, value)
+	}
+	return b
+}
+
+// Channels restricts delivery to the given channels.
+func (b *EventBuilder) Channels(channels ...string) *EventBuilder {
+	b.evt.Channels = append(b.evt.Channels, channels...)
+	return b
+}
+
+// Locale sets the render locale.
+func (b *EventBuilder) Locale(locale string) *EventBuilder {
+	b.evt.Locale = strings.TrimSpace(locale)
+	return b
+}
+
+// Tenant sets the tenant ID.
+func (b *EventBuilder) Tenant(tenantID string) *EventBuilder {
+	b.evt.TenantID = tenantID
+	return b
+}
+
+// Actor sets the actor ID.
+func (b *EventBuilder) Actor(actorID string) *EventBuilder {
+	b.evt.ActorID = actorID
+	return b
+}
+
+// ScheduleAt delays delivery until at.
+func (b *EventBuilder) ScheduleAt(at time.Time) *EventBuilder {
+	b.evt.ScheduledAt = at
+	return b
+}
+
+// Build returns the event, or the errors collected while building it
+// together with missing definition code or recipients. Template schemas are
+// checked when the event is sent (Manager.Send), or on demand with
+// Manager.Validate.
+func (b *EventBuilder) Build() (Event, error) {
+	errs := append([]error(nil), b.errs...)
+	if err := validateEvent(b.evt); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Event{}, err
+	}
+	return b.evt, nil
+}
+
+// Send builds the event and sends it through m, which validates it against
+// the definition's template schemas first.
+func (b *EventBuilder) Send(ctx context.Context, m *Manager) error {
+	evt, err := b.Build()
+	if err != nil {
+		return err
+	}
+	return m.Send(ctx, evt)
+}
+
+// dispatchProvidedFields are set by the dispatcher on every render payload,
+// so schemas may require them without the event context carrying them.
+var dispatchProvidedFields = []string{"recipient", "channel", "provider", "definition", "locale"}
+
+// Validate checks the event context against the required fields of each
+// channel's template (the event channels, or the definition's), resolved for
+// the event locale. It returns a *MissingFieldsError listing missing keys.
+// Channels without a template are skipped; they fail at dispatch instead.
+// Validation is skipped when the manager has no definitions repository or
+// template service.
+func (m *Manager) Validate(ctx context.Context, evt Event) error {
+	if err := validateEvent(evt); err != nil {
+		return err
+	}
+	if m == nil || m.definitions == nil || m.templates == nil {
+		return nil
+	}
+	def, err := m.definitions.GetByCode(ctx, evt.DefinitionCode)
+	if err != nil {
+		return fmt.Errorf("notifier: load definition %s: %w", evt.DefinitionCode, err)
+	}
+
+	data := make(map[string]any, len(evt.Context)+len(dispatchProvidedFields))
+	for _, field := range dispatchProvidedFields {
+		data[field] = true
+	}
+	for key, value := range evt.Context {
+		data[key] = value
+	}
+
+	channels := evt.Channels
+	if len(channels) == 0 {
+		channels = def.Channels
+	}
+	seen := make(map[string]struct{}, len(channels))
+	missing := make(map[string][]string)
+	for _, channel := range channels {
+		chType, _ := adapters.ParseChannel(channel)
+		if _, ok := seen[chType]; ok || chType == "" {
+			continue
+		}
+		seen[chType] = struct{}{}
+		code := dispatcher.TemplateCodeForChannel(def, channel)
+		err := m.templates.Validate(ctx, code, chType, evt.Locale, data)
+		var schemaErr templates.SchemaError
+		switch {
+		case err == nil, errors.Is(err, store.ErrNotFound):
+		case errors.As(err, &schemaErr):
+			missing[chType] = schemaErr.Missing
+		default:
+			return fmt.Errorf("notifier: validate %s template %s: %w", chType, code, err)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingFieldsError{DefinitionCode: evt.DefinitionCode, Missing: missing}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-notifications/internal/storage/memory"
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/console"
+	"github.com/goliatone/go-notifications/pkg/config"
+	"github.com/goliatone/go-notifications/pkg/domain"
+	"github.com/goliatone/go-notifications/pkg/interfaces/cache"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+	"github.com/goliatone/go-notifications/pkg/interfaces/store"
+	"github.com/goliatone/go-notifications/pkg/templates"
+)
+
+func TestEventBuilderBuild(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	evt, err := NewEvent(" welcome ").
+		To("user-1", " ", "user-2").
+		With("Name", "Ada").
+		Channels("email").
+		Locale("es").
+		Tenant("tenant-1").
+		Actor("actor-1").
+		ScheduleAt(at).
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if evt.DefinitionCode != "welcome" || len(evt.Recipients) != 2 || evt.Context["Name"] != "Ada" {
+		t.Fatalf("unexpected event: %#v", evt)
+	}
+	if evt.Locale != "es" || evt.TenantID != "tenant-1" || evt.ActorID != "actor-1" || !evt.ScheduledAt.Equal(at) {
+		t.Fatalf("unexpected event options: %#v", evt)
+	}
+
+	if _, err := NewEvent("welcome").With("", "x").Build(); err == nil {
+		t.Fatalf("expected error for empty key and missing recipients")
+	}
+}
+
+func TestEventBuilderValidatesTemplateSchema(t *testing.T) {
+	ctx := context.Background()
+	defRepo := memory.NewDefinitionRepository()
+	eventRepo := memory.NewEventRepository()
+	tplSvc, err := templates.New(templates.Dependencies{
+		Repository: memory.NewTemplateRepository(),
+		Cache:      &cache.Nop{},
+		Logger:     &logger.Nop{},
+		Translator: newTestTranslator(t),
+	})
+	if err != nil {
+		t.Fatalf("template service: %v", err)
+	}
+	createTemplate(t, tplSvc, templates.TemplateInput{
+		Code:    "order-email",
+		Channel: "email",
+		Locale:  "en",
+		Subject: "Order {{ OrderID }} for {{ recipient }}",
+		Body:    "Hi {{ Name }}, order {{ OrderID }} shipped.",
+		Format:  "text/plain",
+		Schema:  domain.TemplateSchema{Required: []string{"Name", "OrderID", "recipient"}},
+	})
+	if err := defRepo.Create(ctx, &domain.NotificationDefinition{
+		Code:         "order",
+		Channels:     domain.StringList{"email:console", "sms"},
+		TemplateKeys: domain.StringList{"email:order-email", "sms:order-sms"},
+	}); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+
+	manager, err := New(Dependencies{
+		Definitions: defRepo,
+		Events:      eventRepo,
+		Messages:    memory.NewMessageRepository(),
+		Attempts:    memory.NewDeliveryRepository(),
+		Templates:   tplSvc,
+		Adapters:    adapters.NewRegistry(console.New(&logger.Nop{})),
+		Logger:      &logger.Nop{},
+		Config:      config.DispatcherConfig{Enabled: true, MaxAttempts: 1, MaxWorkers: 1},
+	})
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+
+	err = NewEvent("order").To("user@example.com").With("Name", "Ada").Channels("email:console").Send(ctx, manager)
+	if !errors.Is(err, ErrMissingFields) {
+		t.Fatalf("expected ErrMissingFields, got %v", err)
+	}
+	var missingErr *MissingFieldsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("expected *MissingFieldsError, got %T", err)
+	}
+	if got := missingErr.Missing["email"]; len(got) != 1 || got[0] != "OrderID" {
+		t.Fatalf("expected only OrderID missing for email, got %#v", missingErr.Missing)
+	}
+	events, _ := eventRepo.List(ctx, store.ListOptions{})
+	if events.Total != 0 {
+		t.Fatalf("expected no event enqueued on validation failure, got %d", events.Total)
+	}
+
+	// Manager.Send runs the same check for events that skip the builder.
+	err = manager.Send(ctx, Event{
+		DefinitionCode: "order",
+		Recipients:     []string{"user@example.com"},
+		Channels:       []string{"email:console"},
+		Context:        map[string]any{"Name": "Ada"},
+	})
+	if !errors.Is(err, ErrMissingFields) {
+		t.Fatalf("expected Manager.Send to reject missing fields, got %v", err)
+	}
+	if events, _ := eventRepo.List(ctx, store.ListOptions{}); events.Total != 0 {
+		t.Fatalf("expected no event persisted by Manager.Send, got %d", events.Total)
+	}
+
+	evt, err := NewEvent("order").To("user@example.com").WithContext(map[string]any{"Name": "Ada", "OrderID": "A-1"}).Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	// The sms template does not exist, so only email is validated.
+	if err := manager.Validate(ctx, evt); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	if err := manager.Validate(ctx, Event{DefinitionCode: "unknown", Recipients: []string{"x"}}); err == nil {
+		t.Fatalf("expected error for unknown definition")
+	}
+}
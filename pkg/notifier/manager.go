@@ -3,6 +3,9 @@ package notifier
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/goliatone/go-notifications/internal/dispatcher"
@@ -33,10 +36,14 @@ type Event struct {
 
 // Manager orchestrates event persistence + dispatcher invocation.
 type Manager struct {
-	dispatcher *dispatcher.Service
-	events     store.NotificationEventRepository
-	logger     logger.Logger
-	activity   activity.Hooks
+	dispatcher  *dispatcher.Service
+	events      store.NotificationEventRepository
+	messages    store.NotificationMessageRepository
+	attempts    store.DeliveryAttemptRepository
+	definitions store.NotificationDefinitionRepository
+	templates   *templates.Service
+	logger      logger.Logger
+	activity    activity.Hooks
 }
 
 // Dependencies bundles repositories/adapters required by the manager.
@@ -67,6 +74,8 @@ type Dependencies struct {
 
 var (
 	ErrMissingEventsRepository = errors.New("notifier: events repository is required")
+	// ErrShuttingDown is returned for events sent after Module.Shutdown.
+	ErrShuttingDown = dispatcher.ErrShuttingDown
 )
 
 // New constructs the notifier manager along with the dispatcher service.
@@ -111,18 +120,64 @@ func NewWithDispatcher(deps Dependencies, dispatcherSvc *dispatcher.Service) (*M
 	}
 
 	return &Manager{
-		dispatcher: dispatcherSvc,
-		events:     deps.Events,
-		logger:     deps.Logger,
-		activity:   deps.Activity,
+		dispatcher:  dispatcherSvc,
+		events:      deps.Events,
+		messages:    deps.Messages,
+		attempts:    deps.Attempts,
+		definitions: deps.Definitions,
+		templates:   deps.Templates,
+		logger:      deps.Logger,
+		activity:    deps.Activity,
 	}, nil
 }
 
-// Send persists a notification event and triggers dispatch immediately.
+// DeliveryResult is the outcome of one delivery (one recipient on one
+// channel) as reported by SendAndReturn.
+type DeliveryResult = dispatcher.DeliveryResult
+
+// Delivery result statuses.
+const (
+	DeliveryStatusDelivered = dispatcher.ResultStatusDelivered
+	DeliveryStatusFailed    = dispatcher.ResultStatusFailed
+	DeliveryStatusSkipped   = dispatcher.ResultStatusSkipped
+)
+
+// Send persists a notification event and triggers dispatch immediately. The
+// event context is first checked against the template schemas (see Validate);
+// a *MissingFieldsError is returned and nothing is persisted when it fails.
 func (m *Manager) Send(ctx context.Context, evt Event) error {
+	return m.send(ctx, evt, nil)
+}
+
+// SendAndReturn behaves like Send but also returns one DeliveryResult per
+// delivery, including the rendered subject and body, so callers can report
+// success or failure synchronously. Failed deliveries carry their error in
+// the result; the returned error is the same one Send would return. Results
+// follow the order of evt.Channels and evt.Recipients.
+func (m *Manager) SendAndReturn(ctx context.Context, evt Event) ([]DeliveryResult, error) {
+	var (
+		mu      sync.Mutex
+		results []DeliveryResult
+	)
+	err := m.send(ctx, evt, func(result DeliveryResult) {
+		mu.Lock()
+		results = append(results, result)
+		mu.Unlock()
+	})
+	sortDeliveryResults(results, evt)
+	return results, err
+}
+
+func (m *Manager) send(ctx context.Context, evt Event, onResult func(DeliveryResult)) error {
 	if err := validateEvent(evt); err != nil {
 		return err
 	}
+	if m.dispatcher.ShuttingDown() {
+		return ErrShuttingDown
+	}
+	if err := m.Validate(ctx, evt); err != nil {
+		return err
+	}
 	ctxData := evt.Context
 	if ctxData == nil {
 		ctxData = make(map[string]any)
@@ -160,7 +215,14 @@ func (m *Manager) Send(ctx context.Context, evt Event) error {
 	if err := m.dispatcher.Dispatch(ctx, record, dispatcher.DispatchOptions{
 		Channels: evt.Channels,
 		Locale:   evt.Locale,
+		OnResult: onResult,
 	}); err != nil {
+		// Shutdown began after the event was persisted: nothing was
+		// attempted, so leave it pending for a later run instead of
+		// failing it.
+		if errors.Is(err, dispatcher.ErrShuttingDown) {
+			return err
+		}
 		_ = m.events.UpdateStatus(ctx, record.ID, domain.EventStatusFailed)
 		m.activity.Notify(ctx, activity.Event{
 			Verb:           "notification.failed",
@@ -188,3 +250,30 @@ func validateEvent(evt Event) error {
 	}
 	return nil
 }
+
+// sortDeliveryResults puts results from concurrent workers into a stable
+// order: by the event's channel order, then its recipient order.
+func sortDeliveryResults(results []DeliveryResult, evt Event) {
+	channelRank := make(map[string]int, len(evt.Channels))
+	for i, channel := range evt.Channels {
+		channelType, _ := adapters.ParseChannel(channel)
+		if _, ok := channelRank[channelType]; !ok {
+			channelRank[channelType] = i
+		}
+	}
+	recipientRank := make(map[string]int, len(evt.Recipients))
+	for i, recipient := range evt.Recipients {
+		if _, ok := recipientRank[recipient]; !ok {
+			recipientRank[recipient] = i
+		}
+	}
+	slices.SortStableFunc(results, func(a, b DeliveryResult) int {
+		if c := channelRank[a.Channel] - channelRank[b.Channel]; c != 0 {
+			return c
+		}
+		if a.Channel != b.Channel {
+			return strings.Compare(a.Channel, b.Channel)
+		}
+		return recipientRank[a.Recipient] - recipientRank[b.Recipient]
+	})
+}
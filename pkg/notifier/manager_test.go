@@ -3,6 +3,7 @@ package notifier
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	i18n "github.com/goliatone/go-i18n"
@@ -387,6 +388,112 @@ func TestManagerSendRecordsFailures(t *testing.T) {
 	}
 }
 
+func TestManagerSendAndReturnReportsEachDelivery(t *testing.T) {
+	ctx := context.Background()
+	defRepo := memory.NewDefinitionRepository()
+	eventRepo := memory.NewEventRepository()
+	msgRepo := memory.NewMessageRepository()
+	attemptRepo := memory.NewDeliveryRepository()
+	tplRepo := memory.NewTemplateRepository()
+
+	tplSvc, err := templates.New(templates.Dependencies{
+		Repository: tplRepo,
+		Cache:      &cache.Nop{},
+		Logger:     &logger.Nop{},
+		Translator: newTestTranslator(t),
+	})
+	if err != nil {
+		t.Fatalf("template service: %v", err)
+	}
+
+	createTemplate(t, tplSvc, templates.TemplateInput{
+		Code:    "receipt-email",
+		Channel: "email",
+		Locale:  "en",
+		Subject: "Receipt for {{ Name }}",
+		Body:    "Thanks {{ Name }}",
+		Format:  "text/plain",
+	})
+	createTemplate(t, tplSvc, templates.TemplateInput{
+		Code:    "receipt-sms",
+		Channel: "sms",
+		Locale:  "en",
+		Subject: "Receipt",
+		Body:    "Thanks {{ Name }}",
+		Format:  "text/plain",
+	})
+
+	def := &domain.NotificationDefinition{
+		Code:         "receipt",
+		Channels:     domain.StringList{"email:console", "sms:failing"},
+		TemplateKeys: domain.StringList{"email:receipt-email", "sms:receipt-sms"},
+	}
+	if err := defRepo.Create(ctx, def); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+
+	registry := adapters.NewRegistry(
+		console.New(&logger.Nop{}),
+		&failingAdapter{
+			name:       "failing",
+			capability: adapters.Capability{Name: "failing", Channels: []string{"sms"}, Formats: []string{"text/plain"}},
+			failures:   1,
+		},
+	)
+
+	manager, err := New(Dependencies{
+		Definitions: defRepo,
+		Events:      eventRepo,
+		Messages:    msgRepo,
+		Attempts:    attemptRepo,
+		Templates:   tplSvc,
+		Adapters:    registry,
+		Logger:      &logger.Nop{},
+		Config: config.DispatcherConfig{
+			Enabled:              true,
+			MaxAttempts:          1,
+			MaxWorkers:           2,
+			EnvFallbackAllowlist: []string{"user@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+
+	results, err := manager.SendAndReturn(ctx, Event{
+		DefinitionCode: "receipt",
+		Recipients:     []string{"user@example.com"},
+		Channels:       []string{"email:console", "sms:failing"},
+		Context:        map[string]any{"Name": "Rosa"},
+		Locale:         "en",
+	})
+	if err == nil {
+		t.Fatalf("expected send error for the failed sms delivery")
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+
+	email := results[0]
+	if email.Channel != "email" || email.Provider != "console" || email.Status != DeliveryStatusDelivered {
+		t.Fatalf("unexpected email result: %+v", email)
+	}
+	if email.Subject != "Receipt for Rosa" || email.Body != "Thanks Rosa" || email.Err != nil {
+		t.Fatalf("expected rendered email content, got %+v", email)
+	}
+	if email.Recipient != "user@example.com" || email.MessageID == "" {
+		t.Fatalf("expected recipient and message id, got %+v", email)
+	}
+
+	sms := results[1]
+	if sms.Channel != "sms" || sms.Provider != "failing" || sms.Status != DeliveryStatusFailed {
+		t.Fatalf("unexpected sms result: %+v", sms)
+	}
+	if sms.Err == nil || !strings.Contains(sms.Err.Error(), "injected failure") {
+		t.Fatalf("expected sms error to be reported, got %v", sms.Err)
+	}
+}
+
 func TestManagerSkipsBlockedPreferences(t *testing.T) {
 	ctx := context.Background()
 	defRepo := memory.NewDefinitionRepository()
@@ -564,3 +671,66 @@ func newCatalog(locale string, entries map[string]string) *i18n.TranslationCatal
 	}
 	return catalog
 }
+
+// shutdownHook starts a dispatcher shutdown once the event has been persisted,
+// reproducing a shutdown that races a Send.
+type shutdownHook struct {
+	manager *Manager
+}
+
+func (h *shutdownHook) Notify(ctx context.Context, evt activity.Event) {
+	if evt.Verb == "notification.created" {
+		_ = h.manager.dispatcher.Shutdown(ctx)
+	}
+}
+
+func TestManagerSendLeavesEventPendingOnShutdownRace(t *testing.T) {
+	ctx := context.Background()
+	defRepo := memory.NewDefinitionRepository()
+	eventRepo := memory.NewEventRepository()
+	if err := defRepo.Create(ctx, &domain.NotificationDefinition{
+		Code:     "welcome",
+		Channels: domain.StringList{"email:console"},
+	}); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+
+	tplSvc, err := templates.New(templates.Dependencies{
+		Repository: memory.NewTemplateRepository(),
+		Cache:      &cache.Nop{},
+		Logger:     &logger.Nop{},
+		Translator: newTestTranslator(t),
+	})
+	if err != nil {
+		t.Fatalf("template service: %v", err)
+	}
+
+	hook := &shutdownHook{}
+	manager, err := New(Dependencies{
+		Definitions: defRepo,
+		Events:      eventRepo,
+		Messages:    memory.NewMessageRepository(),
+		Attempts:    memory.NewDeliveryRepository(),
+		Templates:   tplSvc,
+		Adapters:    adapters.NewRegistry(console.New(&logger.Nop{})),
+		Logger:      &logger.Nop{},
+		Activity:    activity.Hooks{hook},
+		Config:      config.DispatcherConfig{Enabled: true, MaxAttempts: 1, MaxWorkers: 1},
+	})
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+	hook.manager = manager
+
+	err = manager.Send(ctx, Event{DefinitionCode: "welcome", Recipients: []string{"user@example.com"}})
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("expected ErrShuttingDown, got %v", err)
+	}
+	events, err := eventRepo.List(ctx, store.ListOptions{})
+	if err != nil || events.Total != 1 {
+		t.Fatalf("expected the persisted event, got %+v (%v)", events, err)
+	}
+	if status := events.Items[0].Status; status != domain.EventStatusPending {
+		t.Fatalf("expected event left pending, got %q", status)
+	}
+}
@@ -1,6 +1,9 @@
 package notifier
 
 import (
+	"context"
+	"sync"
+
 	i18n "github.com/goliatone/go-i18n"
 	"github.com/goliatone/go-notifications/internal/di"
 	"github.com/goliatone/go-notifications/pkg/activity"
@@ -39,33 +42,53 @@ type ModuleOptions struct {
 	Secrets      secrets.Resolver
 	Backoff      retry.Backoff
 	Activity     activity.Hooks
+	// Strategies sets provider selection per channel, overriding
+	// Config.Dispatcher.Selection for the same channel.
+	Strategies map[string]adapters.SelectionStrategy
+	// Groups resolves recipient group memberships for group-level preferences
+	// and group-scoped secrets.
+	Groups preferences.GroupResolver
+	// Subscriptions resolves recipient subscriptions for required-subscription
+	// filters; without it the dispatcher uses event.Context["subscriptions"].
+	Subscriptions preferences.SubscriptionResolver
+	// LocaleCatalog supplies go-i18n locale metadata; the format_direction
+	// template helper reads its "rtl" flag.
+	LocaleCatalog *i18n.LocaleCatalog
 }
 
 // Module bundles the container and exposes high-level accessors.
 type Module struct {
 	container *di.Container
 	manager   *Manager
+
+	stopOnce   sync.Once
+	cancel     context.CancelFunc
+	background sync.WaitGroup
 }
 
 // NewModule assembles repositories, services, dispatcher, manager, and commands.
 func NewModule(opts ModuleOptions) (*Module, error) {
 	container, err := di.New(di.Options{
-		Config:       opts.Config,
-		Storage:      opts.Storage,
-		Logger:       opts.Logger,
-		Cache:        opts.Cache,
-		Translator:   opts.Translator,
-		Fallbacks:    opts.Fallbacks,
-		Queue:        opts.Queue,
-		Broadcaster:  opts.Broadcaster,
-		Adapters:     opts.Adapters,
-		LinkBuilder:  opts.LinkBuilder,
-		LinkStore:    opts.LinkStore,
-		LinkObserver: opts.LinkObserver,
-		LinkPolicy:   opts.LinkPolicy,
-		Secrets:      opts.Secrets,
-		Backoff:      opts.Backoff,
-		Activity:     opts.Activity,
+		Config:        opts.Config,
+		Storage:       opts.Storage,
+		Logger:        opts.Logger,
+		Cache:         opts.Cache,
+		Translator:    opts.Translator,
+		Fallbacks:     opts.Fallbacks,
+		LocaleCatalog: opts.LocaleCatalog,
+		Queue:         opts.Queue,
+		Broadcaster:   opts.Broadcaster,
+		Adapters:      opts.Adapters,
+		Strategies:    opts.Strategies,
+		LinkBuilder:   opts.LinkBuilder,
+		LinkStore:     opts.LinkStore,
+		LinkObserver:  opts.LinkObserver,
+		LinkPolicy:    opts.LinkPolicy,
+		Secrets:       opts.Secrets,
+		Backoff:       opts.Backoff,
+		Activity:      opts.Activity,
+		Groups:        opts.Groups,
+		Subscriptions: opts.Subscriptions,
 	})
 	if err != nil {
 		return nil, err
@@ -86,7 +109,55 @@ func NewModule(opts ModuleOptions) (*Module, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Module{container: container, manager: manager}, nil
+	module := &Module{container: container, manager: manager}
+	module.startBackground()
+	return module, nil
+}
+
+// startBackground launches optional periodic jobs configured on the module.
+func (m *Module) startBackground() {
+	interval := m.container.Config.Inbox.SnoozeReleaseInterval
+	if interval <= 0 || m.container.Inbox == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.background.Go(func() {
+		m.container.Inbox.RunReleaser(ctx, interval)
+	})
+}
+
+// Shutdown stops the module gracefully: new events are rejected with
+// ErrShuttingDown, in-flight dispatches are given until ctx ends to finish,
+// and background jobs are stopped. Background jobs are stopped even when ctx
+// ends first, in which case ctx.Err() is returned.
+func (m *Module) Shutdown(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	var err error
+	if m.container.Dispatcher != nil {
+		err = m.container.Dispatcher.Shutdown(ctx)
+	}
+	if closeErr := m.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close stops background jobs started by the module and waits for them to exit.
+// It does not wait for in-flight dispatches; use Shutdown for that.
+func (m *Module) Close() error {
+	if m == nil {
+		return nil
+	}
+	m.stopOnce.Do(func() {
+		if m.cancel != nil {
+			m.cancel()
+		}
+		m.background.Wait()
+	})
+	return nil
 }
 
 // Manager returns the notifier manager.
@@ -145,6 +216,15 @@ func (m *Module) AdapterRegistry() *adapters.Registry {
 	return m.container.Adapters
 }
 
+// DeliveryStatus returns the updater web handlers call with asynchronous
+// provider delivery reports, such as Twilio status callbacks.
+func (m *Module) DeliveryStatus() adapters.DeliveryStatusUpdater {
+	if m == nil || m.container == nil || m.container.Dispatcher == nil {
+		return nil
+	}
+	return m.container.Dispatcher
+}
+
 // Config returns the effective module configuration.
 func (m *Module) Config() config.Config {
 	if m == nil || m.container == nil {
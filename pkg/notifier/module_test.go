@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	i18n "github.com/goliatone/go-i18n"
@@ -29,6 +31,30 @@ func TestModuleConstruction(t *testing.T) {
 	}
 }
 
+func TestModuleShutdownRejectsNewEvents(t *testing.T) {
+	module, err := NewModule(ModuleOptions{
+		Translator: moduleTranslator(t),
+		Logger:     &logger.Nop{},
+		Storage:    storage.NewMemoryProviders(),
+	})
+	if err != nil {
+		t.Fatalf("module: %v", err)
+	}
+	if err := module.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	err = module.Manager().Send(context.Background(), Event{
+		DefinitionCode: "welcome",
+		Recipients:     []string{"user@example.com"},
+	})
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("expected ErrShuttingDown after shutdown, got %v", err)
+	}
+	if err := module.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second shutdown: %v", err)
+	}
+}
+
 func moduleTranslator(t *testing.T) i18n.Translator {
 	t.Helper()
 	translations := i18n.Translations{
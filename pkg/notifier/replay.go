@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goliatone/go-notifications/internal/dispatcher"
+	"github.com/goliatone/go-notifications/pkg/activity"
+	"github.com/goliatone/go-notifications/pkg/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEventNotReplayable is returned by Replay for events that have not
+	// been dispatched yet (pending or scheduled).
+	ErrEventNotReplayable = errors.New("notifier: event has not been dispatched yet")
+	// ErrMissingMessagesRepository is returned by Replay with FailedOnly when
+	// no message repository was configured.
+	ErrMissingMessagesRepository = errors.New("notifier: messages repository is required")
+)
+
+// DispatchOptions controls how Replay re-dispatches a stored event.
+type DispatchOptions struct {
+	// Channels overrides the definition channels, like Event.Channels.
+	Channels []string
+	// Locale overrides the render locale, like Event.Locale.
+	Locale string
+	// FailedOnly limits the replay to channel/recipient pairs that were
+	// never delivered, as recorded by the stored messages and attempts.
+	FailedOnly bool
+}
+
+// Replay loads a stored event and dispatches it again with its original
+// context. Events still pending or scheduled are rejected with
+// ErrEventNotReplayable, since the scheduler has yet to send them. With
+// opts.FailedOnly, recipients that already received the event on a channel
+// are left out.
+func (m *Manager) Replay(ctx context.Context, eventID uuid.UUID, opts DispatchOptions) error {
+	if m.dispatcher.ShuttingDown() {
+		return ErrShuttingDown
+	}
+	record, err := m.events.GetByID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("notifier: load event: %w", err)
+	}
+	switch record.Status {
+	case domain.EventStatusPending, domain.EventStatusScheduled:
+		return fmt.Errorf("%w: %s is %s", ErrEventNotReplayable, record.ID, record.Status)
+	}
+
+	dispatchOpts := dispatcher.DispatchOptions{
+		Channels: opts.Channels,
+		Locale:   opts.Locale,
+	}
+	if opts.FailedOnly {
+		delivered, err := m.deliveredPairs(ctx, record.ID)
+		if err != nil {
+			return err
+		}
+		if len(delivered) > 0 {
+			dispatchOpts.Include = func(channelType, recipient string) bool {
+				return !delivered[deliveryPair{channel: channelType, recipient: recipient}]
+			}
+		}
+	}
+
+	m.activity.Notify(ctx, activity.Event{
+		Verb:           "notification.replayed",
+		ActorID:        record.ActorID,
+		TenantID:       record.TenantID,
+		ObjectType:     "notification_event",
+		ObjectID:       record.ID.String(),
+		DefinitionCode: record.DefinitionCode,
+		Recipients:     []string(record.Recipients),
+		Metadata: map[string]any{
+			"previous_status": record.Status,
+			"channels":        opts.Channels,
+			"failed_only":     opts.FailedOnly,
+		},
+	})
+	if err := m.dispatcher.Dispatch(ctx, record, dispatchOpts); err != nil {
+		m.activity.Notify(ctx, activity.Event{
+			Verb:           "notification.failed",
+			ActorID:        record.ActorID,
+			TenantID:       record.TenantID,
+			ObjectType:     "notification_event",
+			ObjectID:       record.ID.String(),
+			DefinitionCode: record.DefinitionCode,
+			Recipients:     []string(record.Recipients),
+			Metadata: map[string]any{
+				"error": err.Error(),
+			},
+		})
+		return err
+	}
+	return nil
+}
+
+type deliveryPair struct {
+	channel   string
+	recipient string
+}
+
+// deliveredPairs returns the channel/recipient pairs with a delivered
+// message for the event. A message counts as delivered when its status says
+// so, or when it is still pending but an attempt succeeded (the status
+// update was lost).
+func (m *Manager) deliveredPairs(ctx context.Context, eventID uuid.UUID) (map[deliveryPair]bool, error) {
+	if m.messages == nil {
+		return nil, ErrMissingMessagesRepository
+	}
+	messages, err := m.messages.ListByEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("notifier: list event messages: %w", err)
+	}
+	delivered := make(map[deliveryPair]bool)
+	for _, message := range messages {
+		ok, err := m.messageDelivered(ctx, message)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			delivered[deliveryPair{channel: message.Channel, recipient: message.Receiver}] = true
+		}
+	}
+	return delivered, nil
+}
+
+func (m *Manager) messageDelivered(ctx context.Context, message domain.NotificationMessage) (bool, error) {
+	switch message.Status {
+	case domain.MessageStatusDelivered:
+		return true, nil
+	case domain.MessageStatusPending:
+	default:
+		return false, nil
+	}
+	if m.attempts == nil {
+		return false, nil
+	}
+	attempts, err := m.attempts.ListByMessage(ctx, message.ID)
+	if err != nil {
+		return false, fmt.Errorf("notifier: list message attempts: %w", err)
+	}
+	for _, attempt := range attempts {
+		if attempt.Status == domain.AttemptStatusSucceeded {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,173 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goliatone/go-notifications/internal/storage/memory"
+	"github.com/goliatone/go-notifications/pkg/adapters"
+	"github.com/goliatone/go-notifications/pkg/adapters/console"
+	"github.com/goliatone/go-notifications/pkg/config"
+	"github.com/goliatone/go-notifications/pkg/domain"
+	"github.com/goliatone/go-notifications/pkg/interfaces/cache"
+	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
+	"github.com/goliatone/go-notifications/pkg/interfaces/store"
+	"github.com/goliatone/go-notifications/pkg/templates"
+	"github.com/google/uuid"
+)
+
+func TestManagerReplayFailedOnly(t *testing.T) {
+	ctx := context.Background()
+	eventRepo := memory.NewEventRepository()
+	msgRepo := memory.NewMessageRepository()
+	manager := newReplayManager(t, eventRepo, msgRepo)
+
+	err := manager.Send(ctx, Event{
+		DefinitionCode: "receipt",
+		Recipients:     []string{"user@example.com"},
+		Context:        map[string]any{"Name": "Rosa"},
+		Locale:         "en",
+	})
+	if err == nil {
+		t.Fatalf("expected the sms delivery to fail")
+	}
+	events, err := eventRepo.List(ctx, store.ListOptions{})
+	if err != nil || len(events.Items) != 1 {
+		t.Fatalf("expected 1 event, got %v (%v)", events.Items, err)
+	}
+	eventID := events.Items[0].ID
+	before, err := msgRepo.ListByEvent(ctx, eventID)
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	seen := make(map[uuid.UUID]bool, len(before))
+	for _, msg := range before {
+		seen[msg.ID] = true
+	}
+
+	if err := manager.Replay(ctx, eventID, DispatchOptions{FailedOnly: true}); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	record, err := eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		t.Fatalf("get event: %v", err)
+	}
+	if record.Status != domain.EventStatusProcessed {
+		t.Fatalf("expected replayed event processed, got %s", record.Status)
+	}
+
+	messages, err := msgRepo.ListByEvent(ctx, eventID)
+	if err != nil {
+		t.Fatalf("list messages: %v", err)
+	}
+	var replayed []domain.NotificationMessage
+	for _, msg := range messages {
+		if !seen[msg.ID] {
+			replayed = append(replayed, msg)
+		}
+	}
+	if len(replayed) != 1 || replayed[0].Channel != "sms" || replayed[0].Status != domain.MessageStatusDelivered {
+		t.Fatalf("expected only the sms delivery to be replayed, got %+v", replayed)
+	}
+
+	counts := map[string]int{}
+	for _, msg := range messages {
+		counts[msg.Channel+":"+msg.Status]++
+	}
+	want := map[string]int{"email:delivered": 1, "sms:failed": 1, "sms:delivered": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("expected messages %v, got %v", want, counts)
+	}
+	for key, n := range want {
+		if counts[key] != n {
+			t.Fatalf("expected messages %v, got %v", want, counts)
+		}
+	}
+}
+
+func TestManagerReplayRejectsUndispatchedEvents(t *testing.T) {
+	ctx := context.Background()
+	eventRepo := memory.NewEventRepository()
+	manager := newReplayManager(t, eventRepo, memory.NewMessageRepository())
+
+	for _, status := range []string{domain.EventStatusPending, domain.EventStatusScheduled} {
+		record := &domain.NotificationEvent{
+			DefinitionCode: "receipt",
+			Recipients:     domain.StringList{"user@example.com"},
+			Status:         status,
+		}
+		if err := eventRepo.Create(ctx, record); err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+		err := manager.Replay(ctx, record.ID, DispatchOptions{})
+		if !errors.Is(err, ErrEventNotReplayable) {
+			t.Fatalf("expected ErrEventNotReplayable for %s event, got %v", status, err)
+		}
+	}
+}
+
+func newReplayManager(t *testing.T, eventRepo *memory.EventRepository, msgRepo *memory.MessageRepository) *Manager {
+	t.Helper()
+	ctx := context.Background()
+	defRepo := memory.NewDefinitionRepository()
+
+	tplSvc, err := templates.New(templates.Dependencies{
+		Repository: memory.NewTemplateRepository(),
+		Cache:      &cache.Nop{},
+		Logger:     &logger.Nop{},
+		Translator: newTestTranslator(t),
+	})
+	if err != nil {
+		t.Fatalf("template service: %v", err)
+	}
+	for _, channel := range []string{"email", "sms"} {
+		createTemplate(t, tplSvc, templates.TemplateInput{
+			Code:    "receipt-" + channel,
+			Channel: channel,
+			Locale:  "en",
+			Subject: "Receipt",
+			Body:    "Thanks {{ Name }}",
+			Format:  "text/plain",
+		})
+	}
+
+	def := &domain.NotificationDefinition{
+		Code:         "receipt",
+		Channels:     domain.StringList{"email:console", "sms:failing"},
+		TemplateKeys: domain.StringList{"email:receipt-email", "sms:receipt-sms"},
+	}
+	if err := defRepo.Create(ctx, def); err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+
+	registry := adapters.NewRegistry(
+		console.New(&logger.Nop{}),
+		&failingAdapter{
+			name:       "failing",
+			capability: adapters.Capability{Name: "failing", Channels: []string{"sms"}, Formats: []string{"text/plain"}},
+			failures:   1,
+		},
+	)
+
+	manager, err := New(Dependencies{
+		Definitions: defRepo,
+		Events:      eventRepo,
+		Messages:    msgRepo,
+		Attempts:    memory.NewDeliveryRepository(),
+		Templates:   tplSvc,
+		Adapters:    registry,
+		Logger:      &logger.Nop{},
+		Config: config.DispatcherConfig{
+			Enabled:              true,
+			MaxAttempts:          1,
+			MaxWorkers:           2,
+			EnvFallbackAllowlist: []string{"user@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("manager: %v", err)
+	}
+	return manager
+}
@@ -1,6 +1,9 @@
 package onready
 
 import (
+	"strings"
+
+	"github.com/goliatone/go-notifications/pkg/adapters"
 	"github.com/goliatone/go-notifications/pkg/domain"
 )
 
@@ -15,7 +18,7 @@ const (
 
 var templateSchema = domain.TemplateSchema{
 	Required: []string{"file_name", "format", "url", "expires_at"},
-	Optional: []string{"rows", "parts", "manifest_url", "message"},
+	Optional: []string{"rows", "parts", "manifest_url", "part_urls", "message"},
 }
 
 // Definition returns the default export-ready notification definition with channel mappings.
@@ -76,6 +79,48 @@ func Templates() []domain.NotificationTemplate {
 	}
 }
 
+// TemplateCodeFor returns the default template code for a channel type, e.g.
+// export.ready.sms for "sms". Email and in-app map to EmailTemplateCode and
+// InAppTemplateCode.
+func TemplateCodeFor(channel string) string {
+	chType, _ := adapters.ParseChannel(channel)
+	return DefinitionCode + "." + strings.ReplaceAll(chType, "-", "")
+}
+
+// TemplateFor returns the default export-ready template for a channel
+// ("chat:slack" resolves to the chat template). Email and in-app use the
+// Templates() variants; chat channels get a markdown body, sms/whatsapp/push
+// a one-line summary with the link, and anything else a plain-text body.
+func TemplateFor(channel string) domain.NotificationTemplate {
+	chType, _ := adapters.ParseChannel(channel)
+	if tpl := baseTemplateFor(Templates(), chType); tpl.Code != "" {
+		return tpl
+	}
+	tpl := domain.NotificationTemplate{
+		Code:        TemplateCodeFor(chType),
+		Channel:     chType,
+		Locale:      "en",
+		Subject:     `{{ t(locale, "export.ready.title", file_name) }}`,
+		Body:        emailBody,
+		Description: "Template for export-ready notifications on " + chType,
+		Format:      "text/plain",
+		Schema:      templateSchema,
+		Metadata: domain.JSONMap{
+			"category":  "export",
+			"cta_label": "Download",
+		},
+	}
+	switch chType {
+	case "chat", "slack", "teams", "discord", "telegram":
+		tpl.Body = inAppBody
+		tpl.Format = "text/markdown"
+		tpl.Metadata["cta_label"] = "Open"
+	case "sms", "whatsapp", "push":
+		tpl.Body = shortTextBody
+	}
+	return tpl
+}
+
 // Schema returns the template schema describing required/optional placeholders.
 func Schema() domain.TemplateSchema {
 	return templateSchema
@@ -85,9 +130,7 @@ const emailBody = `
 {{ t(locale, "export.ready.body.intro", file_name, format) }}
 {% if cta_label %}{{ cta_label }}{% else %}{{ t(locale, "export.ready.body.link_label") }}{% endif %}: {% if action_url %}{{ action_url }}{% else %}{{ url }}{% endif %}
 {{ t(locale, "export.ready.body.expires", expires_at) }}
-{% if rows %}{{ t(locale, "export.ready.body.rows", rows) }}{% endif %}
-{% if parts %}{{ t(locale, "export.ready.body.parts", parts) }}{% endif %}
-{% if manifest_url %}{{ t(locale, "export.ready.body.manifest", manifest_url) }}{% endif %}
+` + partsSummary + `
 {% if message %}{{ t(locale, "export.ready.body.message", message) }}{% endif %}
 `
 
@@ -96,7 +139,15 @@ const inAppBody = `
 {{ t(locale, "export.ready.body.expires", expires_at) }}
 {% if message %}{{ t(locale, "export.ready.body.message", message) }}{% endif %}
 {% if cta_label %}{{ cta_label }}{% else %}{{ t(locale, "export.ready.body.link_label") }}{% endif %}: {% if action_url %}{{ action_url }}{% else %}{{ url }}{% endif %}
-{% if manifest_url %}{{ t(locale, "export.ready.body.manifest", manifest_url) }}{% endif %}
-{% if rows %}{{ t(locale, "export.ready.body.rows", rows) }}{% endif %}
-{% if parts %}{{ t(locale, "export.ready.body.parts", parts) }}{% endif %}
+` + partsSummary + `
 `
+
+// partsSummary renders "3 parts, 1200 rows" plus a link per part for
+// multi-part exports, and the plain row count for single-part ones. The
+// manifest link is shown in both cases.
+const partsSummary = `{% if parts > 1 %}{% if rows %}{{ t(locale, "export.ready.body.summary_rows", parts, rows) }}{% else %}{{ t(locale, "export.ready.body.summary", parts) }}{% endif %}
+{% for part_url in part_urls %}{{ t(locale, "export.ready.body.part", forloop.Counter, part_url) }}
+{% endfor %}{% elif rows %}{{ t(locale, "export.ready.body.rows", rows) }}{% endif %}
+{% if manifest_url %}{{ t(locale, "export.ready.body.manifest", manifest_url) }}{% endif %}`
+
+const shortTextBody = `{{ t(locale, "export.ready.title", file_name) }} {% if action_url %}{{ action_url }}{% else %}{{ url }}{% endif %}`
@@ -3,6 +3,7 @@ package onready
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -104,8 +105,8 @@ func TestTemplatesRenderEmailAndInApp(t *testing.T) {
 	if !strings.Contains(email.Body, payload["manifest_url"].(string)) {
 		t.Fatalf("email body missing manifest URL: %s", email.Body)
 	}
-	if !strings.Contains(email.Body, "Rows: 1200") || !strings.Contains(email.Body, "Parts: 3") {
-		t.Fatalf("email body missing counts: %s", email.Body)
+	if !strings.Contains(email.Body, "3 parts, 1200 rows") {
+		t.Fatalf("email body missing parts summary: %s", email.Body)
 	}
 
 	inapp, err := svc.Render(ctx, pkgtemplates.RenderRequest{
@@ -130,6 +131,90 @@ func TestTemplatesRenderEmailAndInApp(t *testing.T) {
 	}
 }
 
+func TestTemplatesRenderMultiPartSummary(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	for _, tpl := range Templates() {
+		copy := tpl
+		if err := repo.Create(ctx, &copy); err != nil {
+			t.Fatalf("seed template: %v", err)
+		}
+	}
+	translator := newTranslator(t)
+	svc, err := pkgtemplates.New(pkgtemplates.Dependencies{
+		Repository:    repo,
+		Cache:         &cache.Nop{},
+		Logger:        &logger.Nop{},
+		Translator:    translator,
+		Fallbacks:     i18n.NewStaticFallbackResolver(),
+		DefaultLocale: "en",
+	})
+	if err != nil {
+		t.Fatalf("template service: %v", err)
+	}
+
+	payloadWith := func(parts int, partURLs []string) map[string]any {
+		payload := map[string]any{
+			"file_name":    "orders.csv",
+			"format":       "csv",
+			"url":          "https://example.com/exports/orders.csv",
+			"expires_at":   "2024-05-01T00:00:00Z",
+			"rows":         1200,
+			"parts":        parts,
+			"manifest_url": "https://example.com/exports/manifest.json",
+		}
+		if partURLs != nil {
+			payload["part_urls"] = partURLs
+		}
+		return payload
+	}
+	partURLs := []string{
+		"https://example.com/exports/orders-1.csv",
+		"https://example.com/exports/orders-2.csv",
+		"https://example.com/exports/orders-3.csv",
+	}
+
+	for _, tc := range []struct {
+		code    string
+		channel string
+	}{
+		{EmailTemplateCode, "email"},
+		{InAppTemplateCode, "in-app"},
+	} {
+		single, err := svc.Render(ctx, pkgtemplates.RenderRequest{
+			Code: tc.code, Channel: tc.channel, Locale: "en", Data: payloadWith(1, nil),
+		})
+		if err != nil {
+			t.Fatalf("render %s single-part: %v", tc.channel, err)
+		}
+		multi, err := svc.Render(ctx, pkgtemplates.RenderRequest{
+			Code: tc.code, Channel: tc.channel, Locale: "en", Data: payloadWith(3, partURLs),
+		})
+		if err != nil {
+			t.Fatalf("render %s multi-part: %v", tc.channel, err)
+		}
+		if single.Body == multi.Body {
+			t.Fatalf("%s: expected single and multi-part bodies to differ", tc.channel)
+		}
+		if !strings.Contains(single.Body, "Rows: 1200") || strings.Contains(single.Body, "parts") {
+			t.Fatalf("%s: unexpected single-part body: %s", tc.channel, single.Body)
+		}
+		if !strings.Contains(multi.Body, "3 parts, 1200 rows") {
+			t.Fatalf("%s: multi-part body missing summary: %s", tc.channel, multi.Body)
+		}
+		for i, link := range partURLs {
+			if !strings.Contains(multi.Body, fmt.Sprintf("Part %d: %s", i+1, link)) {
+				t.Fatalf("%s: multi-part body missing part %d link: %s", tc.channel, i+1, multi.Body)
+			}
+		}
+		for _, body := range []string{single.Body, multi.Body} {
+			if !strings.Contains(body, "Manifest: https://example.com/exports/manifest.json") {
+				t.Fatalf("%s: body missing manifest link: %s", tc.channel, body)
+			}
+		}
+	}
+}
+
 func TestTemplatesRespectChannelOverrides(t *testing.T) {
 	ctx := context.Background()
 	repo := memstore.NewTemplateRepository()
@@ -32,6 +32,9 @@ type OnReadyEvent struct {
 	ManifestURL string
 	Message     string
 
+	// PartURLs link each part of a multi-part export (Parts > 1), in order.
+	PartURLs []string
+
 	// Attachments are forwarded to adapters that support files or media URLs.
 	Attachments []adapters.Attachment
 
@@ -88,6 +91,9 @@ func (n *notifierImpl) Send(ctx context.Context, evt OnReadyEvent) error {
 	if evt.ManifestURL != "" {
 		payload["manifest_url"] = evt.ManifestURL
 	}
+	if len(evt.PartURLs) > 0 {
+		payload["part_urls"] = evt.PartURLs
+	}
 	if evt.Message != "" {
 		payload["message"] = evt.Message
 	}
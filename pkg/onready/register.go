@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/goliatone/go-notifications/pkg/adapters"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
 	"github.com/goliatone/go-notifications/pkg/templates"
@@ -24,7 +25,13 @@ type Options struct {
 	Namespace             string
 	DefinitionName        string
 	DefinitionDescription string
-	Channels              []string
+	// Channels selects the channels to deliver on and install templates for
+	// (defaults to email and in-app). Channels sharing a type, such as
+	// chat:slack and chat:teams, share one template.
+	Channels []string
+	// ChannelTemplates overrides the default template per channel type. For
+	// email and in-app these take precedence over the Email*/InApp* fields.
+	ChannelTemplates map[string]ChannelTemplate
 
 	EmailSubject   string
 	EmailBody      string
@@ -38,6 +45,22 @@ type Options struct {
 	TemplateMeta   domain.JSONMap
 }
 
+// ChannelTemplate customizes the template installed for one channel type.
+// Empty fields keep the defaults from TemplateFor.
+type ChannelTemplate struct {
+	Subject  string
+	Body     string
+	Format   string
+	CTALabel string
+	Icon     string
+}
+
+// TemplateRef identifies an installed template.
+type TemplateRef struct {
+	Code string
+	ID   string
+}
+
 // Result exposes the registered assets for callers.
 type Result struct {
 	DefinitionCode string
@@ -46,6 +69,8 @@ type Result struct {
 	EmailID        string
 	InAppCode      string
 	InAppID        string
+	// Templates lists every installed template keyed by channel type.
+	Templates map[string]TemplateRef
 }
 
 // Register installs (or updates) the export-ready definition and templates.
@@ -67,23 +92,29 @@ func Register(ctx context.Context, deps Dependencies, opts Options) (Result, err
 		return Result{}, err
 	}
 
-	installedEmail, err := upsertTemplate(ctx, deps.Templates, emailTemplateFor(tpls))
-	if err != nil {
-		return Result{}, err
-	}
-	installedInApp, err := upsertTemplate(ctx, deps.Templates, inAppTemplateFor(tpls))
-	if err != nil {
-		return Result{}, err
-	}
-
-	return Result{
+	result := Result{
 		DefinitionCode: installedDef.Code,
 		DefinitionID:   installedDef.ID.String(),
-		EmailCode:      codeOrEmpty(installedEmail),
-		EmailID:        idOrEmpty(installedEmail),
-		InAppCode:      codeOrEmpty(installedInApp),
-		InAppID:        idOrEmpty(installedInApp),
-	}, nil
+		Templates:      make(map[string]TemplateRef, len(tpls)),
+	}
+	for _, tpl := range tpls {
+		installed, err := upsertTemplate(ctx, deps.Templates, tpl)
+		if err != nil {
+			return Result{}, err
+		}
+		if installed == nil {
+			continue
+		}
+		ref := TemplateRef{Code: codeOrEmpty(installed), ID: idOrEmpty(installed)}
+		result.Templates[tpl.Channel] = ref
+		switch tpl.Channel {
+		case "email":
+			result.EmailCode, result.EmailID = ref.Code, ref.ID
+		case "in-app":
+			result.InAppCode, result.InAppID = ref.Code, ref.ID
+		}
+	}
+	return result, nil
 }
 
 func buildDefinition(opts Options) domain.NotificationDefinition {
@@ -109,44 +140,72 @@ func buildDefinition(opts Options) domain.NotificationDefinition {
 }
 
 func buildTemplates(opts Options) []domain.NotificationTemplate {
-	base := Templates()
-	email := baseTemplateFor(base, "email")
-	inapp := baseTemplateFor(base, "in-app")
+	channels := normalizeChannels(opts.Channels)
+	if len(channels) == 0 {
+		channels = Definition().Channels
+	}
 
-	if opts.Namespace != "" {
-		email.Code = namespaced(opts.Namespace, email.Code)
-		inapp.Code = namespaced(opts.Namespace, inapp.Code)
+	seen := make(map[string]struct{}, len(channels))
+	out := make([]domain.NotificationTemplate, 0, len(channels))
+	for _, channel := range channels {
+		chType, _ := adapters.ParseChannel(channel)
+		if _, ok := seen[chType]; ok || chType == "" {
+			continue
+		}
+		seen[chType] = struct{}{}
+		out = append(out, buildTemplate(opts, chType))
 	}
+	return out
+}
 
-	if opts.EmailSubject != "" {
-		email.Subject = opts.EmailSubject
+func buildTemplate(opts Options, chType string) domain.NotificationTemplate {
+	tpl := TemplateFor(chType)
+	defaultLabel, _ := tpl.Metadata["cta_label"].(string)
+
+	custom := ChannelTemplate{}
+	switch chType {
+	case "email":
+		custom = ChannelTemplate{Subject: opts.EmailSubject, Body: opts.EmailBody, CTALabel: opts.EmailCTALabel, Icon: opts.EmailIcon}
+	case "in-app":
+		custom = ChannelTemplate{Subject: opts.InAppSubject, Body: opts.InAppBody, CTALabel: opts.InAppCTALabel, Icon: opts.InAppIcon}
 	}
-	if opts.EmailBody != "" {
-		email.Body = opts.EmailBody
+	if override, ok := channelTemplateFor(opts.ChannelTemplates, chType); ok {
+		custom.Subject = defaultValue(override.Subject, custom.Subject)
+		custom.Body = defaultValue(override.Body, custom.Body)
+		custom.Format = defaultValue(override.Format, custom.Format)
+		custom.CTALabel = defaultValue(override.CTALabel, custom.CTALabel)
+		custom.Icon = defaultValue(override.Icon, custom.Icon)
 	}
-	email.Metadata = mergeJSON(email.Metadata, opts.TemplateMeta)
-	if label := defaultValue(opts.EmailCTALabel, "Download"); label != "" {
-		email.Metadata["cta_label"] = label
+
+	if opts.Namespace != "" {
+		tpl.Code = namespaced(opts.Namespace, tpl.Code)
 	}
-	if opts.EmailIcon != "" {
-		email.Metadata["icon"] = opts.EmailIcon
+	if custom.Subject != "" {
+		tpl.Subject = custom.Subject
 	}
-
-	if opts.InAppSubject != "" {
-		inapp.Subject = opts.InAppSubject
+	if custom.Body != "" {
+		tpl.Body = custom.Body
 	}
-	if opts.InAppBody != "" {
-		inapp.Body = opts.InAppBody
+	if custom.Format != "" {
+		tpl.Format = custom.Format
 	}
-	inapp.Metadata = mergeJSON(inapp.Metadata, opts.TemplateMeta)
-	if label := defaultValue(opts.InAppCTALabel, "Open"); label != "" {
-		inapp.Metadata["cta_label"] = label
+	tpl.Metadata = mergeJSON(tpl.Metadata, opts.TemplateMeta)
+	if label := defaultValue(custom.CTALabel, defaultLabel); label != "" {
+		tpl.Metadata["cta_label"] = label
 	}
-	if opts.InAppIcon != "" {
-		inapp.Metadata["icon"] = opts.InAppIcon
+	if custom.Icon != "" {
+		tpl.Metadata["icon"] = custom.Icon
 	}
+	return tpl
+}
 
-	return []domain.NotificationTemplate{email, inapp}
+func channelTemplateFor(overrides map[string]ChannelTemplate, chType string) (ChannelTemplate, bool) {
+	for channel, override := range overrides {
+		if key, _ := adapters.ParseChannel(channel); key == chType {
+			return override, true
+		}
+	}
+	return ChannelTemplate{}, false
 }
 
 func upsertDefinition(ctx context.Context, repo store.NotificationDefinitionRepository, desired domain.NotificationDefinition) (*domain.NotificationDefinition, error) {
@@ -403,7 +462,8 @@ func filterTemplatesByChannels(tpls []domain.NotificationTemplate, channels []st
 	}
 	chSet := make(map[string]struct{}, len(channels))
 	for _, ch := range channels {
-		chSet[strings.ToLower(ch)] = struct{}{}
+		chType, _ := adapters.ParseChannel(ch)
+		chSet[chType] = struct{}{}
 	}
 	out := make([]domain.NotificationTemplate, 0, len(tpls))
 	for _, tpl := range tpls {
@@ -423,14 +483,6 @@ func baseTemplateFor(tpls []domain.NotificationTemplate, channel string) domain.
 	return domain.NotificationTemplate{}
 }
 
-func emailTemplateFor(tpls []domain.NotificationTemplate) domain.NotificationTemplate {
-	return baseTemplateFor(tpls, "email")
-}
-
-func inAppTemplateFor(tpls []domain.NotificationTemplate) domain.NotificationTemplate {
-	return baseTemplateFor(tpls, "in-app")
-}
-
 func defaultValue(value, fallback string) string {
 	if strings.TrimSpace(value) != "" {
 		return value
@@ -133,3 +133,65 @@ func TestRegisterSupportsNamespaceAndOverrides(t *testing.T) {
 		t.Fatalf("expected no in-app template when channel omitted, got %v", err)
 	}
 }
+
+func TestRegisterInstallsTemplatesForArbitraryChannels(t *testing.T) {
+	ctx := context.Background()
+	defRepo := memstore.NewDefinitionRepository()
+	tplRepo := memstore.NewTemplateRepository()
+	tplSvc := newTemplateService(t, tplRepo)
+
+	result, err := Register(ctx, Dependencies{
+		Definitions: defRepo,
+		Templates:   tplSvc,
+	}, Options{
+		Channels: []string{"email", "chat:slack", "chat:teams", "sms"},
+		ChannelTemplates: map[string]ChannelTemplate{
+			"sms":   {Body: "Export {{ file_name }} ready: {{ url }}"},
+			"email": {Subject: "Override wins"},
+		},
+		EmailSubject: "Legacy subject",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	def, err := defRepo.GetByCode(ctx, DefinitionCode)
+	if err != nil {
+		t.Fatalf("get definition: %v", err)
+	}
+	wantKeys := []string{"email:" + EmailTemplateCode, "chat:export.ready.chat", "sms:export.ready.sms"}
+	if !equalStringSets(def.TemplateKeys, wantKeys) {
+		t.Fatalf("unexpected template keys: %v", def.TemplateKeys)
+	}
+	if len(def.Channels) != 4 {
+		t.Fatalf("expected requested channels kept on definition, got %v", def.Channels)
+	}
+	if len(result.Templates) != 3 || result.Templates["chat"].Code != "export.ready.chat" {
+		t.Fatalf("unexpected installed templates: %#v", result.Templates)
+	}
+	if result.InAppCode != "" || result.EmailCode != EmailTemplateCode {
+		t.Fatalf("unexpected legacy result fields: %#v", result)
+	}
+
+	chat, err := tplSvc.Get(ctx, "export.ready.chat", "chat", "en")
+	if err != nil {
+		t.Fatalf("get chat template: %v", err)
+	}
+	if chat.Format != "text/markdown" || chat.Body == "" {
+		t.Fatalf("expected markdown chat default, got format=%s", chat.Format)
+	}
+	sms, err := tplSvc.Get(ctx, "export.ready.sms", "sms", "en")
+	if err != nil {
+		t.Fatalf("get sms template: %v", err)
+	}
+	if sms.Body != "Export {{ file_name }} ready: {{ url }}" {
+		t.Fatalf("expected sms body override, got %q", sms.Body)
+	}
+	email, err := tplSvc.Get(ctx, EmailTemplateCode, "email", "en")
+	if err != nil {
+		t.Fatalf("get email template: %v", err)
+	}
+	if email.Subject != "Override wins" {
+		t.Fatalf("expected channel template to override legacy subject, got %q", email.Subject)
+	}
+}
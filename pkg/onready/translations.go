@@ -8,15 +8,18 @@ import (
 func Translations() i18n.Translations {
 	return i18n.Translations{
 		"en": newCatalog("en", map[string]string{
-			"export.ready.subject":         `Your export "%s" is ready`,
-			"export.ready.title":           `Export ready: %s`,
-			"export.ready.body.intro":      `Your export "%s" (%s) is ready to download.`,
-			"export.ready.body.link_label": "Download",
-			"export.ready.body.expires":    "Link expires at %s",
-			"export.ready.body.rows":       "Rows: %v",
-			"export.ready.body.parts":      "Parts: %v",
-			"export.ready.body.manifest":   "Manifest: %s",
-			"export.ready.body.message":    "Note: %s",
+			"export.ready.subject":           `Your export "%s" is ready`,
+			"export.ready.title":             `Export ready: %s`,
+			"export.ready.body.intro":        `Your export "%s" (%s) is ready to download.`,
+			"export.ready.body.link_label":   "Download",
+			"export.ready.body.expires":      "Link expires at %s",
+			"export.ready.body.rows":         "Rows: %v",
+			"export.ready.body.parts":        "Parts: %v",
+			"export.ready.body.summary":      "%v parts",
+			"export.ready.body.summary_rows": "%v parts, %v rows",
+			"export.ready.body.part":         "Part %v: %s",
+			"export.ready.body.manifest":     "Manifest: %s",
+			"export.ready.body.message":      "Note: %s",
 		}),
 	}
 }
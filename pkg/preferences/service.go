@@ -3,30 +3,59 @@ package preferences
 import (
 	"context"
 	"errors"
+	"time"
 
 	internalprefs "github.com/goliatone/go-notifications/internal/preferences"
 	"github.com/goliatone/go-notifications/pkg/domain"
 	"github.com/goliatone/go-notifications/pkg/interfaces/logger"
 	"github.com/goliatone/go-notifications/pkg/interfaces/store"
+	pkgoptions "github.com/goliatone/go-notifications/pkg/options"
 	opts "github.com/goliatone/go-options"
 )
 
 // Re-export types required by consumers so they do not depend on the internal package.
 type (
-	PreferenceInput   = internalprefs.PreferenceInput
-	EvaluationRequest = internalprefs.EvaluationRequest
-	EvaluationResult  = internalprefs.EvaluationResult
-	QuietHoursWindow  = internalprefs.QuietHoursWindow
+	PreferenceInput      = internalprefs.PreferenceInput
+	EvaluationRequest    = internalprefs.EvaluationRequest
+	EvaluationResult     = internalprefs.EvaluationResult
+	QuietHoursWindow     = internalprefs.QuietHoursWindow
+	ProviderLookup       = internalprefs.ProviderLookup
+	GroupResolver        = internalprefs.GroupResolver
+	SubscriptionResolver = internalprefs.SubscriptionResolver
+	Subject              = internalprefs.Subject
+	EffectiveState       = internalprefs.EffectiveState
 )
 
+// ErrUnknownProvider is returned when a preference names an unregistered provider.
+var ErrUnknownProvider = internalprefs.ErrUnknownProvider
+
 const (
 	ReasonDefault            = internalprefs.ReasonDefault
 	ReasonOptOut             = internalprefs.ReasonOptOut
 	ReasonQuietHours         = internalprefs.ReasonQuietHours
 	ReasonChannelOverride    = internalprefs.ReasonChannelOverride
 	ReasonSubscriptionFilter = internalprefs.ReasonSubscriptionFilter
+	ReasonCriticalOverride   = internalprefs.ReasonCriticalOverride
+	ReasonDoNotDisturb       = internalprefs.ReasonDoNotDisturb
+	ReasonGlobalMute         = internalprefs.ReasonGlobalMute
 )
 
+// GlobalMuteCode is the definition code and channel of the snooze-all preference.
+const GlobalMuteCode = internalprefs.GlobalMuteCode
+
+// SubjectScopes returns the scopes the dispatcher evaluates for a subject.
+func SubjectScopes(subject Subject, definitionCode, channel string) []pkgoptions.PreferenceScopeRef {
+	return internalprefs.SubjectScopes(subject, definitionCode, channel)
+}
+
+// CategoryCode returns the definition code used for category-level preferences.
+func CategoryCode(category string) string {
+	return internalprefs.CategoryCode(category)
+}
+
+// DefaultCriticalSeverities lists severities treated as critical by default.
+var DefaultCriticalSeverities = internalprefs.DefaultCriticalSeverities
+
 // Service exposes CRUD and evaluation helpers to consumers.
 type Service struct {
 	internal *internalprefs.Service
@@ -36,6 +65,14 @@ type Service struct {
 type Dependencies struct {
 	Repository store.NotificationPreferenceRepository
 	Logger     logger.Logger
+	// KnownProviders validates provider overrides on writes when set.
+	KnownProviders ProviderLookup
+	// CriticalSeverities overrides DefaultCriticalSeverities.
+	CriticalSeverities []string
+	// Groups resolves user group memberships for group-level defaults.
+	Groups GroupResolver
+	// Subscriptions resolves recipient subscriptions for subscription filters.
+	Subscriptions SubscriptionResolver
 }
 
 var errServiceNotInitialised = errors.New("preferences: service not initialised")
@@ -43,8 +80,12 @@ var errServiceNotInitialised = errors.New("preferences: service not initialised"
 // New constructs the preferences facade backed by the internal service.
 func New(deps Dependencies) (*Service, error) {
 	internal, err := internalprefs.NewService(internalprefs.Dependencies{
-		Repository: deps.Repository,
-		Logger:     deps.Logger,
+		Repository:         deps.Repository,
+		Logger:             deps.Logger,
+		KnownProviders:     deps.KnownProviders,
+		CriticalSeverities: deps.CriticalSeverities,
+		Groups:             deps.Groups,
+		Subscriptions:      deps.Subscriptions,
 	})
 	if err != nil {
 		return nil, err
@@ -76,6 +117,15 @@ func (s *Service) Upsert(ctx context.Context, input PreferenceInput) (*domain.No
 	return s.internal.Upsert(ctx, input)
 }
 
+// UpsertMany creates or updates several preference records, atomically when
+// the repository supports transactions.
+func (s *Service) UpsertMany(ctx context.Context, inputs []PreferenceInput) ([]*domain.NotificationPreference, error) {
+	if s == nil || s.internal == nil {
+		return nil, errServiceNotInitialised
+	}
+	return s.internal.UpsertMany(ctx, inputs)
+}
+
 // Delete removes the preference record for the provided subject.
 func (s *Service) Delete(ctx context.Context, subjectType, subjectID, definitionCode, channel string) error {
 	if s == nil || s.internal == nil {
@@ -108,6 +158,15 @@ func (s *Service) Evaluate(ctx context.Context, req EvaluationRequest) (Evaluati
 	return s.internal.Evaluate(ctx, req)
 }
 
+// Effective reports whether definitionCode/channel would be delivered to the
+// subject at now, and why, without dispatching.
+func (s *Service) Effective(ctx context.Context, subject Subject, definitionCode, channel string, now time.Time) (EffectiveState, error) {
+	if s == nil || s.internal == nil {
+		return EffectiveState{}, errServiceNotInitialised
+	}
+	return s.internal.Effective(ctx, subject, definitionCode, channel, now)
+}
+
 // ResolveWithTrace evaluates the request and resolves the provided path.
 func (s *Service) ResolveWithTrace(ctx context.Context, req EvaluationRequest, path string) (any, opts.Trace, error) {
 	result, err := s.Evaluate(ctx, req)
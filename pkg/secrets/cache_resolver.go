@@ -7,32 +7,53 @@ import (
 
 // CachingResolver wraps another resolver and caches successful lookups for a short TTL.
 // Intended for external secret managers to avoid excessive network calls.
+//
+// Entries are keyed on the full Reference, so values never leak between
+// scopes, subjects or tenants. References the inner resolver did not return
+// are remembered as misses for the same TTL; a broadcast resolving
+// user -> tenant -> system for every recipient then reaches the inner
+// resolver once per distinct reference instead of once per delivery.
 type CachingResolver struct {
 	Resolver Resolver
 	TTL      time.Duration
 
-	now   func() time.Time
-	mu    sync.Mutex
-	cache map[string]cacheEntry
+	now       func() time.Time
+	mu        sync.Mutex
+	cache     map[Reference]cacheEntry
+	nextSweep time.Time
+	// generations counts invalidations per versionless reference (purges
+	// for every reference) so a lookup that raced an Invalidate does not
+	// store the value it read before the rotation.
+	generations map[Reference]uint64
+	purges      uint64
 }
 
 type cacheEntry struct {
 	value   SecretValue
+	found   bool
 	expires time.Time
 }
 
+// WithCache wraps inner with a CachingResolver using ttl. Keep the returned
+// value to call Invalidate after rotating a secret. If ttl <= 0, lookups
+// pass straight through to inner.
+func WithCache(inner Resolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{
+		Resolver:    inner,
+		TTL:         ttl,
+		now:         time.Now,
+		cache:       make(map[Reference]cacheEntry),
+		generations: make(map[Reference]uint64),
+	}
+}
+
 // NewCachingResolver builds a resolver that caches results for the provided TTL.
 // If ttl <= 0, the inner resolver is returned unchanged.
 func NewCachingResolver(inner Resolver, ttl time.Duration) Resolver {
 	if ttl <= 0 {
 		return inner
 	}
-	return &CachingResolver{
-		Resolver: inner,
-		TTL:      ttl,
-		now:      time.Now,
-		cache:    make(map[string]cacheEntry),
-	}
+	return WithCache(inner, ttl)
 }
 
 // Resolve returns cached entries when fresh and only fetches missing refs from the inner resolver.
@@ -50,13 +71,20 @@ func (c *CachingResolver) Resolve(refs ...Reference) (map[Reference]SecretValue,
 	missing := make([]Reference, 0, len(refs))
 
 	c.mu.Lock()
+	purges := c.purges
 	for _, ref := range refs {
-		if entry, ok := c.cache[key(ref)]; ok && (entry.expires.IsZero() || entry.expires.After(now)) {
-			results[ref] = entry.value
+		if entry, ok := c.cache[ref]; ok && entry.expires.After(now) {
+			if entry.found {
+				results[ref] = entry.value
+			}
 			continue
 		}
 		missing = append(missing, ref)
 	}
+	generations := make([]uint64, len(missing))
+	for i, ref := range missing {
+		generations[i] = c.generations[secretKey(ref)]
+	}
 	c.mu.Unlock()
 
 	if len(missing) > 0 {
@@ -64,16 +92,80 @@ func (c *CachingResolver) Resolve(refs ...Reference) (map[Reference]SecretValue,
 		if err != nil {
 			return nil, err
 		}
+		expires := now.Add(c.TTL)
 		c.mu.Lock()
-		for ref, val := range fresh {
-			c.cache[key(ref)] = cacheEntry{
-				value:   val,
-				expires: now.Add(c.TTL),
+		c.evictExpired(now)
+		for i, ref := range missing {
+			val, ok := fresh[ref]
+			if ok {
+				results[ref] = val
 			}
-			results[ref] = val
+			if c.purges != purges || c.generations[secretKey(ref)] != generations[i] {
+				continue
+			}
+			c.cache[ref] = cacheEntry{value: val, found: ok, expires: expires}
 		}
 		c.mu.Unlock()
 	}
 
 	return results, nil
 }
+
+// Invalidate drops cached entries, hits and misses, for the given references
+// so the next Resolve reads through. Every cached version of a reference is
+// dropped regardless of ref.Version; call it after Put or Delete when
+// rotating a secret. A Resolve already in flight for the reference returns
+// what it read but does not cache it.
+func (c *CachingResolver) Invalidate(refs ...Reference) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ref := range refs {
+		c.generations[secretKey(ref)]++
+	}
+	for cached := range c.cache {
+		for _, ref := range refs {
+			if sameSecret(cached, ref) {
+				delete(c.cache, cached)
+				break
+			}
+		}
+	}
+}
+
+// Purge drops every cached entry.
+func (c *CachingResolver) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	clear(c.cache)
+	c.purges++
+	c.mu.Unlock()
+}
+
+// evictExpired removes stale entries at most once per TTL so the cache does
+// not grow without bound; callers hold c.mu.
+func (c *CachingResolver) evictExpired(now time.Time) {
+	if now.Before(c.nextSweep) {
+		return
+	}
+	c.nextSweep = now.Add(c.TTL)
+	for ref, entry := range c.cache {
+		if !entry.expires.After(now) {
+			delete(c.cache, ref)
+		}
+	}
+}
+
+func sameSecret(a, b Reference) bool {
+	return secretKey(a) == secretKey(b)
+}
+
+// secretKey drops the version so every version of a secret shares one key.
+func secretKey(ref Reference) Reference {
+	ref.Version = ""
+	return ref
+}
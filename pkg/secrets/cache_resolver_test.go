@@ -98,3 +98,146 @@ func TestCachingResolverBypassesWhenTTLDisabled(t *testing.T) {
 		t.Fatalf("expected resolver to be called every time when TTL disabled, got %d", counter.count)
 	}
 }
+
+// partialResolver behaves like SimpleResolver: unknown references are
+// omitted from the result instead of failing the whole call.
+type partialResolver struct {
+	calls [][]Reference
+	data  map[Reference]SecretValue
+}
+
+func (p *partialResolver) Resolve(refs ...Reference) (map[Reference]SecretValue, error) {
+	p.calls = append(p.calls, refs)
+	out := make(map[Reference]SecretValue, len(refs))
+	for _, ref := range refs {
+		if val, ok := p.data[ref]; ok {
+			out[ref] = val
+		}
+	}
+	return out, nil
+}
+
+func TestCachingResolverRemembersMisses(t *testing.T) {
+	system := Reference{Scope: ScopeSystem, SubjectID: "default", Channel: "email", Provider: "sendgrid", Key: "default"}
+	inner := &partialResolver{data: map[Reference]SecretValue{system: {Data: []byte("sys")}}}
+	resolver := WithCache(inner, time.Minute)
+
+	for _, user := range []string{"u1", "u1", "u1"} {
+		userRef := Reference{Scope: ScopeUser, SubjectID: user, Channel: "email", Provider: "sendgrid", Key: "default"}
+		out, err := resolver.Resolve(userRef, system)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if _, ok := out[userRef]; ok {
+			t.Fatalf("expected user secret to stay missing")
+		}
+		if string(out[system].Data) != "sys" {
+			t.Fatalf("expected system secret, got %+v", out)
+		}
+	}
+	if len(inner.calls) != 1 {
+		t.Fatalf("expected misses to be cached, got %d inner calls", len(inner.calls))
+	}
+}
+
+func TestCachingResolverKeysOnFullReference(t *testing.T) {
+	tenantA := Reference{Scope: ScopeTenant, SubjectID: "acme|chat", Channel: "slack", Provider: "slack", Key: "default"}
+	tenantB := Reference{Scope: ScopeTenant, SubjectID: "acme", Channel: "chat|slack", Provider: "slack", Key: "default"}
+	inner := &partialResolver{data: map[Reference]SecretValue{tenantA: {Data: []byte("a")}}}
+	resolver := WithCache(inner, time.Minute)
+
+	if _, err := resolver.Resolve(tenantA); err != nil {
+		t.Fatalf("resolve A: %v", err)
+	}
+	out, err := resolver.Resolve(tenantB)
+	if err != nil {
+		t.Fatalf("resolve B: %v", err)
+	}
+	if _, ok := out[tenantB]; ok {
+		t.Fatalf("tenant B must not see tenant A's cached secret")
+	}
+}
+
+func TestCachingResolverInvalidate(t *testing.T) {
+	ref := Reference{Scope: ScopeUser, SubjectID: "u1", Channel: "chat", Provider: "slack", Key: "token"}
+	inner := &partialResolver{data: map[Reference]SecretValue{ref: {Data: []byte("old"), Version: "v1"}}}
+	resolver := WithCache(inner, time.Minute)
+
+	if _, err := resolver.Resolve(ref); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	inner.data[ref] = SecretValue{Data: []byte("new"), Version: "v2"}
+
+	rotated := ref
+	rotated.Version = "v2"
+	resolver.Invalidate(rotated)
+	out, err := resolver.Resolve(ref)
+	if err != nil {
+		t.Fatalf("resolve after invalidate: %v", err)
+	}
+	if string(out[ref].Data) != "new" {
+		t.Fatalf("expected rotated value, got %s", out[ref].Data)
+	}
+
+	resolver.Purge()
+	if _, err := resolver.Resolve(ref); err != nil {
+		t.Fatalf("resolve after purge: %v", err)
+	}
+	if len(inner.calls) != 3 {
+		t.Fatalf("expected 3 inner calls, got %d", len(inner.calls))
+	}
+}
+
+// blockingResolver parks Resolve until release is closed so a test can
+// interleave an Invalidate with an in-flight lookup.
+type blockingResolver struct {
+	value   SecretValue
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingResolver) Resolve(refs ...Reference) (map[Reference]SecretValue, error) {
+	value := b.value
+	if b.started != nil {
+		close(b.started)
+		<-b.release
+		b.started = nil
+	}
+	out := make(map[Reference]SecretValue, len(refs))
+	for _, ref := range refs {
+		out[ref] = value
+	}
+	return out, nil
+}
+
+func TestCachingResolverInvalidateDuringResolveSkipsStore(t *testing.T) {
+	ref := Reference{Scope: ScopeUser, SubjectID: "u1", Channel: "chat", Provider: "slack", Key: "token"}
+	inner := &blockingResolver{
+		value:   SecretValue{Data: []byte("old"), Version: "v1"},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	resolver := WithCache(inner, time.Minute)
+
+	started := inner.started
+	done := make(chan map[Reference]SecretValue)
+	go func() {
+		out, _ := resolver.Resolve(ref)
+		done <- out
+	}()
+	<-started
+	inner.value = SecretValue{Data: []byte("new"), Version: "v2"}
+	resolver.Invalidate(ref)
+	close(inner.release)
+
+	if out := <-done; string(out[ref].Data) != "old" {
+		t.Fatalf("expected in-flight lookup to return what it read, got %s", out[ref].Data)
+	}
+	out, err := resolver.Resolve(ref)
+	if err != nil {
+		t.Fatalf("resolve after invalidate: %v", err)
+	}
+	if string(out[ref].Data) != "new" {
+		t.Fatalf("expected stale in-flight value not to be cached, got %s", out[ref].Data)
+	}
+}
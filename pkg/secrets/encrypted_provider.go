@@ -0,0 +1,226 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownKey is returned when a ciphertext names a key the provider was
+// not given.
+var ErrUnknownKey = errors.New("secrets: unknown encryption key")
+
+// encryptedMagic prefixes every value written by EncryptedProvider.
+var encryptedMagic = []byte("ntfe1")
+
+// EncryptedProvider encrypts values with AES-256-GCM before delegating to
+// another Provider and decrypts them on Get. Each ciphertext is tagged with
+// the ID of the key-encryption key that sealed it, so old keys can stay
+// configured for reads while new writes use the active one. Ciphertexts are
+// bound to their reference, so a value copied to another subject or scope
+// fails to decrypt.
+type EncryptedProvider struct {
+	inner  Provider
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewEncryptedProvider wraps inner. keys maps key IDs to 32-byte KEKs;
+// activeKeyID selects the one used for writes. The others are only used to
+// decrypt values written before a key rotation.
+func NewEncryptedProvider(inner Provider, activeKeyID string, keys map[string][]byte) (*EncryptedProvider, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("encrypted provider: inner provider required")
+	}
+	if _, ok := keys[activeKeyID]; !ok || activeKeyID == "" {
+		return nil, fmt.Errorf("encrypted provider: active key %q not configured", activeKeyID)
+	}
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("encrypted provider: invalid key id %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encrypted provider: key %q must be 32 bytes", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		aeads[id] = aead
+	}
+	return &EncryptedProvider{inner: inner, active: activeKeyID, keys: aeads}, nil
+}
+
+func (p *EncryptedProvider) Get(ref Reference) (SecretValue, error) {
+	val, err := p.inner.Get(ref)
+	if err != nil {
+		return SecretValue{}, err
+	}
+	plain, _, err := p.open(ref, val.Data)
+	if err != nil {
+		return SecretValue{}, err
+	}
+	val.Data = plain
+	return val, nil
+}
+
+func (p *EncryptedProvider) Put(ref Reference, value []byte) (string, error) {
+	if len(value) == 0 {
+		return "", ErrEmptyValue
+	}
+	sealed, err := p.seal(ref, value)
+	if err != nil {
+		return "", err
+	}
+	return p.inner.Put(ref, sealed)
+}
+
+func (p *EncryptedProvider) Delete(ref Reference) error {
+	return p.inner.Delete(ref)
+}
+
+// Describe returns the inner provider's metadata plus the key_id that
+// sealed the current value.
+func (p *EncryptedProvider) Describe(ref Reference) (map[string]any, error) {
+	meta, err := p.inner.Describe(ref)
+	if err != nil {
+		return nil, err
+	}
+	if val, err := p.inner.Get(ref); err == nil {
+		if keyID, _, err := parseEncrypted(val.Data); err == nil {
+			out := make(map[string]any, len(meta)+1)
+			for k, v := range meta {
+				out[k] = v
+			}
+			out["key_id"] = keyID
+			return out, nil
+		}
+	}
+	return meta, nil
+}
+
+// Resolve decrypts each reference, skipping ones that are not found.
+func (p *EncryptedProvider) Resolve(refs ...Reference) (map[Reference]SecretValue, error) {
+	return SimpleResolver{Provider: p}.Resolve(refs...)
+}
+
+// PutSecret encrypts value and stages it as a new version when the inner
+// provider is a VersionedProvider.
+func (p *EncryptedProvider) PutSecret(ref Reference, value []byte, grace time.Duration) (string, error) {
+	versioned, ok := p.inner.(VersionedProvider)
+	if !ok {
+		return "", ErrUnsupported
+	}
+	if len(value) == 0 {
+		return "", ErrEmptyValue
+	}
+	sealed, err := p.seal(ref, value)
+	if err != nil {
+		return "", err
+	}
+	return versioned.PutSecret(ref, sealed, grace)
+}
+
+// ListVersions delegates to the inner provider when it is a VersionedProvider.
+func (p *EncryptedProvider) ListVersions(ref Reference) ([]VersionInfo, error) {
+	versioned, ok := p.inner.(VersionedProvider)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return versioned.ListVersions(ref)
+}
+
+// Reencrypt rewrites the value stored for ref under the active key, keeping
+// its version. Run it over every reference after rotating the KEK, then drop
+// the old key from the configuration. It returns the key ID the value was
+// sealed with before.
+func (p *EncryptedProvider) Reencrypt(ref Reference) (string, error) {
+	val, err := p.inner.Get(ref)
+	if err != nil {
+		return "", err
+	}
+	plain, keyID, err := p.open(ref, val.Data)
+	if err != nil {
+		return "", err
+	}
+	if keyID == p.active {
+		return keyID, nil
+	}
+	sealed, err := p.seal(ref, plain)
+	if err != nil {
+		return "", err
+	}
+	ref.Version = val.Version
+	if _, err := p.inner.Put(ref, sealed); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// seal encodes magic | len(keyID) | keyID | nonce | ciphertext.
+func (p *EncryptedProvider) seal(ref Reference, plain []byte) ([]byte, error) {
+	aead := p.keys[p.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("nonce: %w", err)
+	}
+	out := make([]byte, 0, len(encryptedMagic)+1+len(p.active)+len(nonce)+len(plain)+aead.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, byte(len(p.active)))
+	out = append(out, p.active...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plain, additionalData(ref, p.active)), nil
+}
+
+func (p *EncryptedProvider) open(ref Reference, data []byte) ([]byte, string, error) {
+	keyID, nonceAndCipher, err := parseEncrypted(data)
+	if err != nil {
+		return nil, "", err
+	}
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, keyID, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	if len(nonceAndCipher) < aead.NonceSize() {
+		return nil, keyID, fmt.Errorf("decrypt: ciphertext too short")
+	}
+	nonce, sealed := nonceAndCipher[:aead.NonceSize()], nonceAndCipher[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, additionalData(ref, keyID))
+	if err != nil {
+		return nil, keyID, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, keyID, nil
+}
+
+// parseEncrypted splits a sealed value into its key ID and nonce+ciphertext.
+func parseEncrypted(data []byte) (string, []byte, error) {
+	if !bytes.HasPrefix(data, encryptedMagic) || len(data) < len(encryptedMagic)+1 {
+		return "", nil, fmt.Errorf("decrypt: value is not encrypted")
+	}
+	header := len(encryptedMagic) + 1 + int(data[len(encryptedMagic)])
+	if len(data) < header {
+		return "", nil, fmt.Errorf("decrypt: truncated key id")
+	}
+	return string(data[len(encryptedMagic)+1 : header]), data[header:], nil
+}
+
+// additionalData binds a ciphertext to its key ID and to the reference it
+// was written for, ignoring the version so values survive re-versioning.
+func additionalData(ref Reference, keyID string) []byte {
+	var buf bytes.Buffer
+	for _, part := range []string{keyID, string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key} {
+		buf.WriteByte(byte(len(part) >> 8))
+		buf.WriteByte(byte(len(part)))
+		buf.WriteString(part)
+	}
+	return buf.Bytes()
+}
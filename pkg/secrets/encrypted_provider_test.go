@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEncryptedProviderRoundTrip(t *testing.T) {
+	inner := NewStaticProvider(nil)
+	prov, err := NewEncryptedProvider(inner, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	ref := Reference{Scope: ScopeTenant, SubjectID: "acme", Channel: "email", Provider: "sendgrid", Key: "default"}
+	if _, err := prov.Put(ref, []byte("SG.secret")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	stored, err := inner.Get(ref)
+	if err != nil {
+		t.Fatalf("inner get: %v", err)
+	}
+	if bytes.Contains(stored.Data, []byte("SG.secret")) {
+		t.Fatalf("expected ciphertext at rest, got %q", stored.Data)
+	}
+
+	out, err := prov.Resolve(ref)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if string(out[ref].Data) != "SG.secret" {
+		t.Fatalf("expected decrypted value, got %q", out[ref].Data)
+	}
+	meta, err := prov.Describe(ref)
+	if err != nil || meta["key_id"] != "k1" {
+		t.Fatalf("expected key_id in description, got %v (%v)", meta, err)
+	}
+}
+
+func TestEncryptedProviderRejectsMovedCiphertext(t *testing.T) {
+	inner := NewStaticProvider(nil)
+	prov, err := NewEncryptedProvider(inner, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	acme := Reference{Scope: ScopeTenant, SubjectID: "acme", Channel: "email", Provider: "sendgrid", Key: "default"}
+	other := acme
+	other.SubjectID = "globex"
+	if _, err := prov.Put(acme, []byte("acme-key")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	stored, _ := inner.Get(acme)
+	if _, err := inner.Put(other, stored.Data); err != nil {
+		t.Fatalf("copy ciphertext: %v", err)
+	}
+	if _, err := prov.Get(other); err == nil {
+		t.Fatalf("expected ciphertext bound to another tenant to fail")
+	}
+}
+
+func TestEncryptedProviderKeyRotation(t *testing.T) {
+	inner := NewStaticProvider(nil)
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	ref := Reference{Scope: ScopeSystem, SubjectID: "default", Channel: "sms", Provider: "twilio", Key: "default", Version: "v1"}
+
+	before, err := NewEncryptedProvider(inner, "k1", map[string][]byte{"k1": oldKey})
+	if err != nil {
+		t.Fatalf("provider k1: %v", err)
+	}
+	if _, err := before.Put(ref, []byte("token")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	rotated, err := NewEncryptedProvider(inner, "k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	if err != nil {
+		t.Fatalf("provider k2: %v", err)
+	}
+	if val, err := rotated.Get(ref); err != nil || string(val.Data) != "token" {
+		t.Fatalf("expected old ciphertext readable after rotation, got %q (%v)", val.Data, err)
+	}
+	prev, err := rotated.Reencrypt(ref)
+	if err != nil || prev != "k1" {
+		t.Fatalf("reencrypt: prev=%q err=%v", prev, err)
+	}
+
+	after, err := NewEncryptedProvider(inner, "k2", map[string][]byte{"k2": newKey})
+	if err != nil {
+		t.Fatalf("provider k2 only: %v", err)
+	}
+	val, err := after.Get(ref)
+	if err != nil || string(val.Data) != "token" || val.Version != "v1" {
+		t.Fatalf("expected re-encrypted value under k2, got %+v (%v)", val, err)
+	}
+	if _, err := before.Get(ref); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown key for k1-only provider, got %v", err)
+	}
+}
+
+func TestEncryptedProviderVersions(t *testing.T) {
+	prov, err := NewEncryptedProvider(NewStaticProvider(nil), "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	ref := Reference{Scope: ScopeUser, SubjectID: "u1", Channel: "chat", Provider: "slack", Key: "default"}
+	if _, err := prov.PutSecret(withVersion(ref, "v1"), []byte("one"), time.Hour); err != nil {
+		t.Fatalf("put v1: %v", err)
+	}
+	if _, err := prov.PutSecret(withVersion(ref, "v2"), []byte("two"), time.Hour); err != nil {
+		t.Fatalf("put v2: %v", err)
+	}
+	if val, err := prov.Get(withVersion(ref, "v1")); err != nil || string(val.Data) != "one" {
+		t.Fatalf("expected v1 during grace, got %q (%v)", val.Data, err)
+	}
+	versions, err := prov.ListVersions(ref)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("expected two versions, got %+v (%v)", versions, err)
+	}
+}
+
+func TestNewEncryptedProviderValidatesKeys(t *testing.T) {
+	inner := NewStaticProvider(nil)
+	if _, err := NewEncryptedProvider(inner, "missing", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}); err == nil {
+		t.Fatalf("expected error for unknown active key")
+	}
+	if _, err := NewEncryptedProvider(inner, "k1", map[string][]byte{"k1": []byte("short")}); err == nil {
+		t.Fatalf("expected error for short key")
+	}
+}
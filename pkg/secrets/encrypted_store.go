@@ -39,7 +39,7 @@ func NewEncryptedStoreProvider(store iface.Store, key []byte) (*EncryptedStorePr
 	return &EncryptedStoreProvider{
 		store: store,
 		aead:  aead,
-		now:   time.Now().UTC,
+		now:   func() time.Time { return time.Now().UTC() },
 	}, nil
 }
 
@@ -54,10 +54,16 @@ func (p *EncryptedStoreProvider) Get(ref Reference) (SecretValue, error) {
 		rec, err = p.store.GetVersion(ctx, string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key, ref.Version)
 	} else {
 		rec, err = p.store.GetLatest(ctx, string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key)
+		if err == nil && expired(rec.Metadata, p.now()) {
+			rec, err = p.latestActive(ctx, ref)
+		}
 	}
 	if err != nil {
 		return SecretValue{}, translateStoreError(err)
 	}
+	if expired(rec.Metadata, p.now()) {
+		return SecretValue{}, ErrNotFound
+	}
 	plain, err := p.aead.Open(nil, rec.Nonce, rec.Cipher, nil)
 	if err != nil {
 		return SecretValue{}, fmt.Errorf("decrypt: %w", err)
@@ -102,6 +108,83 @@ func (p *EncryptedStoreProvider) Put(ref Reference, value []byte) (string, error
 	return ref.Version, nil
 }
 
+// PutSecret stores value as a new version and retires the previous versions
+// after grace.
+func (p *EncryptedStoreProvider) PutSecret(ref Reference, value []byte, grace time.Duration) (string, error) {
+	if err := ValidateReference(ref); err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+	previous, err := p.store.List(ctx, string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key)
+	if err != nil {
+		return "", translateStoreError(err)
+	}
+	version, err := p.Put(ref, value)
+	if err != nil {
+		return "", err
+	}
+	expires := p.now().Add(max(grace, 0))
+	for _, rec := range previous {
+		if rec.Version == version {
+			continue
+		}
+		meta, changed := retireMetadata(rec.Metadata, expires)
+		if !changed {
+			continue
+		}
+		rec.Metadata = meta
+		if err := p.store.Put(ctx, rec); err != nil {
+			return version, fmt.Errorf("retire version %s: %w", rec.Version, translateStoreError(err))
+		}
+	}
+	return version, nil
+}
+
+// ListVersions returns the stored versions of ref, newest first.
+func (p *EncryptedStoreProvider) ListVersions(ref Reference) ([]VersionInfo, error) {
+	if err := ValidateReference(ref); err != nil {
+		return nil, err
+	}
+	recs, err := p.store.List(context.Background(), string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key)
+	if err != nil {
+		return nil, translateStoreError(err)
+	}
+	if len(recs) == 0 {
+		return nil, ErrNotFound
+	}
+	versions := make([]VersionInfo, 0, len(recs))
+	for _, rec := range recs {
+		versions = append(versions, versionInfo(rec.Version, rec.Metadata))
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// latestActive scans every version when the newest one has been retired,
+// which only happens when versions were supplied out of order.
+func (p *EncryptedStoreProvider) latestActive(ctx context.Context, ref Reference) (iface.Record, error) {
+	recs, err := p.store.List(ctx, string(ref.Scope), ref.SubjectID, ref.Channel, ref.Provider, ref.Key)
+	if err != nil {
+		return iface.Record{}, err
+	}
+	var latest iface.Record
+	var found bool
+	now := p.now()
+	for _, rec := range recs {
+		if expired(rec.Metadata, now) {
+			continue
+		}
+		if !found || rec.Version > latest.Version {
+			latest = rec
+			found = true
+		}
+	}
+	if !found {
+		return iface.Record{}, ErrNotFound
+	}
+	return latest, nil
+}
+
 func (p *EncryptedStoreProvider) Delete(ref Reference) error {
 	if err := ValidateReference(ref); err != nil {
 		return err
@@ -2,7 +2,9 @@ package secrets
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestEncryptedStoreProviderRoundTrip(t *testing.T) {
@@ -52,3 +54,49 @@ func TestEncryptedStoreProviderDelete(t *testing.T) {
 		t.Fatalf("expected not found after delete")
 	}
 }
+
+func TestEncryptedStoreProviderRotation(t *testing.T) {
+	key := bytes.Repeat([]byte{3}, 32)
+	prov, err := NewEncryptedStoreProvider(NewMemoryStore(), key)
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	ref := Reference{Scope: ScopeSystem, SubjectID: "default", Channel: "email", Provider: "sendgrid", Key: "default"}
+	v1, err := prov.Put(ref, []byte("old"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	v2, err := prov.PutSecret(ref, []byte("new"), time.Hour)
+	if err != nil {
+		t.Fatalf("put secret: %v", err)
+	}
+	if v1 == v2 {
+		t.Fatalf("expected distinct versions, got %s twice", v1)
+	}
+
+	latest, err := prov.Get(ref)
+	if err != nil || string(latest.Data) != "new" || latest.Version != v2 {
+		t.Fatalf("expected latest %s, got %+v (%v)", v2, latest, err)
+	}
+	old, err := prov.Get(withVersion(ref, v1))
+	if err != nil || string(old.Data) != "old" {
+		t.Fatalf("expected previous version during grace, got %+v (%v)", old, err)
+	}
+
+	versions, err := prov.ListVersions(ref)
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != v2 || versions[1].ExpiresAt.IsZero() {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+
+	// Past the grace period the previous version no longer resolves.
+	prov.now = func() time.Time { return time.Now().UTC().Add(2 * time.Hour) }
+	if _, err := prov.Get(withVersion(ref, v1)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected retired version to be gone, got %v", err)
+	}
+	if latest, err := prov.Get(ref); err != nil || latest.Version != v2 {
+		t.Fatalf("expected latest to survive grace expiry, got %+v (%v)", latest, err)
+	}
+}
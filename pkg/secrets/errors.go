@@ -32,7 +32,7 @@ func ValidateReference(ref Reference) error {
 
 func isValidScope(scope Scope) bool {
 	switch scope {
-	case ScopeSystem, ScopeTenant, ScopeUser:
+	case ScopeSystem, ScopeTenant, ScopeGroup, ScopeUser:
 		return true
 	default:
 		return false
@@ -4,6 +4,7 @@ package secrets
 type Registry struct {
 	System Provider
 	Tenant Provider
+	Group  Provider
 	User   Provider
 }
 
@@ -14,6 +15,8 @@ func (r Registry) ProviderFor(scope Scope) Provider {
 		return r.System
 	case ScopeTenant:
 		return r.Tenant
+	case ScopeGroup:
+		return r.Group
 	case ScopeUser:
 		return r.User
 	default:
@@ -26,17 +26,18 @@ func (p *StaticProvider) Get(ref Reference) (SecretValue, error) {
 	}
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+	now := time.Now().UTC()
 	if ref.Version != "" {
-		if val, ok := p.store[key(ref)]; ok {
+		if val, ok := p.store[key(ref)]; ok && !expired(val.Metadata, now) {
 			return val, nil
 		}
 		return SecretValue{}, ErrNotFound
 	}
-	// If no version requested, return latest by lexical max.
+	// If no version requested, return the latest active version by lexical max.
 	var latest SecretValue
 	var found bool
 	for k, v := range p.store {
-		if matchesBase(ref, k) {
+		if matchesBase(ref, k) && !expired(v.Metadata, now) {
 			if !found || v.Version > latest.Version {
 				latest = v
 				found = true
@@ -69,6 +70,60 @@ func (p *StaticProvider) Put(ref Reference, value []byte) (string, error) {
 	return ref.Version, nil
 }
 
+// PutSecret stores value as a new version and retires the previous versions
+// after grace.
+func (p *StaticProvider) PutSecret(ref Reference, value []byte, grace time.Duration) (string, error) {
+	if err := ValidateReference(ref); err != nil {
+		return "", err
+	}
+	if len(value) == 0 {
+		return "", ErrEmptyValue
+	}
+	now := time.Now().UTC()
+	if ref.Version == "" {
+		ref.Version = now.Format(time.RFC3339Nano)
+	}
+	expires := now.Add(max(grace, 0))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for k, v := range p.store {
+		if !matchesBase(ref, k) || v.Version == ref.Version {
+			continue
+		}
+		if meta, changed := retireMetadata(v.Metadata, expires); changed {
+			v.Metadata = meta
+			p.store[k] = v
+		}
+	}
+	p.store[key(ref)] = SecretValue{
+		Data:      append([]byte(nil), value...),
+		Version:   ref.Version,
+		Retrieved: now,
+		Metadata:  map[string]any{MetadataCreatedAt: now},
+	}
+	return ref.Version, nil
+}
+
+// ListVersions returns the stored versions of ref, newest first.
+func (p *StaticProvider) ListVersions(ref Reference) ([]VersionInfo, error) {
+	if err := ValidateReference(ref); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var versions []VersionInfo
+	for k, v := range p.store {
+		if matchesBase(ref, k) {
+			versions = append(versions, versionInfo(v.Version, v.Metadata))
+		}
+	}
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
 func (p *StaticProvider) Delete(ref Reference) error {
 	if err := ValidateReference(ref); err != nil {
 		return err
@@ -1,6 +1,10 @@
 package secrets
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestStaticProviderRoundTrip(t *testing.T) {
 	ref := Reference{Scope: ScopeUser, SubjectID: "u1", Channel: "chat", Provider: "slack", Key: "token"}
@@ -24,6 +28,51 @@ func TestStaticProviderRoundTrip(t *testing.T) {
 	}
 }
 
+func TestStaticProviderPutSecretKeepsPreviousVersionForGrace(t *testing.T) {
+	ref := Reference{Scope: ScopeTenant, SubjectID: "acme", Channel: "sms", Provider: "twilio", Key: "default"}
+	p := NewStaticProvider(nil)
+	if _, err := p.Put(withVersion(ref, "v1"), []byte("old")); err != nil {
+		t.Fatalf("put v1: %v", err)
+	}
+	if _, err := p.PutSecret(withVersion(ref, "v2"), []byte("new"), time.Hour); err != nil {
+		t.Fatalf("put v2: %v", err)
+	}
+
+	latest, err := p.Get(ref)
+	if err != nil || string(latest.Data) != "new" {
+		t.Fatalf("expected latest v2, got %+v (%v)", latest, err)
+	}
+	old, err := p.Get(withVersion(ref, "v1"))
+	if err != nil || string(old.Data) != "old" {
+		t.Fatalf("expected v1 readable during grace, got %+v (%v)", old, err)
+	}
+
+	versions, err := p.ListVersions(ref)
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != "v2" || versions[1].Version != "v1" {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	if !versions[0].ExpiresAt.IsZero() || versions[1].ExpiresAt.IsZero() {
+		t.Fatalf("expected only v1 to be retired: %+v", versions)
+	}
+
+	if _, err := p.PutSecret(withVersion(ref, "v3"), []byte("newer"), 0); err != nil {
+		t.Fatalf("put v3: %v", err)
+	}
+	for _, version := range []string{"v1", "v2"} {
+		if _, err := p.Get(withVersion(ref, version)); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected %s retired without grace, got %v", version, err)
+		}
+	}
+}
+
+func withVersion(ref Reference, version string) Reference {
+	ref.Version = version
+	return ref
+}
+
 func TestMaskValues(t *testing.T) {
 	ref := Reference{Scope: ScopeUser, SubjectID: "u1", Channel: "chat", Provider: "slack", Key: "token"}
 	masked := MaskValues(map[Reference]SecretValue{
@@ -1,6 +1,9 @@
 package secrets
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Scope defines the ownership boundary for a secret.
 type Scope string
@@ -8,6 +11,7 @@ type Scope string
 const (
 	ScopeSystem Scope = "system"
 	ScopeTenant Scope = "tenant"
+	ScopeGroup  Scope = "group" // shared by a team; SubjectID is GroupSubject(tenant, group)
 	ScopeUser   Scope = "user"
 )
 
@@ -21,6 +25,17 @@ type Reference struct {
 	Version   string
 }
 
+// GroupSubject returns the SubjectID of a group scoped secret. Group IDs are
+// only unique within a tenant, so the tenant is prefixed when set
+// ("tenant/group"); without a tenant the group ID is used as is.
+func GroupSubject(tenantID, groupID string) string {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return groupID
+	}
+	return tenantID + "/" + groupID
+}
+
 // SecretValue carries the resolved secret payload.
 type SecretValue struct {
 	Data      []byte
@@ -0,0 +1,398 @@
+// Package vault resolves secrets from a HashiCorp Vault KV v2 engine.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/secrets"
+)
+
+// DefaultPathTemplate mirrors the layout documented in pkg/secrets.
+const DefaultPathTemplate = "ntf/{scope}/{subject}/{channel}/{provider}/{key}"
+
+// Config configures the Vault resolver.
+type Config struct {
+	// Address of the Vault server; defaults to $VAULT_ADDR.
+	Address string
+	// Token used to authenticate; defaults to $VAULT_TOKEN.
+	Token string
+	// Namespace is sent as X-Vault-Namespace (Vault Enterprise / HCP).
+	Namespace string
+	// Mount is the KV v2 mount path, "secret" by default.
+	Mount string
+	// PathTemplate maps a Reference to a path below the mount using the
+	// placeholders {scope}, {subject}, {channel}, {provider} and {key}.
+	PathTemplate string
+	// Field is the key inside the secret's data holding the value, "value"
+	// by default.
+	Field string
+	// CacheTTL bounds how long a read is reused when Vault reports no lease
+	// duration, as KV v2 does. Zero disables caching of such reads.
+	CacheTTL time.Duration
+	// RenewBefore renews a renewable token this long before it expires;
+	// defaults to one minute.
+	RenewBefore time.Duration
+	Timeout     time.Duration
+	Client      *http.Client
+}
+
+// Resolver reads secrets from Vault KV v2. It implements secrets.Resolver:
+// references whose path does not exist are omitted from the result, so the
+// dispatcher's user -> group -> tenant -> system fallback works unchanged.
+type Resolver struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[secrets.Reference]cachedValue
+	token tokenState
+}
+
+type cachedValue struct {
+	value   secrets.SecretValue
+	expires time.Time
+}
+
+type tokenState struct {
+	checked   bool
+	renewable bool
+	expires   time.Time
+}
+
+// New builds a Vault resolver.
+func New(cfg Config) (*Resolver, error) {
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if strings.TrimSpace(cfg.Address) == "" {
+		return nil, fmt.Errorf("vault: address required")
+	}
+	if strings.TrimSpace(cfg.Token) == "" {
+		return nil, fmt.Errorf("vault: token required")
+	}
+	cfg.Address = strings.TrimRight(strings.TrimSpace(cfg.Address), "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.PathTemplate == "" {
+		cfg.PathTemplate = DefaultPathTemplate
+	}
+	if cfg.Field == "" {
+		cfg.Field = "value"
+	}
+	if cfg.RenewBefore <= 0 {
+		cfg.RenewBefore = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &Resolver{
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+		cache:  make(map[secrets.Reference]cachedValue),
+	}, nil
+}
+
+// Resolve reads each reference from Vault. Missing paths and references that
+// cannot be mapped to a path are skipped, so the next scope in the fallback
+// chain still gets its turn; any other failure aborts the call.
+func (r *Resolver) Resolve(refs ...secrets.Reference) (map[secrets.Reference]secrets.SecretValue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout)
+	defer cancel()
+
+	results := make(map[secrets.Reference]secrets.SecretValue, len(refs))
+	for _, ref := range refs {
+		val, err := r.Get(ctx, ref)
+		if err != nil {
+			if errors.Is(err, secrets.ErrNotFound) || errors.Is(err, secrets.ErrInvalidRef) {
+				continue
+			}
+			return nil, err
+		}
+		results[ref] = val
+	}
+	return results, nil
+}
+
+// Get reads a single reference, returning secrets.ErrNotFound when the path,
+// the requested version or the configured field does not exist.
+func (r *Resolver) Get(ctx context.Context, ref secrets.Reference) (secrets.SecretValue, error) {
+	if err := secrets.ValidateReference(ref); err != nil {
+		return secrets.SecretValue{}, err
+	}
+	if val, ok := r.cached(ref); ok {
+		return val, nil
+	}
+	path, err := r.Path(ref)
+	if err != nil {
+		return secrets.SecretValue{}, err
+	}
+	if err := r.renewToken(ctx); err != nil {
+		return secrets.SecretValue{}, err
+	}
+
+	endpoint := r.cfg.Address + "/v1/" + r.cfg.Mount + "/data/" + path
+	if ref.Version != "" {
+		endpoint += "?version=" + url.QueryEscape(ref.Version)
+	}
+	var body struct {
+		LeaseDuration int `json:"lease_duration"`
+		Data          struct {
+			Data     map[string]any `json:"data"`
+			Metadata struct {
+				Version      json.Number `json:"version"`
+				CreatedTime  string      `json:"created_time"`
+				DeletionTime string      `json:"deletion_time"`
+				Destroyed    bool        `json:"destroyed"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := r.do(ctx, http.MethodGet, endpoint, nil, &body); err != nil {
+		return secrets.SecretValue{}, err
+	}
+	meta := body.Data.Metadata
+	if body.Data.Data == nil || meta.Destroyed || meta.DeletionTime != "" {
+		return secrets.SecretValue{}, secrets.ErrNotFound
+	}
+	raw, ok := body.Data.Data[r.cfg.Field]
+	if !ok {
+		return secrets.SecretValue{}, secrets.ErrNotFound
+	}
+	var data []byte
+	switch v := raw.(type) {
+	case string:
+		data = []byte(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return secrets.SecretValue{}, fmt.Errorf("vault: encode field %s: %w", r.cfg.Field, err)
+		}
+		data = encoded
+	}
+	if len(data) == 0 {
+		return secrets.SecretValue{}, secrets.ErrEmptyValue
+	}
+
+	now := r.now()
+	val := secrets.SecretValue{
+		Data:      data,
+		Version:   meta.Version.String(),
+		Retrieved: now,
+		Metadata:  map[string]any{"path": r.cfg.Mount + "/" + path},
+	}
+	if meta.CreatedTime != "" {
+		val.Metadata[secrets.MetadataCreatedAt] = meta.CreatedTime
+	}
+	ttl := r.cfg.CacheTTL
+	if body.LeaseDuration > 0 {
+		ttl = time.Duration(body.LeaseDuration) * time.Second
+	}
+	if ttl > 0 {
+		r.mu.Lock()
+		r.cache[ref] = cachedValue{value: val, expires: now.Add(ttl)}
+		r.mu.Unlock()
+	}
+	return val, nil
+}
+
+// Invalidate drops cached reads for refs, across versions.
+func (r *Resolver) Invalidate(refs ...secrets.Reference) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for cached := range r.cache {
+		base := cached
+		base.Version = ""
+		for _, ref := range refs {
+			ref.Version = ""
+			if base == ref {
+				delete(r.cache, cached)
+				break
+			}
+		}
+	}
+}
+
+// Path renders the KV path for ref below the mount. Segments may not
+// contain "/" or be "." / "..", so a subject ID cannot reach another
+// tenant's path. Group subjects ("tenant/group", see secrets.GroupSubject)
+// are the one exception: they map to nested tenant and group segments.
+func (r *Resolver) Path(ref secrets.Reference) (string, error) {
+	subject, err := pathSegments("subject", ref.SubjectID, ref.Scope == secrets.ScopeGroup)
+	if err != nil {
+		return "", err
+	}
+	pairs := []string{"{subject}", subject}
+	for placeholder, value := range map[string]string{
+		"{scope}":    string(ref.Scope),
+		"{channel}":  ref.Channel,
+		"{provider}": ref.Provider,
+		"{key}":      ref.Key,
+	} {
+		segment, err := pathSegments(strings.Trim(placeholder, "{}"), value, false)
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, placeholder, segment)
+	}
+	return strings.Trim(strings.NewReplacer(pairs...).Replace(r.cfg.PathTemplate), "/"), nil
+}
+
+// pathSegments validates and escapes value for the named placeholder. With
+// nested set, value may hold a tenant and a group separated by one "/".
+func pathSegments(name, value string, nested bool) (string, error) {
+	parts := []string{value}
+	if nested {
+		if tenant, group, ok := strings.Cut(value, "/"); ok {
+			parts = []string{tenant, group}
+		}
+	}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, "/\\?#") {
+			return "", fmt.Errorf("%w: %s %q not allowed in vault path", secrets.ErrInvalidRef, name, value)
+		}
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+func (r *Resolver) cached(ref secrets.Reference) (secrets.SecretValue, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[ref]
+	if !ok {
+		return secrets.SecretValue{}, false
+	}
+	if !r.now().Before(entry.expires) {
+		delete(r.cache, ref)
+		return secrets.SecretValue{}, false
+	}
+	return entry.value, true
+}
+
+// renewToken looks the token up once and renews it when it is renewable and
+// within RenewBefore of expiring. Lookup failures (for example a policy
+// without auth/token/lookup-self) disable renewal rather than reads.
+func (r *Resolver) renewToken(ctx context.Context) error {
+	r.mu.Lock()
+	state := r.token
+	r.mu.Unlock()
+
+	if !state.checked {
+		state = tokenState{checked: true}
+		var body struct {
+			Data struct {
+				TTL       int  `json:"ttl"`
+				Renewable bool `json:"renewable"`
+			} `json:"data"`
+		}
+		if err := r.do(ctx, http.MethodGet, r.cfg.Address+"/v1/auth/token/lookup-self", nil, &body); err == nil && body.Data.TTL > 0 {
+			state.renewable = body.Data.Renewable
+			state.expires = r.now().Add(time.Duration(body.Data.TTL) * time.Second)
+		}
+		r.mu.Lock()
+		r.token = state
+		r.mu.Unlock()
+	}
+	if !state.renewable || r.now().Before(state.expires.Add(-r.cfg.RenewBefore)) {
+		return nil
+	}
+
+	var body struct {
+		Auth struct {
+			LeaseDuration int  `json:"lease_duration"`
+			Renewable     bool `json:"renewable"`
+		} `json:"auth"`
+	}
+	if err := r.do(ctx, http.MethodPost, r.cfg.Address+"/v1/auth/token/renew-self", []byte("{}"), &body); err != nil {
+		return fmt.Errorf("vault: renew token: %w", err)
+	}
+	r.mu.Lock()
+	r.token = tokenState{
+		checked:   true,
+		renewable: body.Auth.Renewable,
+		expires:   r.now().Add(time.Duration(body.Auth.LeaseDuration) * time.Second),
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Resolver) do(ctx context.Context, method, endpoint string, payload []byte, out any) error {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("vault: build request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", r.cfg.Token)
+	req.Header.Set("X-Vault-Request", "true")
+	if r.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.cfg.Namespace)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return secrets.ErrNotFound
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%w: vault status %d", secrets.ErrUnauthorized, resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("vault: unexpected status %d: %s", resp.StatusCode, vaultErrors(data))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("vault: decode response: %w", err)
+	}
+	return nil
+}
+
+// vaultErrors extracts the "errors" list Vault returns on failure, never the
+// request payload.
+func vaultErrors(data []byte) string {
+	var body struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && len(body.Errors) > 0 {
+		return strings.Join(body.Errors, "; ")
+	}
+	return strconv.Itoa(len(data)) + " bytes"
+}
@@ -0,0 +1,210 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-notifications/pkg/secrets"
+)
+
+func TestResolveReadsKVv2AndSkipsMissingPaths(t *testing.T) {
+	var reads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			t.Errorf("missing vault token header")
+		}
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			_, _ = io.WriteString(w, `{"data":{"ttl":0,"renewable":false}}`)
+		case "/v1/kv/data/ntf/system/default/email/sendgrid/default":
+			reads.Add(1)
+			if r.URL.Query().Get("version") != "" {
+				t.Errorf("unexpected version query %q", r.URL.RawQuery)
+			}
+			_, _ = io.WriteString(w, `{"lease_duration":0,"data":{"data":{"value":"SG.key"},"metadata":{"version":3,"created_time":"2025-01-02T03:04:05Z"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	resolver, err := New(Config{Address: server.URL, Token: "root", Mount: "kv", CacheTTL: time.Minute, Client: server.Client()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	user := secrets.Reference{Scope: secrets.ScopeUser, SubjectID: "u1", Channel: "email", Provider: "sendgrid", Key: "default"}
+	system := secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: "email", Provider: "sendgrid", Key: "default"}
+
+	for range 2 {
+		out, err := resolver.Resolve(user, system)
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if _, ok := out[user]; ok {
+			t.Fatalf("expected missing user path to be skipped")
+		}
+		if got := out[system]; string(got.Data) != "SG.key" || got.Version != "3" {
+			t.Fatalf("unexpected system secret %+v", got)
+		}
+	}
+	if reads.Load() != 1 {
+		t.Fatalf("expected cached second read, got %d reads", reads.Load())
+	}
+
+	if _, err := resolver.Get(context.Background(), user); !errors.Is(err, secrets.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetRequestsVersionAndTreatsDeletedAsMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/token/lookup-self" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("version") == "1" {
+			_, _ = io.WriteString(w, `{"data":{"data":null,"metadata":{"version":1,"deletion_time":"2025-01-01T00:00:00Z"}}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"data":{"value":"v2"},"metadata":{"version":2}}}`)
+	}))
+	defer server.Close()
+
+	resolver, err := New(Config{Address: server.URL, Token: "t", Client: server.Client()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ref := secrets.Reference{Scope: secrets.ScopeTenant, SubjectID: "acme", Channel: "sms", Provider: "twilio", Key: "default", Version: "1"}
+	if _, err := resolver.Get(context.Background(), ref); !errors.Is(err, secrets.ErrNotFound) {
+		t.Fatalf("expected deleted version to be not found, got %v", err)
+	}
+	ref.Version = ""
+	val, err := resolver.Get(context.Background(), ref)
+	if err != nil || string(val.Data) != "v2" {
+		t.Fatalf("expected latest value, got %+v (%v)", val, err)
+	}
+}
+
+func TestRenewsTokenBeforeExpiry(t *testing.T) {
+	var renewals atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			_, _ = io.WriteString(w, `{"data":{"ttl":120,"renewable":true}}`)
+		case "/v1/auth/token/renew-self":
+			renewals.Add(1)
+			_, _ = io.WriteString(w, `{"auth":{"lease_duration":3600,"renewable":true}}`)
+		default:
+			_, _ = io.WriteString(w, `{"data":{"data":{"value":"x"},"metadata":{"version":1}}}`)
+		}
+	}))
+	defer server.Close()
+
+	resolver, err := New(Config{Address: server.URL, Token: "t", Client: server.Client()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	start := time.Now()
+	resolver.now = func() time.Time { return start }
+	ref := secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: "chat", Provider: "slack", Key: "default"}
+	if _, err := resolver.Get(context.Background(), ref); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if renewals.Load() != 0 {
+		t.Fatalf("expected no renewal with two minutes left")
+	}
+
+	resolver.now = func() time.Time { return start.Add(90 * time.Second) }
+	if _, err := resolver.Get(context.Background(), ref); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if renewals.Load() != 1 {
+		t.Fatalf("expected renewal inside the renew window, got %d", renewals.Load())
+	}
+}
+
+func TestPathRejectsTraversal(t *testing.T) {
+	resolver, err := New(Config{Address: "http://vault", Token: "t"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	for _, subject := range []string{"../acme", "a/b", ".."} {
+		ref := secrets.Reference{Scope: secrets.ScopeTenant, SubjectID: subject, Channel: "sms", Provider: "twilio", Key: "default"}
+		if _, err := resolver.Path(ref); !errors.Is(err, secrets.ErrInvalidRef) {
+			t.Fatalf("subject %q: expected invalid reference, got %v", subject, err)
+		}
+	}
+	for _, subject := range []string{"acme/../x", "acme/ops/x", "/ops", "acme/"} {
+		ref := secrets.Reference{Scope: secrets.ScopeGroup, SubjectID: subject, Channel: "sms", Provider: "twilio", Key: "default"}
+		if _, err := resolver.Path(ref); !errors.Is(err, secrets.ErrInvalidRef) {
+			t.Fatalf("group subject %q: expected invalid reference, got %v", subject, err)
+		}
+	}
+	path, err := resolver.Path(secrets.Reference{Scope: secrets.ScopeUser, SubjectID: "user@example.com", Channel: "email", Provider: "ses", Key: "default"})
+	if err != nil || path != "ntf/user/user@example.com/email/ses/default" {
+		t.Fatalf("unexpected path %q (%v)", path, err)
+	}
+}
+
+func TestResolveGroupSubjectUsesNestedPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			_, _ = io.WriteString(w, `{"data":{"ttl":0,"renewable":false}}`)
+		case "/v1/secret/data/ntf/group/acme/ops/email/sendgrid/default":
+			_, _ = io.WriteString(w, `{"data":{"data":{"value":"SG.group"},"metadata":{"version":1}}}`)
+		case "/v1/secret/data/ntf/tenant/acme/email/sendgrid/default":
+			_, _ = io.WriteString(w, `{"data":{"data":{"value":"SG.tenant"},"metadata":{"version":1}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver, err := New(Config{Address: server.URL, Token: "root", Client: server.Client()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	user := secrets.Reference{Scope: secrets.ScopeUser, SubjectID: "u1", Channel: "email", Provider: "sendgrid", Key: "default"}
+	invalid := secrets.Reference{Scope: secrets.ScopeUser, SubjectID: "a/b", Channel: "email", Provider: "sendgrid", Key: "default"}
+	group := secrets.Reference{Scope: secrets.ScopeGroup, SubjectID: secrets.GroupSubject("acme", "ops"), Channel: "email", Provider: "sendgrid", Key: "default"}
+	tenant := secrets.Reference{Scope: secrets.ScopeTenant, SubjectID: "acme", Channel: "email", Provider: "sendgrid", Key: "default"}
+
+	out, err := resolver.Resolve(user, invalid, group, tenant)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if _, ok := out[invalid]; ok {
+		t.Fatalf("expected invalid reference to be skipped")
+	}
+	if got := out[group]; string(got.Data) != "SG.group" {
+		t.Fatalf("expected group secret, got %+v", got)
+	}
+	if got := out[tenant]; string(got.Data) != "SG.tenant" {
+		t.Fatalf("expected tenant secret, got %+v", got)
+	}
+}
+
+func TestForbiddenReadIsUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"errors":["permission denied"]}`)
+	}))
+	defer server.Close()
+
+	resolver, err := New(Config{Address: server.URL, Token: "t", Client: server.Client()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ref := secrets.Reference{Scope: secrets.ScopeSystem, SubjectID: "default", Channel: "chat", Provider: "slack", Key: "default"}
+	if _, err := resolver.Resolve(ref); !errors.Is(err, secrets.ErrUnauthorized) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+}
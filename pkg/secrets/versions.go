@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Metadata keys used to track version lifecycle.
+const (
+	MetadataCreatedAt = "created_at"
+	MetadataExpiresAt = "expires_at"
+)
+
+// VersionInfo describes one stored version of a secret without its value.
+type VersionInfo struct {
+	Version   string
+	CreatedAt time.Time
+	// ExpiresAt is set once a newer version replaced this one; the version
+	// stays readable until then. Zero means the version has not been retired.
+	ExpiresAt time.Time
+}
+
+// Active reports whether the version can still be resolved at now.
+func (v VersionInfo) Active(now time.Time) bool {
+	return v.ExpiresAt.IsZero() || now.Before(v.ExpiresAt)
+}
+
+// VersionedProvider is implemented by providers that keep previous versions
+// around while a secret rotates.
+type VersionedProvider interface {
+	Provider
+	// PutSecret stores value as a new version and retires the versions it
+	// replaces after grace, so in-flight deliveries holding the old value (or
+	// asking for it by Reference.Version) keep working until then. A
+	// non-positive grace retires them immediately.
+	PutSecret(ref Reference, value []byte, grace time.Duration) (string, error)
+	// ListVersions returns every stored version of ref, newest first,
+	// including retired ones. ref.Version is ignored.
+	ListVersions(ref Reference) ([]VersionInfo, error)
+}
+
+func versionInfo(version string, meta map[string]any) VersionInfo {
+	return VersionInfo{
+		Version:   version,
+		CreatedAt: metadataTime(meta, MetadataCreatedAt),
+		ExpiresAt: metadataTime(meta, MetadataExpiresAt),
+	}
+}
+
+// retireMetadata returns a copy of meta marked to expire at expires, keeping
+// an earlier expiry if one was already set.
+func retireMetadata(meta map[string]any, expires time.Time) (map[string]any, bool) {
+	if current := metadataTime(meta, MetadataExpiresAt); !current.IsZero() && !current.After(expires) {
+		return meta, false
+	}
+	out := make(map[string]any, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
+	}
+	out[MetadataExpiresAt] = expires
+	return out, true
+}
+
+func expired(meta map[string]any, now time.Time) bool {
+	expires := metadataTime(meta, MetadataExpiresAt)
+	return !expires.IsZero() && !now.Before(expires)
+}
+
+// metadataTime reads a timestamp stored either as time.Time or, after a JSON
+// round trip through a store, as an RFC 3339 string.
+func metadataTime(meta map[string]any, key string) time.Time {
+	switch v := meta[key].(type) {
+	case time.Time:
+		return v
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(v)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func sortVersions(versions []VersionInfo) {
+	slices.SortFunc(versions, func(a, b VersionInfo) int {
+		return strings.Compare(b.Version, a.Version)
+	})
+}
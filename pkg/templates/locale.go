@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"strings"
+
+	i18n "github.com/goliatone/go-i18n"
+	"golang.org/x/text/language"
+)
+
+// NegotiateLocale picks the locale to render for an Accept-Language header by
+// matching it against the catalog's active locales with
+// LocaleCatalog.MatchAcceptLanguageWithOptions. The catalog code is returned;
+// fallback is returned when the catalog is nil or none of the requested
+// languages is active.
+func NegotiateLocale(catalog *i18n.LocaleCatalog, header, fallback string) string {
+	meta, ok := catalog.MatchAcceptLanguageWithOptions(header, i18n.MatchOptions{Scope: i18n.ScopeActiveOnly})
+	if !ok || !requestedLanguage(header, meta.Code) {
+		return fallback
+	}
+	return meta.Code
+}
+
+// requestedLanguage reports whether the header asks for the language of code.
+// The catalog matcher answers with its first locale when nothing matches, so
+// the match is only trusted when its base language was requested.
+func requestedLanguage(header, code string) bool {
+	matched, err := language.Parse(code)
+	if err != nil {
+		return false
+	}
+	base, _ := matched.Base()
+	tags, quality, err := language.ParseAcceptLanguage(strings.ReplaceAll(header, "_", "-"))
+	if err != nil {
+		return false
+	}
+	for i, tag := range tags {
+		if quality[i] <= 0 {
+			continue
+		}
+		if requested, _ := tag.Base(); requested == base {
+			return true
+		}
+	}
+	return false
+}
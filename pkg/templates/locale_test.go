@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	i18n "github.com/goliatone/go-i18n"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	catalog, err := i18n.NewLocaleCatalogFromLocales("en", []string{"en", "es", "pt-BR", "zh-Hant"})
+	if err != nil {
+		t.Fatalf("catalog: %v", err)
+	}
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "empty header", header: "", want: "en"},
+		{name: "exact match", header: "es", want: "es"},
+		{name: "matches base language", header: "es-MX", want: "es"},
+		{name: "case and underscore insensitive", header: "PT_br", want: "pt-BR"},
+		{name: "quality order", header: "fr;q=0.9, es;q=0.5, pt-BR;q=0.8", want: "pt-BR"},
+		{name: "ties keep header order", header: "es, en", want: "es"},
+		{name: "skips wildcard and q=0", header: "*, es;q=0, en;q=0.1", want: "en"},
+		{name: "drops private use", header: "zh-Hant-x-private", want: "zh-Hant"},
+		{name: "matches regional variant", header: "pt", want: "pt-BR"},
+		{name: "falls back", header: "de-DE, fr", want: "en"},
+		{name: "malformed header", header: "es;;q", want: "en"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NegotiateLocale(catalog, tc.header, "en"); got != tc.want {
+				t.Fatalf("NegotiateLocale(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+	if got := NegotiateLocale(nil, "es", "en"); got != "en" {
+		t.Fatalf("expected fallback without a catalog, got %q", got)
+	}
+}
+
+func TestNegotiateLocaleSkipsInactiveLocales(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "culture.json")
+	data := `{
+		"default_locale": "en",
+		"locales": {
+			"en": {"display_name": "English", "active": true},
+			"es": {"display_name": "Español", "active": true},
+			"fr": {"display_name": "Français", "active": false}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write culture data: %v", err)
+	}
+	cfg, err := i18n.NewConfig(i18n.WithDefaultLocale("en"), i18n.WithCultureData(path))
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if got := NegotiateLocale(cfg.LocaleCatalog(), "fr-CA, fr", "es"); got != "es" {
+		t.Fatalf("expected fallback for an inactive locale, got %q", got)
+	}
+	if got := NegotiateLocale(cfg.LocaleCatalog(), "fr, en;q=0.5", "es"); got != "en" {
+		t.Fatalf("expected next active locale, got %q", got)
+	}
+}
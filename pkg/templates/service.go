@@ -22,6 +22,9 @@ type RenderRequest = internaltemplates.RenderRequest
 // RenderResult wraps the rendered subject/body pair returned by the internal service.
 type RenderResult = internaltemplates.RenderResult
 
+// SchemaError lists the placeholders missing from render data.
+type SchemaError = internaltemplates.SchemaError
+
 // Service exposes CRUD helpers and rendering facilities for notification templates.
 type Service struct {
 	repo          store.NotificationTemplateRepository
@@ -40,6 +43,7 @@ type Dependencies struct {
 	Logger        logger.Logger
 	Translator    i18n.Translator
 	Fallbacks     i18n.FallbackResolver
+	LocaleCatalog *i18n.LocaleCatalog
 	DefaultLocale string
 	CacheTTL      time.Duration
 }
@@ -95,6 +99,7 @@ func New(deps Dependencies) (*Service, error) {
 		deps.Translator,
 		internaltemplates.WithDefaultLocale(defaultLocale),
 		internaltemplates.WithFallbackResolver(deps.Fallbacks),
+		internaltemplates.WithLocaleCatalog(deps.LocaleCatalog),
 	)
 	if err != nil {
 		return nil, err
@@ -194,17 +199,35 @@ func (s *Service) Render(ctx context.Context, req RenderRequest) (RenderResult,
 	return s.engine.Render(ctx, req)
 }
 
-func (s *Service) ensureVariant(ctx context.Context, code, channel, locale string) error {
+// Resolve returns the variant Render would use for locale, walking the same
+// fallback chain (requested locale, fallbacks, default locale, en).
+func (s *Service) Resolve(ctx context.Context, code, channel, locale string) (*domain.NotificationTemplate, error) {
 	for _, candidate := range s.localeCandidates(locale) {
-		if _, err := s.loadTemplate(ctx, code, channel, candidate); err != nil {
+		tpl, err := s.loadTemplate(ctx, code, channel, candidate)
+		if err != nil {
 			if errors.Is(err, store.ErrNotFound) {
 				continue
 			}
-			return err
+			return nil, err
 		}
-		return nil
+		return tpl, nil
 	}
-	return store.ErrNotFound
+	return nil, store.ErrNotFound
+}
+
+// Validate checks data against the schema of the variant Render would use,
+// returning a SchemaError that lists missing required fields.
+func (s *Service) Validate(ctx context.Context, code, channel, locale string, data map[string]any) error {
+	tpl, err := s.Resolve(ctx, code, channel, locale)
+	if err != nil {
+		return err
+	}
+	return internaltemplates.ValidateSchema(tpl.Schema, data)
+}
+
+func (s *Service) ensureVariant(ctx context.Context, code, channel, locale string) error {
+	_, err := s.Resolve(ctx, code, channel, locale)
+	return err
 }
 
 func (s *Service) loadTemplate(ctx context.Context, code, channel, locale string) (*domain.NotificationTemplate, error) {
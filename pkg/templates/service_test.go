@@ -3,6 +3,8 @@ package templates
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -152,6 +154,207 @@ func TestServiceSecureLinkHelper(t *testing.T) {
 	}
 }
 
+func TestServiceDirectionHelpers(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	svc := newTestService(t, repo, &cache.Nop{}, i18n.NewStaticFallbackResolver())
+
+	for _, locale := range []string{"en", "ar"} {
+		seedTemplate(t, repo, domain.NotificationTemplate{
+			Code:    "direction.helper",
+			Channel: "email",
+			Locale:  locale,
+			Subject: `{{ format_direction(locale) }}`,
+			Body:    `{{ bidi_isolate(Name) }}|{{ bidi_isolate(Name, "rtl") }}`,
+			Format:  "text/plain",
+		})
+	}
+
+	cases := map[string]string{"en": "ltr", "ar": "rtl"}
+	for locale, want := range cases {
+		result, err := svc.Render(ctx, RenderRequest{
+			Code:    "direction.helper",
+			Channel: "email",
+			Locale:  locale,
+			Data:    map[string]any{"Name": "Rosa"},
+		})
+		if err != nil {
+			t.Fatalf("render %s: %v", locale, err)
+		}
+		if result.Subject != want {
+			t.Fatalf("expected %s direction for %s, got %q", want, locale, result.Subject)
+		}
+		if result.Body != "\u2068Rosa\u2069|\u2067Rosa\u2069" {
+			t.Fatalf("expected isolated name, got %q", result.Body)
+		}
+	}
+}
+
+func TestServiceDirectionHelperUsesLocaleCatalog(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+
+	culture := filepath.Join(t.TempDir(), "culture.json")
+	data := `{
+  "default_locale": "en",
+  "locales": {
+    "en": {"display_name": "English"},
+    "dv": {"display_name": "Dhivehi", "metadata": {"rtl": false}},
+    "xx": {"display_name": "Custom", "metadata": {"rtl": true}}
+  }
+}`
+	if err := os.WriteFile(culture, []byte(data), 0o600); err != nil {
+		t.Fatalf("write culture data: %v", err)
+	}
+	cfg, err := i18n.NewConfig(i18n.WithDefaultLocale("en"), i18n.WithCultureData(culture))
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	svc, err := New(Dependencies{
+		Repository:    repo,
+		Cache:         &cache.Nop{},
+		Logger:        &logger.Nop{},
+		Translator:    newTestTranslator(t),
+		LocaleCatalog: cfg.LocaleCatalog(),
+		DefaultLocale: "en",
+	})
+	if err != nil {
+		t.Fatalf("New service: %v", err)
+	}
+	seedTemplate(t, repo, domain.NotificationTemplate{
+		Code:    "direction.catalog",
+		Channel: "email",
+		Locale:  "en",
+		Subject: `{{ format_direction(Target) }}`,
+		Body:    "body",
+		Format:  "text/plain",
+	})
+
+	// Catalog metadata wins over the script; locales missing from the
+	// catalog fall back to the CLDR likely script.
+	cases := map[string]string{"xx-YY": "rtl", "dv": "ltr", "fa": "rtl", "az-Arab": "rtl", "en": "ltr"}
+	for target, want := range cases {
+		result, err := svc.Render(ctx, RenderRequest{
+			Code:    "direction.catalog",
+			Channel: "email",
+			Locale:  "en",
+			Data:    map[string]any{"Target": target},
+		})
+		if err != nil {
+			t.Fatalf("render %s: %v", target, err)
+		}
+		if result.Subject != want {
+			t.Fatalf("expected %s direction for %s, got %q", want, target, result.Subject)
+		}
+	}
+}
+
+func TestServiceFormatRelativeHelper(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	resolver := i18n.NewStaticFallbackResolver()
+	resolver.Set("es-mx", "es", "en")
+	svc := newTestService(t, repo, &cache.Nop{}, resolver)
+
+	for _, locale := range []string{"en", "es"} {
+		seedTemplate(t, repo, domain.NotificationTemplate{
+			Code:    "relative.helper",
+			Channel: "email",
+			Locale:  locale,
+			Subject: `{{ format_relative(locale, sent_at) }}`,
+			Body:    `{{ format_relative(locale, due_at) }}`,
+			Format:  "text/plain",
+		})
+	}
+
+	now := time.Now()
+	data := map[string]any{
+		"sent_at": now.Add(-2 * time.Hour),
+		"due_at":  now.Add(3*24*time.Hour + time.Minute).Format(time.RFC3339),
+	}
+	cases := []struct {
+		locale  string
+		subject string
+		body    string
+	}{
+		{locale: "en", subject: "2 hours ago", body: "in 3 days"},
+		{locale: "es-mx", subject: "hace 2 horas", body: "dentro de 3 días"},
+	}
+	for _, tc := range cases {
+		result, err := svc.Render(ctx, RenderRequest{Code: "relative.helper", Channel: "email", Locale: tc.locale, Data: data})
+		if err != nil {
+			t.Fatalf("render %s: %v", tc.locale, err)
+		}
+		if result.Subject != tc.subject || result.Body != tc.body {
+			t.Fatalf("%s: expected %q/%q, got %q/%q", tc.locale, tc.subject, tc.body, result.Subject, result.Body)
+		}
+	}
+}
+
+func TestServiceFormatRelativeRollsOverUnits(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	svc := newTestService(t, repo, &cache.Nop{}, i18n.NewStaticFallbackResolver())
+	seedTemplate(t, repo, domain.NotificationTemplate{
+		Code:    "relative.boundary",
+		Channel: "email",
+		Locale:  "en",
+		Subject: `{{ format_relative(locale, at) }}`,
+		Body:    "-",
+		Format:  "text/plain",
+	})
+
+	cases := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{offset: -59*time.Second - 600*time.Millisecond, want: "1 minute ago"},
+		{offset: -59*time.Minute - 36*time.Second, want: "1 hour ago"},
+		{offset: -58 * time.Minute, want: "58 minutes ago"},
+		{offset: 23*time.Hour + 40*time.Minute, want: "in 1 day"},
+		{offset: 6*24*time.Hour + 15*time.Hour, want: "in 1 week"},
+		{offset: 6 * 24 * time.Hour, want: "in 6 days"},
+	}
+	for _, tc := range cases {
+		data := map[string]any{"at": time.Now().Add(tc.offset)}
+		result, err := svc.Render(ctx, RenderRequest{Code: "relative.boundary", Channel: "email", Locale: "en", Data: data})
+		if err != nil {
+			t.Fatalf("render %s: %v", tc.offset, err)
+		}
+		if result.Subject != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.offset, tc.want, result.Subject)
+		}
+	}
+}
+
+func TestServiceFormatRelativeFallsBackToEnglish(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	resolver := i18n.NewStaticFallbackResolver()
+	resolver.Set("ca", "es")
+	svc := newTestService(t, repo, &cache.Nop{}, resolver)
+	seedTemplate(t, repo, domain.NotificationTemplate{
+		Code:    "relative.fallback",
+		Channel: "email",
+		Locale:  "en",
+		Subject: `{{ format_relative("de-AT", at) }}|{{ format_relative("ca", at) }}`,
+		Body:    `{{ format_relative("ja", at) }}`,
+		Format:  "text/plain",
+	})
+
+	data := map[string]any{"at": time.Now().Add(-2 * time.Hour)}
+	result, err := svc.Render(ctx, RenderRequest{Code: "relative.fallback", Channel: "email", Locale: "en", Data: data})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if result.Subject != "2 hours ago|hace 2 horas" {
+		t.Fatalf("expected English wording for de-AT and the ca fallback chain, got %q", result.Subject)
+	}
+	if result.Body != "2 hours ago" {
+		t.Fatalf("expected English wording for ja, got %q", result.Body)
+	}
+}
+
 func TestServiceSupportsGoCMSPayloads(t *testing.T) {
 	ctx := context.Background()
 	repo := memstore.NewTemplateRepository()
@@ -201,6 +404,66 @@ func TestServiceSupportsGoCMSPayloads(t *testing.T) {
 	}
 }
 
+func TestServiceRendersChatBlocks(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewTemplateRepository()
+	svc := newTestService(t, repo, &cache.Nop{}, i18n.NewStaticFallbackResolver())
+
+	seedTemplate(t, repo, domain.NotificationTemplate{
+		Code:    "deploy.done",
+		Channel: "chat",
+		Locale:  "en",
+		Subject: "Deploy",
+		Body:    "Deployed {{ Service }}",
+		Format:  "text/plain",
+		Metadata: domain.JSONMap{
+			"blocks": []any{
+				map[string]any{
+					"type": "section",
+					"text": map[string]any{"type": "mrkdwn", "text": `*{{ Service }}* by {{ Author }}`},
+				},
+			},
+		},
+	})
+	seedTemplate(t, repo, domain.NotificationTemplate{
+		Code:    "deploy.cms",
+		Channel: "chat",
+		Locale:  "en",
+		Format:  "text/plain",
+		Source: domain.TemplateSource{
+			Type: "gocms-block",
+			Payload: domain.JSONMap{
+				"subject":      "Deploy",
+				"body":         "Deployed {{ Service }}",
+				"slack_blocks": `[{"type":"header","text":{"type":"plain_text","text":"{{ Service }}"}}]`,
+			},
+		},
+	})
+
+	data := map[string]any{"Service": "api", "Author": "Dana"}
+	result, err := svc.Render(ctx, RenderRequest{Code: "deploy.done", Channel: "chat", Locale: "en", Data: data})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	blocks, ok := result.Metadata["blocks"].([]any)
+	if !ok || len(blocks) != 1 {
+		t.Fatalf("expected rendered blocks in metadata, got %#v", result.Metadata["blocks"])
+	}
+	text := blocks[0].(map[string]any)["text"].(map[string]any)["text"]
+	if text != "*api* by Dana" {
+		t.Fatalf("expected interpolated block text, got %v", text)
+	}
+
+	result, err = svc.Render(ctx, RenderRequest{Code: "deploy.cms", Channel: "chat", Locale: "en", Data: data})
+	if err != nil {
+		t.Fatalf("render cms: %v", err)
+	}
+	blocks, ok = result.Metadata["blocks"].([]any)
+	if !ok || len(blocks) != 1 || blocks[0].(map[string]any)["type"] != "header" {
+		t.Fatalf("expected blocks decoded from go-cms payload, got %#v", result.Metadata["blocks"])
+	}
+}
+
 func TestServiceCreateAndUpdateBumpRevision(t *testing.T) {
 	ctx := context.Background()
 	repo := memstore.NewTemplateRepository()